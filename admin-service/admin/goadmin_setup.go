@@ -14,9 +14,10 @@ func SetupGoAdmin(eng *engine.Engine) error {
 	// Initialize admin plugin with table generators
 	// Create a map of generators
 	generators := map[string]table.Generator{
-		"users":  GetUsersTable,
-		"stores": GetStoresTable,
-		"brands": GetBrandsTable,
+		"users":                  GetUsersTable,
+		"stores":                 GetStoresTable,
+		"brands":                 GetBrandsTable,
+		"notification_templates": GetNotificationTemplatesTable,
 	}
 	
 	// Initialize admin plugin
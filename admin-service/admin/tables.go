@@ -324,3 +324,120 @@ func GetBrandsTable(ctx *context.Context) table.Table {
 	return brandsTable
 }
 
+
+// GetNotificationTemplatesTable returns the notification templates table configuration with full CRUD operations
+func GetNotificationTemplatesTable(ctx *context.Context) table.Table {
+	templatesTable := table.NewDefaultTable(ctx)
+
+	info := templatesTable.GetInfo()
+	
+	// Configure table display fields
+	info.AddField("ID", "id", db.Int).
+		FieldSortable().
+		FieldWidth(80)
+	
+	info.AddField("Name", "name", db.Varchar).
+		FieldFilterable(types.FilterType{Operator: types.FilterOperatorLike}).
+		FieldSortable().
+		FieldWidth(180)
+	
+	info.AddField("Type", "type", db.Varchar).
+		FieldFilterable(types.FilterType{Operator: types.FilterOperatorLike}).
+		FieldSortable().
+		FieldWidth(150)
+	
+	info.AddField("Locale", "locale", db.Varchar).
+		FieldFilterable(types.FilterType{Operator: types.FilterOperatorLike}).
+		FieldSortable().
+		FieldWidth(80)
+	
+	info.AddField("Subject", "subject", db.Varchar).
+		FieldWidth(180)
+	
+	info.AddField("Body Template", "body_template", db.Text).
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			body := value.Value
+			if len(body) > 100 {
+				return body[:100] + "..."
+			}
+			return body
+		}).
+		FieldWidth(250)
+	
+	info.AddField("Active", "is_active", db.Tinyint).
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			if value.Value == "1" {
+				return `<span class="label label-success">Active</span>`
+			}
+			return `<span class="label label-danger">Inactive</span>`
+		}).
+		FieldWidth(100)
+	
+	info.AddField("Updated At", "updated_at", db.Datetime).
+		FieldSortable().
+		FieldWidth(150)
+
+	// Configure table settings
+	info.SetTable("notification_templates").
+		SetTitle("Notification Templates").
+		SetDescription("Manage notification templates and their locale variants").
+		SetDefaultPageSize(10).
+		SetFilterFormLayout(form.LayoutTwoCol)
+
+	// Configure form fields
+	formList := templatesTable.GetForm()
+	
+	formList.AddField("ID", "id", db.Int, form.Default).
+		FieldNotAllowAdd().
+		FieldNotAllowEdit()
+	
+	formList.AddField("Name", "name", db.Varchar, form.Text).
+		FieldMust().
+		FieldPlaceholder("Schedule Change (es)").
+		FieldHelpMsg("Unique name of the template variant")
+	
+	formList.AddField("Type", "type", db.Varchar, form.Text).
+		FieldMust().
+		FieldPlaceholder("ScheduleChange").
+		FieldHelpMsg("Notification type the template renders")
+	
+	formList.AddField("Locale", "locale", db.Varchar, form.Text).
+		FieldDefault("en").
+		FieldMust().
+		FieldPlaceholder("en").
+		FieldHelpMsg("Language tag such as en, es or es-mx; lookups fall back es-mx, es, en")
+	
+	formList.AddField("Subject", "subject", db.Varchar, form.Text).
+		FieldPlaceholder("Schedule Change").
+		FieldHelpMsg("Subject line, may contain {{placeholders}}")
+	
+	formList.AddField("Body Template", "body_template", db.Text, form.TextArea).
+		FieldMust().
+		FieldPlaceholder("{{event_title}} has been {{change_type}}.").
+		FieldHelpMsg("Message body with {{placeholders}}; every placeholder must be supplied when sending")
+	
+	formList.AddField("Active", "is_active", db.Tinyint, form.Select).
+		FieldOptions(types.FieldOptions{
+			{Text: "Active", Value: "1"},
+			{Text: "Inactive", Value: "0"},
+		}).
+		FieldDefault("1").
+		FieldMust()
+	
+	formList.AddField("Created At", "created_at", db.Datetime, form.Datetime).
+		FieldNotAllowAdd().
+		FieldNotAllowEdit().
+		FieldNowWhenInsert()
+	
+	formList.AddField("Updated At", "updated_at", db.Datetime, form.Datetime).
+		FieldNotAllowAdd().
+		FieldNotAllowEdit().
+		FieldNowWhenUpdate()
+
+	// Configure form settings
+	formList.SetTable("notification_templates").
+		SetTitle("Notification Template Form").
+		SetDescription("Add or Edit a Notification Template")
+
+	return templatesTable
+}
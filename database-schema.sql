@@ -47,7 +47,7 @@ CREATE TABLE users (
 CREATE TABLE user_roles (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     user_id BIGINT UNSIGNED NOT NULL,
-    role TINYINT UNSIGNED NOT NULL COMMENT '1=SuperAdmin, 2=OrgAdmin, 3=Coach, 4=AssistantCoach, 5=Player, 6=Parent',
+    role TINYINT UNSIGNED NOT NULL COMMENT '1=SuperAdmin, 2=OrgAdmin, 3=Coach, 4=AssistantCoach, 5=Player, 6=Parent, 7=Recruiter',
     organization_id SMALLINT UNSIGNED, -- NULL for SuperAdmin, references organizations
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
@@ -466,12 +466,14 @@ CREATE TABLE notification_templates (
     id SMALLINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL UNIQUE,
     type VARCHAR(100) NOT NULL,
+    locale VARCHAR(20) NOT NULL DEFAULT 'en', -- e.g., "en", "es", "es-mx"; lookups fall back es-mx -> es -> en
     subject VARCHAR(255),
     body_template TEXT NOT NULL, -- Template with placeholders
     is_active BOOLEAN DEFAULT TRUE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_type (type),
+    INDEX idx_type_locale (type, locale),
     INDEX idx_is_active (is_active)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
@@ -486,6 +488,12 @@ CREATE TABLE notification_preferences (
     schedule_change_notifications BOOLEAN DEFAULT TRUE,
     announcement_notifications BOOLEAN DEFAULT TRUE,
     media_notifications BOOLEAN DEFAULT TRUE,
+    time_zone VARCHAR(64) DEFAULT 'UTC', -- IANA zone, e.g. "America/Chicago"
+    quiet_hours_enabled BOOLEAN DEFAULT FALSE,
+    quiet_hours_start VARCHAR(5) DEFAULT '22:00', -- HH:MM in time_zone
+    quiet_hours_end VARCHAR(5) DEFAULT '07:00', -- HH:MM in time_zone, may wrap midnight
+    digest_enabled BOOLEAN DEFAULT FALSE, -- Hold all non-urgent pings for one daily summary
+    digest_time VARCHAR(5) DEFAULT '07:00', -- HH:MM in time_zone
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_user_id (user_id)
@@ -507,6 +515,34 @@ CREATE TABLE notification_devices (
     INDEX idx_is_active (is_active)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Held notifications awaiting a quiet-hours / daily digest summary (BIGINT - one per held notification)
+CREATE TABLE notification_digest_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL, -- References users (auth-service)
+    notification_id BIGINT UNSIGNED NOT NULL, -- References notifications
+    release_at TIMESTAMP NOT NULL, -- When the summary containing this item is due
+    delivered_at TIMESTAMP NULL, -- NULL until included in a summary
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    INDEX idx_release_at (release_at),
+    INDEX idx_delivered_at (delivered_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Notification delivery attempts (BIGINT - one row per channel attempt)
+CREATE TABLE notification_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    notification_id BIGINT UNSIGNED NOT NULL, -- References notifications, 0 if the in-app record failed
+    user_id BIGINT UNSIGNED NOT NULL, -- References users (auth-service)
+    channel TINYINT UNSIGNED NOT NULL COMMENT '1=in_app, 2=push, 3=email, 4=sms',
+    attempt INT NOT NULL, -- 1-based retry attempt
+    status TINYINT UNSIGNED NOT NULL COMMENT '1=sent, 2=failed',
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notification_id (notification_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- ============================================================================
 -- MEDIA SERVICE SCHEMA
 -- ============================================================================
@@ -523,8 +559,10 @@ CREATE TABLE videos (
     storage_url VARCHAR(500) NOT NULL, -- Cloud Storage URL
     thumbnail_url VARCHAR(500),
     duration INT, -- Duration in seconds
+    duration_ms BIGINT, -- Exact duration in milliseconds, bounds video tags
     file_size BIGINT, -- Size in bytes
     file_type VARCHAR(100), -- e.g., "video/mp4"
+    metadata JSON, -- Container, bit rate and streams extracted during processing
     processing_status TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '1=pending, 2=processing, 3=completed, 4=failed',
     uploaded_by BIGINT UNSIGNED NOT NULL, -- User ID
     uploaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -537,6 +575,57 @@ CREATE TABLE videos (
     INDEX idx_deleted_at (deleted_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Resumable (tus) video uploads (BIGINT - one per video)
+CREATE TABLE video_uploads (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    video_id BIGINT UNSIGNED NOT NULL, -- References videos
+    upload_key VARCHAR(100) NOT NULL, -- Storage staging key for in-progress chunks
+    object_key VARCHAR(255) NOT NULL, -- Final storage object key
+    upload_length BIGINT NOT NULL, -- Declared size in bytes
+    upload_offset BIGINT NOT NULL DEFAULT 0, -- Bytes received so far
+    expected_sha256 VARCHAR(64), -- Whole-file checksum declared by the client
+    hash_state BLOB, -- Running SHA-256 state of the bytes received
+    created_by BIGINT UNSIGNED NOT NULL, -- User ID, the only user allowed to resume
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_video_id (video_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Video processing jobs (BIGINT - one per processing run, queued on a Redis stream)
+CREATE TABLE video_processing_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    video_id BIGINT UNSIGNED NOT NULL, -- References videos
+    status TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '1=queued, 2=running, 3=succeeded, 4=retrying, 5=dead',
+    stage VARCHAR(50), -- e.g., "probe", "thumbnail"
+    progress TINYINT UNSIGNED NOT NULL DEFAULT 0, -- 0-100
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    lease_expires_at TIMESTAMP NULL, -- A running job whose lease lapsed can be claimed by another worker
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_video_id (video_id),
+    INDEX idx_status (status),
+    INDEX idx_lease_expires_at (lease_expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- HLS renditions of processed videos (BIGINT - a few per video)
+CREATE TABLE video_renditions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    video_id BIGINT UNSIGNED NOT NULL, -- References videos
+    name VARCHAR(20) NOT NULL, -- e.g., "360p"
+    width INT,
+    height INT,
+    bandwidth INT, -- Peak bits per second advertised in the master playlist
+    playlist_key VARCHAR(255) NOT NULL, -- Storage key of the variant playlist; segments sit beside it
+    target_duration INT,
+    segments JSON, -- [{"uri", "duration"}] in playback order, used to build clip playlists
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_video_id (video_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- Video tags (BIGINT - many tags across all videos)
 CREATE TABLE video_tags (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
@@ -550,7 +639,8 @@ CREATE TABLE video_tags (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
     INDEX idx_video_id (video_id),
-    INDEX idx_player_id (player_id)
+    INDEX idx_player_id (player_id),
+    INDEX idx_video_player (video_id, player_id, start_time) -- Overlap checks per player
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Video permissions (INT - few permissions per video)
@@ -593,8 +683,9 @@ CREATE TABLE game_stats (
     free_throws_made INT DEFAULT 0,
     free_throws_attempted INT DEFAULT 0,
     minutes_played INT, -- Minutes played
+    plus_minus INT, -- Derived from play-by-play; NULL for manually entered lines
     -- Additional flexible stats (JSON for sport-specific)
-    additional_stats JSON,
+    additional_stats JSON, -- Fields of the team sport's stat definition not stored in the columns above
     notes TEXT,
     entered_by BIGINT UNSIGNED NOT NULL, -- User ID
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -604,13 +695,124 @@ CREATE TABLE game_stats (
     UNIQUE KEY unique_event_player (event_id, player_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Game stat edit history (BIGINT - one row per saved change)
+CREATE TABLE game_stat_edits (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    game_stat_id BIGINT UNSIGNED NOT NULL, -- References game_stats
+    event_id BIGINT UNSIGNED NOT NULL, -- References events (event-service)
+    player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
+    action TINYINT UNSIGNED NOT NULL COMMENT '1=created, 2=updated, 3=derived from play-by-play',
+    before_values JSON, -- Stat line before the change; NULL when created
+    after_values JSON, -- Stat line after the change
+    edited_by BIGINT UNSIGNED NOT NULL, -- User ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_game_stat_id (game_stat_id),
+    INDEX idx_event_id (event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Play-by-play log (BIGINT - hundreds of plays per game)
+-- game_stats of a game with plays are derived from its counted plays (not voided, not duplicates)
+CREATE TABLE game_plays (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_id BIGINT UNSIGNED NOT NULL, -- References events (event-service)
+    client_id VARCHAR(64) NOT NULL, -- Generated on the device; makes re-syncs idempotent
+    device_id VARCHAR(100) NOT NULL, -- Device that recorded the play
+    play_type TINYINT UNSIGNED NOT NULL COMMENT '1=2PT made, 2=2PT missed, 3=3PT made, 4=3PT missed, 5=FT made, 6=FT missed, 7=rebound, 8=assist, 9=steal, 10=block, 11=turnover, 12=foul, 13=sub in, 14=sub out, 15=opponent score',
+    player_id BIGINT UNSIGNED, -- User ID (auth-service); NULL for opponent scores
+    period TINYINT UNSIGNED NOT NULL,
+    clock_seconds INT NOT NULL, -- Game clock remaining in the period
+    points TINYINT UNSIGNED NOT NULL DEFAULT 0, -- Opponent scores only
+    voided BOOLEAN NOT NULL DEFAULT FALSE,
+    duplicate_of BIGINT UNSIGNED, -- Play another device recorded for the same action
+    version INT NOT NULL DEFAULT 1, -- Edit count, compared with the device's base_version
+    revision BIGINT UNSIGNED NOT NULL, -- Per-game change counter devices pull from
+    recorded_by BIGINT UNSIGNED NOT NULL, -- User ID
+    client_updated_at DATETIME(3) NOT NULL, -- Device time of the last change; settles conflicting edits
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_event_client (event_id, client_id),
+    INDEX idx_event_revision (event_id, revision),
+    INDEX idx_player_id (player_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Shot chart (BIGINT - one row per charted field goal attempt)
+-- Coordinates are feet on a half court: x from -25 to 25 with the basket at 0, y from the baseline (0) to half court (47)
+CREATE TABLE game_shots (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_id BIGINT UNSIGNED NOT NULL, -- References events (event-service)
+    client_id VARCHAR(64) NOT NULL, -- Generated on the device; re-sending it corrects the shot
+    team_id INT UNSIGNED NOT NULL, -- Event's team, for season charts
+    player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
+    x DECIMAL(4,1) NOT NULL,
+    y DECIMAL(4,1) NOT NULL,
+    shot_type TINYINT UNSIGNED NOT NULL COMMENT '1=jump shot, 2=layup, 3=dunk, 4=hook, 5=tip-in, 6=floater',
+    point_value TINYINT UNSIGNED NOT NULL, -- 2 or 3
+    made BOOLEAN NOT NULL,
+    period TINYINT UNSIGNED NOT NULL,
+    clock_seconds INT NOT NULL, -- Game clock remaining in the period
+    zone VARCHAR(30) NOT NULL, -- Court zone derived from the location and point value
+    recorded_by BIGINT UNSIGNED NOT NULL, -- User ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_event_client (event_id, client_id),
+    INDEX idx_team_id (team_id),
+    INDEX idx_player_id (player_id),
+    INDEX idx_zone (zone)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Season totals per player and team (BIGINT - one row per player per team)
+-- Materialized from game_stats whenever a game of the team is saved; advanced metrics are computed on read
+CREATE TABLE player_season_stats (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
+    team_id INT UNSIGNED NOT NULL, -- References teams (org-service)
+    season_id SMALLINT UNSIGNED NOT NULL, -- Team's season
+    sport VARCHAR(50), -- Team's normalized sport_type
+    games_played INT NOT NULL DEFAULT 0,
+    points INT DEFAULT 0,
+    rebounds INT DEFAULT 0,
+    assists INT DEFAULT 0,
+    steals INT DEFAULT 0,
+    blocks INT DEFAULT 0,
+    turnovers INT DEFAULT 0,
+    fouls INT DEFAULT 0,
+    field_goals_made INT DEFAULT 0,
+    field_goals_attempted INT DEFAULT 0,
+    three_pointers_made INT DEFAULT 0,
+    three_pointers_attempted INT DEFAULT 0,
+    free_throws_made INT DEFAULT 0,
+    free_throws_attempted INT DEFAULT 0,
+    minutes_played INT NOT NULL DEFAULT 0,
+    minutes_games INT NOT NULL DEFAULT 0, -- Games with minutes recorded
+    plus_minus INT NOT NULL DEFAULT 0,
+    plus_minus_games INT NOT NULL DEFAULT 0, -- Games tracked play by play
+    additional_totals JSON, -- Sums of the sport's additional_stats fields
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_player_team (player_id, team_id),
+    INDEX idx_team_id (team_id),
+    INDEX idx_season_id (season_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- CSV import mappings (INT - a few saved per team)
+CREATE TABLE stat_import_profiles (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    team_id INT UNSIGNED NOT NULL, -- References teams (org-service)
+    name VARCHAR(100) NOT NULL,
+    delimiter CHAR(1) NOT NULL DEFAULT ',',
+    columns JSON NOT NULL, -- Stat field -> CSV header, e.g. {"points": "PTS", "field_goals": "FG"}
+    created_by BIGINT UNSIGNED NOT NULL, -- User ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_team_name (team_id, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- Effort metrics (BIGINT - many metrics)
 CREATE TABLE effort_metrics (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     event_id BIGINT UNSIGNED NOT NULL, -- References events (event-service)
     player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
-    hustle_score INT CHECK (hustle_score >= 1 AND hustle_score <= 10),
-    engagement_score INT CHECK (engagement_score >= 1 AND engagement_score <= 10),
+    hustle_score INT NOT NULL CHECK (hustle_score >= 1 AND hustle_score <= 10), -- Range also enforced by the API
+    engagement_score INT NOT NULL CHECK (engagement_score >= 1 AND engagement_score <= 10),
     notes TEXT,
     entered_by BIGINT UNSIGNED NOT NULL, -- User ID
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -631,13 +833,16 @@ CREATE TABLE effort_metrics (
 CREATE TABLE leadership_notes (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
+    team_id INT UNSIGNED NOT NULL, -- References teams (org-service)
     coach_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
-    score INT CHECK (score >= 1 AND score <= 10),
+    score INT CHECK (score >= 1 AND score <= 10) NOT NULL, -- Range also enforced by the API
     comment TEXT NOT NULL,
     event_id BIGINT UNSIGNED, -- References events (event-service), optional
+    visibility TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '1=coach only, 2=player, 3=parents',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_player_id (player_id),
+    INDEX idx_team_id (team_id),
     INDEX idx_coach_id (coach_id),
     INDEX idx_event_id (event_id),
     INDEX idx_created_at (created_at)
@@ -648,10 +853,11 @@ CREATE TABLE buy_in_scores (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     event_id BIGINT UNSIGNED NOT NULL, -- References events (event-service)
     player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
-    score INT CHECK (score >= 1 AND score <= 10) NOT NULL,
+    score INT CHECK (score >= 1 AND score <= 10) NOT NULL, -- Range also enforced by the API
     notes TEXT,
     entered_by BIGINT UNSIGNED NOT NULL, -- User ID
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_event_id (event_id),
     INDEX idx_player_id (player_id),
     UNIQUE KEY unique_event_player (event_id, player_id)
@@ -662,13 +868,48 @@ CREATE TABLE academic_entries (
     id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
     term VARCHAR(100) NOT NULL, -- e.g., "Fall 2025", "Semester 1"
+    term_end_date DATE, -- Orders terms, latest first
     gpa DECIMAL(3, 2), -- GPA score
+    failing_grades INT, -- Number of failing grades in the term
     notes TEXT,
     entered_by BIGINT UNSIGNED NOT NULL, -- User ID
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_player_id (player_id),
-    INDEX idx_term (term)
+    INDEX idx_term (term),
+    INDEX idx_term_end_date (term_end_date),
+    UNIQUE KEY unique_player_term (player_id, term)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Academic eligibility rule sets (SMALLINT - one per organization)
+-- Ineligible below min_gpa or above max_failing_grades; on watch below
+-- watch_gpa or at watch_failing_grades or more. NULL thresholds are not checked.
+CREATE TABLE eligibility_rules (
+    id SMALLINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    organization_id SMALLINT UNSIGNED NOT NULL, -- References organizations (org-service)
+    min_gpa DECIMAL(3, 2),
+    watch_gpa DECIMAL(3, 2),
+    max_failing_grades INT,
+    watch_failing_grades INT,
+    updated_by BIGINT UNSIGNED NOT NULL, -- User ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_organization (organization_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Player eligibility (INT - one row per player per organization)
+CREATE TABLE player_eligibility (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    player_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
+    organization_id SMALLINT UNSIGNED NOT NULL, -- References organizations (org-service)
+    status TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '1=eligible, 2=watch, 3=ineligible',
+    term VARCHAR(100), -- Term of the academic entry evaluated
+    reasons JSON, -- Rules the entry failed
+    evaluated_at TIMESTAMP NOT NULL,
+    changed_at TIMESTAMP NOT NULL, -- Last status change
+    INDEX idx_organization_id (organization_id),
+    INDEX idx_status (status),
+    UNIQUE KEY unique_player_org (player_id, organization_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Life goals (INT - few goals per player)
@@ -680,12 +921,49 @@ CREATE TABLE life_goals (
     status TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '1=active, 2=completed, 3=paused',
     target_date DATE,
     completed_at TIMESTAMP NULL,
+    reminder_sent_at TIMESTAMP NULL, -- Last approaching-target-date reminder
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_player_id (player_id),
     INDEX idx_status (status)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Goal milestones (INT - few milestones per goal)
+CREATE TABLE goal_milestones (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    goal_id INT UNSIGNED NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES life_goals(id) ON DELETE CASCADE,
+    INDEX idx_goal_id (goal_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Goal progress check-ins (INT - periodic per goal)
+CREATE TABLE goal_check_ins (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    goal_id INT UNSIGNED NOT NULL,
+    progress INT CHECK (progress >= 0 AND progress <= 100) NOT NULL, -- Percentage, also enforced by the API
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES life_goals(id) ON DELETE CASCADE,
+    INDEX idx_goal_id (goal_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Coach comments on goals (INT - few per goal)
+CREATE TABLE goal_comments (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    goal_id INT UNSIGNED NOT NULL,
+    coach_id BIGINT UNSIGNED NOT NULL, -- User ID (auth-service)
+    comment TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES life_goals(id) ON DELETE CASCADE,
+    INDEX idx_goal_id (goal_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- ============================================================================
 -- RECRUITING SERVICE SCHEMA
 -- ============================================================================
@@ -696,47 +974,112 @@ CREATE TABLE life_goals (
 CREATE TABLE recruiting_profiles (
     id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     player_id BIGINT UNSIGNED NOT NULL UNIQUE, -- User ID (auth-service)
+    sport VARCHAR(50) NOT NULL, -- Normalized sport; selects the additional_info schema
     bio TEXT,
-    height VARCHAR(50), -- e.g., "6'2\""
+    height_inches INT,
     weight INT, -- Weight in pounds
     graduation_year INT,
     position VARCHAR(100),
-    additional_info JSON, -- Flexible additional fields
+    date_of_birth DATE, -- Parents may edit the profile while the player is under 18
+    additional_info JSON, -- Measurables of the sport's schema (e.g. wingspan_inches, vertical_leap_inches)
     profile_photo_url VARCHAR(500),
+    embed_season_stats BOOLEAN NOT NULL DEFAULT TRUE,
     is_active BOOLEAN DEFAULT TRUE,
+    updated_by BIGINT UNSIGNED, -- User ID of the last editor (player or parent)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_player_id (player_id),
+    INDEX idx_graduation_year (graduation_year),
+    INDEX idx_position (position),
     INDEX idx_is_active (is_active)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Profile highlights (INT - a few clips per profile)
+CREATE TABLE profile_highlights (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    profile_id INT UNSIGNED NOT NULL,
+    video_tag_id BIGINT UNSIGNED NOT NULL, -- Tagged clip of the player
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (profile_id) REFERENCES recruiting_profiles(id) ON DELETE CASCADE,
+    FOREIGN KEY (video_tag_id) REFERENCES video_tags(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_profile_tag (profile_id, video_tag_id),
+    INDEX idx_video_tag_id (video_tag_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- Profile shares (INT - few shares per profile)
 CREATE TABLE profile_shares (
     id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     profile_id INT UNSIGNED NOT NULL, -- References recruiting_profiles
-    share_token VARCHAR(255) NOT NULL UNIQUE,
+    share_token VARCHAR(255) NOT NULL UNIQUE, -- 32 random bytes, base64url
+    label VARCHAR(255), -- e.g. the college the link was sent to
     expires_at TIMESTAMP NULL, -- NULL = never expires
     is_active BOOLEAN DEFAULT TRUE,
-    view_count INT DEFAULT 0,
+    view_count INT DEFAULT 0, -- Deduplicated views, see profile_views
     created_by BIGINT UNSIGNED NOT NULL, -- User ID
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (profile_id) REFERENCES recruiting_profiles(id) ON DELETE CASCADE,
-    INDEX idx_share_token (share_token),
     INDEX idx_profile_id (profile_id),
     INDEX idx_is_active (is_active)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Profile views (BIGINT - one row per counted view of a share)
+CREATE TABLE profile_views (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    share_id INT UNSIGNED NOT NULL,
+    visitor_hash CHAR(64) NOT NULL, -- sha256 of share, IP and user agent
+    referrer VARCHAR(500),
+    referrer_host VARCHAR(255), -- Lowercased, without www.
+    viewed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (share_id) REFERENCES profile_shares(id) ON DELETE CASCADE,
+    INDEX idx_share_visitor (share_id, visitor_hash),
+    INDEX idx_referrer_host (referrer_host),
+    INDEX idx_viewed_at (viewed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Recruiter accounts (INT - one per user with the Recruiter role)
+CREATE TABLE recruiter_accounts (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL UNIQUE, -- References users (auth-service)
+    account_type TINYINT UNSIGNED NOT NULL COMMENT '2=coach, 3=college, 4=recruiter', -- Matches profile_permissions.permission_type
+    organization_name VARCHAR(255) NOT NULL, -- College, club or scouting service
+    title VARCHAR(255), -- e.g., "Recruiting Coordinator"
+    division VARCHAR(100), -- e.g., "NCAA D1"
+    website VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_account_type (account_type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- Profile permissions (INT - few permissions per profile)
+-- Access requests of recruiter accounts and grants by the player, or by a parent while the player is a minor
 CREATE TABLE profile_permissions (
     id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     profile_id INT UNSIGNED NOT NULL,
     granted_to BIGINT UNSIGNED, -- User ID, NULL for public
     permission_type TINYINT UNSIGNED NOT NULL COMMENT '1=public, 2=coach, 3=college, 4=recruiter',
-    granted_by BIGINT UNSIGNED NOT NULL, -- User ID
+    status TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '1=pending, 2=approved, 3=denied, 4=revoked',
+    message VARCHAR(500), -- Note of the access request
+    granted_by BIGINT UNSIGNED, -- User ID who decided, NULL while pending
+    decided_at TIMESTAMP NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (profile_id) REFERENCES recruiting_profiles(id) ON DELETE CASCADE,
-    INDEX idx_profile_id (profile_id),
-    INDEX idx_granted_to (granted_to)
+    UNIQUE KEY unique_profile_grantee (profile_id, granted_to),
+    INDEX idx_granted_to (granted_to),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Recruiter profile views (BIGINT - one row per profile opened by a recruiter account)
+CREATE TABLE recruiter_profile_views (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    profile_id INT UNSIGNED NOT NULL,
+    recruiter_id BIGINT UNSIGNED NOT NULL, -- User ID
+    viewed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (profile_id) REFERENCES recruiting_profiles(id) ON DELETE CASCADE,
+    INDEX idx_profile_viewed (profile_id, viewed_at),
+    INDEX idx_recruiter_id (recruiter_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- ============================================================================
@@ -749,7 +1092,14 @@ INSERT INTO notification_templates (name, type, subject, body_template, is_activ
 ('Game Day Published', 'GameDayPublished', 'Game Day Instructions', 'Game day plan for {{event_title}} has been published. Reporting time: {{reporting_time}}', TRUE),
 ('New Announcement', 'Announcement', 'New Team Announcement', '{{announcement_title}}\n\n{{announcement_body}}', TRUE),
 ('Schedule Change', 'ScheduleChange', 'Schedule Change', '{{event_title}} has been {{change_type}}. New time: {{new_time}}', TRUE),
-('New Video Tagged', 'VideoTagged', 'New Video Tagged', 'You have been tagged in a new video: {{video_title}}', TRUE);
+('New Video Tagged', 'VideoTagged', 'New Video Tagged', 'You have been tagged in a new video: {{video_title}}', TRUE),
+('Daily Digest', 'Digest', 'Your Team Updates', 'You have {{count}} new updates:\n{{items}}', TRUE),
+('Eligibility Change', 'EligibilityChange', 'Academic Eligibility Update', '{{player_name}} is now {{status}}: {{reason}}', TRUE),
+('Goal Reminder', 'GoalReminder', 'Goal Target Approaching', 'Your goal "{{goal_title}}" is due on {{target_date}}. Take a moment to check in on your progress.', TRUE),
+('Goal Ready To Complete', 'GoalReadyToComplete', 'All Milestones Done', 'You finished every milestone of "{{goal_title}}". Mark the goal complete!', TRUE),
+('Goal Comment', 'GoalComment', 'New Comment on Your Goal', '{{coach_name}} commented on "{{goal_title}}": {{comment}}', TRUE),
+('Profile Access Requested', 'ProfileAccessRequested', 'Recruiting Profile Access Request', '{{recruiter_name}} ({{organization_name}}) asked to see {{player_name}}''s recruiting profile. {{message}}', TRUE),
+('Profile Access Decided', 'ProfileAccessDecided', 'Recruiting Profile Access', 'Your request to see {{player_name}}''s recruiting profile was {{decision}}.', TRUE);
 
 -- ============================================================================
 -- COMMENTS AND NOTES
@@ -762,16 +1112,20 @@ INSERT INTO notification_templates (name, type, subject, body_template, is_activ
 --    - users, user_roles, refresh_tokens
 --    - team_members, roster_list_items, membership_history
 --    - events, announcements, announcement_recipients
---    - notifications, videos, video_tags
+--    - notifications, notification_deliveries, notification_digest_items, videos, video_tags
+--    - video_uploads, video_processing_jobs, video_renditions
 --    - game_stats, effort_metrics, leadership_notes, buy_in_scores
+--    - game_stat_edits, game_plays, game_shots, player_season_stats, profile_views
+--    - recruiter_profile_views
 -- 
 -- 2. INT UNSIGNED: Medium-scale tables (thousands to hundreds of thousands)
 --    - teams, coach_profiles
 --    - roster_lists, password_resets, game_day_plans
 --    - event_announcements, event_rsvps
 --    - announcement_attachments, notification_preferences, notification_devices
---    - video_permissions, academic_entries, life_goals
---    - recruiting_profiles, profile_shares, profile_permissions
+--    - video_permissions, stat_import_profiles, academic_entries, player_eligibility, life_goals
+--    - goal_milestones, goal_check_ins, goal_comments
+--    - recruiting_profiles, profile_highlights, profile_shares, profile_permissions, recruiter_accounts
 --    - player_signup_requests, player_invitations, parent_invitations
 --    - parent_players (parent-player relationships)
 -- 
@@ -779,11 +1133,12 @@ INSERT INTO notification_templates (name, type, subject, body_template, is_activ
 --    - organizations (max 65,535 organizations)
 --    - seasons (limited over time)
 --    - notification_templates (very few templates)
+--    - eligibility_rules (one per organization)
 -- 
 -- 4. TINYINT UNSIGNED: ENUM replacements (status fields, types, roles)
 --    All ENUM columns converted to TINYINT with COMMENT showing mapping
 --    - users.status: 1=active, 2=inactive, 3=suspended, 4=pending (for player signup requests)
---    - user_roles.role: 1=SuperAdmin, 2=OrgAdmin, 3=Coach, 4=AssistantCoach, 5=Player, 6=Parent
+--    - user_roles.role: 1=SuperAdmin, 2=OrgAdmin, 3=Coach, 4=AssistantCoach, 5=Player, 6=Parent, 7=Recruiter
 --    - organizations.type: 1=college, 2=club, 3=academy
 --    - organizations.status: 1=active, 2=inactive
 --    - seasons.status: 1=upcoming, 2=active, 3=completed
@@ -798,10 +1153,14 @@ INSERT INTO notification_templates (name, type, subject, body_template, is_activ
 --    - event_rsvps.status: 1=attending, 2=not_attending, 3=maybe
 --    - announcements.audience_type: 1=team, 2=group, 3=individual
 --    - notification_devices.platform: 1=ios, 2=android, 3=web
+--    - notification_deliveries.channel: 1=in_app, 2=push, 3=email, 4=sms
+--    - notification_deliveries.status: 1=sent, 2=failed
 --    - videos.processing_status: 1=pending, 2=processing, 3=completed, 4=failed
 --    - video_permissions.permission_type: 1=public, 2=team, 3=player, 4=parent
 --    - life_goals.status: 1=active, 2=completed, 3=paused
 --    - profile_permissions.permission_type: 1=public, 2=coach, 3=college, 4=recruiter
+--    - profile_permissions.status: 1=pending, 2=approved, 3=denied, 4=revoked
+--    - recruiter_accounts.account_type: 2=coach, 3=college, 4=recruiter
 --    - player_signup_requests.status: 1=pending, 2=approved, 3=rejected
 --    - player_invitations.status: 1=pending, 2=used, 3=expired
 --    - parent_invitations.status: 1=pending, 2=used, 3=expired
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/services"
+	"mobile-api-service/storage"
+)
+
+// video-worker runs the video processing pool on its own, for deployments that
+// set VIDEO_WORKERS_IN_PROCESS=false on the API servers
+func main() {
+	// Load configuration
+	config.LoadConfig()
+
+	// Connect to MySQL
+	database.ConnectMySQL()
+
+	// Connect to Redis
+	database.ConnectRedis()
+
+	// Select media storage
+	storage.Init()
+
+	// Stop taking jobs on SIGINT/SIGTERM; jobs in flight are finished first
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := services.RunVideoWorkers(ctx, services.VideoWorkerConcurrency()); err != nil {
+		log.Fatal("Video workers failed:", err)
+	}
+	log.Println("Video workers stopped")
+}
@@ -23,6 +23,43 @@ type Config struct {
 	RedisDB       string
 
 	JWTSecret string
+
+	FCMBaseURL         string
+	FCMProjectID       string
+	FCMCredentialsFile string
+
+	APNsBaseURL string
+	APNsKeyPath string
+	APNsKeyID   string
+	APNsTeamID  string
+	APNsTopic   string
+
+	DeviceStaleDays string
+
+	ScheduleChangeCoalesceSeconds string
+
+	GoalReminderDays string
+
+	ProfileViewDedupeMinutes string
+
+	StorageBackend       string
+	StorageLocalDir      string
+	StoragePublicBaseURL string
+	S3Endpoint           string
+	S3AccessKey          string
+	S3SecretKey          string
+	S3Bucket             string
+	S3UseSSL             string
+	VideoMaxUploadBytes  string
+	MediaSigningSecret   string
+	PublicAPIBaseURL     string
+	PlaybackURLTTL       string
+
+	FFmpegPath             string
+	FFprobePath            string
+	VideoWorkersInProcess  string
+	VideoWorkerConcurrency string
+	VideoJobMaxAttempts    string
 }
 
 var AppConfig *Config
@@ -49,6 +86,43 @@ func LoadConfig() {
 		RedisDB:       getEnv("REDIS_DB", "0"),
 
 		JWTSecret: getEnv("JWT_SECRET", "your_jwt_secret_key_here"),
+
+		FCMBaseURL:         getEnv("FCM_BASE_URL", "https://fcm.googleapis.com"),
+		FCMProjectID:       getEnv("FCM_PROJECT_ID", ""),
+		FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+
+		APNsBaseURL: getEnv("APNS_BASE_URL", "https://api.push.apple.com"),
+		APNsKeyPath: getEnv("APNS_KEY_PATH", ""),
+		APNsKeyID:   getEnv("APNS_KEY_ID", ""),
+		APNsTeamID:  getEnv("APNS_TEAM_ID", ""),
+		APNsTopic:   getEnv("APNS_TOPIC", ""),
+
+		DeviceStaleDays: getEnv("DEVICE_STALE_DAYS", "90"),
+
+		ScheduleChangeCoalesceSeconds: getEnv("SCHEDULE_CHANGE_COALESCE_SECONDS", "120"),
+
+		GoalReminderDays: getEnv("GOAL_REMINDER_DAYS", "7"),
+
+		ProfileViewDedupeMinutes: getEnv("PROFILE_VIEW_DEDUPE_MINUTES", "30"),
+
+		StorageBackend:       getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalDir:      getEnv("STORAGE_LOCAL_DIR", "./data/media"),
+		StoragePublicBaseURL: getEnv("STORAGE_PUBLIC_BASE_URL", "http://localhost:8081/media"),
+		S3Endpoint:           getEnv("S3_ENDPOINT", ""),
+		S3AccessKey:          getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:          getEnv("S3_SECRET_KEY", ""),
+		S3Bucket:             getEnv("S3_BUCKET", ""),
+		S3UseSSL:             getEnv("S3_USE_SSL", "true"),
+		VideoMaxUploadBytes:  getEnv("VIDEO_MAX_UPLOAD_BYTES", "4294967296"),
+		MediaSigningSecret:   getEnv("MEDIA_SIGNING_SECRET", ""),
+		PublicAPIBaseURL:     getEnv("PUBLIC_API_BASE_URL", "http://localhost:8081"),
+		PlaybackURLTTL:       getEnv("PLAYBACK_URL_TTL_MINUTES", "15"),
+
+		FFmpegPath:             getEnv("FFMPEG_PATH", "ffmpeg"),
+		FFprobePath:            getEnv("FFPROBE_PATH", "ffprobe"),
+		VideoWorkersInProcess:  getEnv("VIDEO_WORKERS_IN_PROCESS", "true"),
+		VideoWorkerConcurrency: getEnv("VIDEO_WORKER_CONCURRENCY", "2"),
+		VideoJobMaxAttempts:    getEnv("VIDEO_JOB_MAX_ATTEMPTS", "3"),
 	}
 }
 
@@ -35,10 +35,46 @@ func ConnectMySQL() {
 	log.Println("MySQL database connected successfully")
 
 	// Auto migrate tables
+	// Teams, rosters and events are owned by other services and only read here
 	err = DB.AutoMigrate(
 		&models.User{},
 		&models.Store{},
 		&models.Brand{},
+		&models.Notification{},
+		&models.NotificationTemplate{},
+		&models.NotificationPreference{},
+		&models.NotificationDelivery{},
+		&models.NotificationDevice{},
+		&models.NotificationDigestItem{},
+		&models.Video{},
+		&models.VideoUpload{},
+		&models.VideoProcessingJob{},
+		&models.VideoTag{},
+		&models.VideoPermission{},
+		&models.VideoRendition{},
+		&models.GameStat{},
+		&models.GameStatEdit{},
+		&models.GamePlay{},
+		&models.GameShot{},
+		&models.StatImportProfile{},
+		&models.EffortMetric{},
+		&models.BuyInScore{},
+		&models.LeadershipNote{},
+		&models.AcademicEntry{},
+		&models.EligibilityRule{},
+		&models.PlayerEligibility{},
+		&models.LifeGoal{},
+		&models.GoalMilestone{},
+		&models.GoalCheckIn{},
+		&models.GoalComment{},
+		&models.RecruitingProfile{},
+		&models.ProfileHighlight{},
+		&models.ProfileShare{},
+		&models.ProfileView{},
+		&models.RecruiterAccount{},
+		&models.ProfilePermission{},
+		&models.RecruiterProfileView{},
+		&models.PlayerSeasonStat{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
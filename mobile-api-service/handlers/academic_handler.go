@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadAcademicEntry loads the entry of the route and checks the caller manages its player
+func loadAcademicEntry(c *gin.Context) (*models.AcademicEntry, bool) {
+	entryID, err := strconv.ParseUint(c.Param("entryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entry ID"})
+		return nil, false
+	}
+
+	entry, err := services.GetAcademicEntry(c.Request.Context(), uint(entryID))
+	switch {
+	case errors.Is(err, services.ErrAcademicEntryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Academic entry not found"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch academic entry"})
+		return nil, false
+	}
+	if !canManagePlayer(c, entry.PlayerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player's coaches and OrgAdmins can change academic entries"})
+		return nil, false
+	}
+	return entry, true
+}
+
+func academicEntryError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrAcademicTermExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save academic entry"})
+}
+
+// API for Frontend - Get Academic Entries of a Player (player, parents, coaches, OrgAdmins); latest term first
+func GetAcademicEntries(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this player's academics"})
+		return
+	}
+
+	entries, err := services.ListAcademicEntries(c.Request.Context(), uint(playerID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch academic entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// API for Frontend - Create Academic Entry (coaches, OrgAdmins); term, term end date, GPA and failing grades, then re-evaluates eligibility
+func CreateAcademicEntry(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canManagePlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player's coaches and OrgAdmins can enter academics"})
+		return
+	}
+
+	var req models.CreateAcademicEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	entry, err := services.CreateAcademicEntry(ctx, uint(playerID), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		academicEntryError(c, err)
+		return
+	}
+	eligibility, _ := services.GetPlayerEligibility(ctx, uint(playerID))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":     true,
+		"data":        entry,
+		"eligibility": eligibility,
+	})
+}
+
+// API for Frontend - Update Academic Entry (coaches, OrgAdmins)
+func UpdateAcademicEntry(c *gin.Context) {
+	entry, ok := loadAcademicEntry(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAcademicEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := services.UpdateAcademicEntry(ctx, entry, &req); err != nil {
+		academicEntryError(c, err)
+		return
+	}
+	eligibility, _ := services.GetPlayerEligibility(ctx, entry.PlayerID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        entry,
+		"eligibility": eligibility,
+	})
+}
+
+// API for Frontend - Delete Academic Entry (coaches, OrgAdmins)
+func DeleteAcademicEntry(c *gin.Context) {
+	entry, ok := loadAcademicEntry(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteAcademicEntry(c.Request.Context(), entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete academic entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Academic entry deleted",
+	})
+}
+
+// API for Frontend - Get Eligibility of a Player (player, parents, coaches, OrgAdmins); status per organization
+func GetPlayerEligibility(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this player's academics"})
+		return
+	}
+
+	statuses, err := services.GetPlayerEligibility(c.Request.Context(), uint(playerID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch eligibility"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    statuses,
+	})
+}
+
+// API for Frontend - Get Eligibility of a Team (coaches, OrgAdmins); roster statuses, ineligible players first
+func GetTeamEligibility(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can view team eligibility"})
+		return
+	}
+
+	var team models.Team
+	if err := database.DB.Select("id", "organization_id").First(&team, teamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	lines, err := services.GetTeamEligibility(c.Request.Context(), &team)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch eligibility"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    lines,
+	})
+}
+
+// API for Frontend - Get Eligibility Rules of an Organization (OrgAdmins); null when none are configured
+func GetEligibilityRule(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+	if !canManageOrganization(c, uint(orgID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only OrgAdmins can manage eligibility rules"})
+		return
+	}
+
+	rule, err := services.GetEligibilityRule(c.Request.Context(), uint(orgID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch eligibility rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// API for Frontend - Set Eligibility Rules of an Organization (OrgAdmins); replaces the rule set and re-evaluates its players
+func SaveEligibilityRule(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return
+	}
+	if !canManageOrganization(c, uint(orgID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only OrgAdmins can manage eligibility rules"})
+		return
+	}
+
+	var req models.EligibilityRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := services.SaveEligibilityRule(c.Request.Context(), uint(orgID), middleware.CurrentUserID(c), &req)
+	if errors.Is(err, services.ErrEligibilityRuleOrder) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save eligibility rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// canManageTeam allows the team's coaches, admins of the team's organization and super admins
+func canManageTeam(c *gin.Context, teamID uint) bool {
+	if middleware.HasRole(c, models.RoleSuperAdmin) {
+		return true
+	}
+	userID := middleware.CurrentUserID(c)
+	if services.IsTeamCoach(c.Request.Context(), userID, teamID) {
+		return true
+	}
+	if !middleware.HasRole(c, models.RoleOrgAdmin) {
+		return false
+	}
+
+	var team models.Team
+	if err := database.DB.Select("id", "organization_id").First(&team, teamID).Error; err != nil {
+		return false
+	}
+	return middleware.InOrganization(c, team.OrganizationID)
+}
+
+// canViewTeam allows anyone who can manage the team, its active roster and
+// the approved parents of its players
+func canViewTeam(c *gin.Context, teamID uint) bool {
+	if canManageTeam(c, teamID) {
+		return true
+	}
+	ctx := c.Request.Context()
+	userID := middleware.CurrentUserID(c)
+	return services.IsTeamMember(ctx, userID, teamID) || services.IsParentOnTeam(ctx, userID, teamID)
+}
+
+// canViewPlayer allows the player, their approved parents and anyone who
+// can manage the player
+func canViewPlayer(c *gin.Context, playerID uint) bool {
+	userID := middleware.CurrentUserID(c)
+	if userID == playerID || services.IsParentOf(c.Request.Context(), userID, playerID) {
+		return true
+	}
+	return canManagePlayer(c, playerID)
+}
+
+// canManagePlayer allows the player's coaches, admins of an organization they
+// play in and super admins
+func canManagePlayer(c *gin.Context, playerID uint) bool {
+	if middleware.HasRole(c, models.RoleSuperAdmin) {
+		return true
+	}
+	ctx := c.Request.Context()
+	if services.IsCoachOfPlayer(ctx, middleware.CurrentUserID(c), playerID) {
+		return true
+	}
+	if !middleware.HasRole(c, models.RoleOrgAdmin) {
+		return false
+	}
+
+	orgIDs, err := services.PlayerOrganizationIDs(ctx, playerID)
+	if err != nil {
+		return false
+	}
+	for _, orgID := range orgIDs {
+		if middleware.InOrganization(c, orgID) {
+			return true
+		}
+	}
+	return false
+}
+
+// canManageOrganization allows the organization's admins and super admins
+func canManageOrganization(c *gin.Context, organizationID uint) bool {
+	if middleware.HasRole(c, models.RoleSuperAdmin) {
+		return true
+	}
+	return middleware.HasRole(c, models.RoleOrgAdmin) && middleware.InOrganization(c, organizationID)
+}
+
+// videoViewer describes the caller for the video permission checks
+func videoViewer(c *gin.Context) services.VideoViewer {
+	viewer := services.VideoViewer{
+		UserID:     middleware.CurrentUserID(c),
+		SuperAdmin: middleware.HasRole(c, models.RoleSuperAdmin),
+	}
+	if middleware.HasRole(c, models.RoleOrgAdmin) {
+		viewer.AdminOrgIDs = middleware.OrganizationIDs(c)
+	}
+	return viewer
+}
+
+// canViewVideo applies video_permissions to the caller
+func canViewVideo(c *gin.Context, videoID uint) bool {
+	return services.CanViewVideo(c.Request.Context(), videoViewer(c), videoID)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadEffortEvent loads the event of an effort route and checks the caller coaches its team
+func loadEffortEvent(c *gin.Context) (*models.Event, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, false
+	}
+
+	event, err := services.LoadEffortEvent(c.Request.Context(), uint(id))
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can rate effort"})
+		return nil, false
+	}
+	return event, true
+}
+
+// effortSaveError answers the errors of the bulk score entries
+func effortSaveError(c *gin.Context, err error) {
+	var invalid *services.GameStatValidationError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "some scores are invalid",
+			"rows":  invalid.Rows,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save scores"})
+}
+
+// effortTrendFilter reads ?player_id, ?sessions (default 20) and ?window (default 3)
+func effortTrendFilter(c *gin.Context) services.EffortTrendFilter {
+	playerID, _ := strconv.ParseUint(c.Query("player_id"), 10, 64)
+	sessions, _ := strconv.Atoi(c.DefaultQuery("sessions", "20"))
+	window, _ := strconv.Atoi(c.DefaultQuery("window", "3"))
+	if sessions < 1 || sessions > 100 {
+		sessions = 20
+	}
+	if window < 2 || window > 10 {
+		window = 3
+	}
+	return services.EffortTrendFilter{PlayerID: uint(playerID), Sessions: sessions, Window: window}
+}
+
+// API for Frontend - Enter Effort Scores (coaches); hustle and engagement (1-10) for the whole roster after an event
+func SaveEffortMetrics(c *gin.Context) {
+	event, ok := loadEffortEvent(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkEffortRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := services.SaveEffortMetrics(ctx, event, middleware.CurrentUserID(c), req.Scores); err != nil {
+		effortSaveError(c, err)
+		return
+	}
+
+	sheet, err := services.GetEventEffort(ctx, event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scores saved but could not be listed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sheet,
+	})
+}
+
+// API for Frontend - Enter Buy-in Scores (coaches); 1-10 for the whole roster after an event
+func SaveBuyInScores(c *gin.Context) {
+	event, ok := loadEffortEvent(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkBuyInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := services.SaveBuyInScores(ctx, event, middleware.CurrentUserID(c), req.Scores); err != nil {
+		effortSaveError(c, err)
+		return
+	}
+
+	sheet, err := services.GetEventEffort(ctx, event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scores saved but could not be listed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sheet,
+	})
+}
+
+// API for Frontend - Get Effort Sheet of an Event (coaches); every roster player with their scores
+func GetEventEffort(c *gin.Context) {
+	event, ok := loadEffortEvent(c)
+	if !ok {
+		return
+	}
+
+	sheet, err := services.GetEventEffort(c.Request.Context(), event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sheet,
+	})
+}
+
+// API for Frontend - Get Effort Trends of a Team (coaches); rolling averages, streaks and drop alerts per player
+func GetEffortTrends(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can view effort trends"})
+		return
+	}
+
+	trends, err := services.GetEffortTrends(c.Request.Context(), uint(teamID), effortTrendFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch effort trends"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    trends,
+	})
+}
+
+// API for Frontend - Get Players to Check In With (coaches); players whose effort or buy-in dropped
+func GetCheckInList(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can view effort trends"})
+		return
+	}
+
+	list, err := services.GetCheckInList(c.Request.Context(), uint(teamID), effortTrendFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch check-in list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    list,
+	})
+}
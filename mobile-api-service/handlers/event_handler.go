@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Update Event (coaches); schedule changes notify the team
+func UpdateEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req models.UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var event models.Event
+	if err := database.DB.First(&event, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can edit events"})
+		return
+	}
+
+	old := event
+	if req.Title != nil {
+		event.Title = *req.Title
+	}
+	if req.Description != nil {
+		event.Description = *req.Description
+	}
+	if req.StartTime != nil {
+		event.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		event.EndTime = *req.EndTime
+	}
+	if req.Location != nil {
+		event.Location = *req.Location
+	}
+	if req.Opponent != nil {
+		event.Opponent = *req.Opponent
+	}
+	if req.IsHomeGame != nil {
+		event.IsHomeGame = *req.IsHomeGame
+	}
+	if req.Status != nil {
+		event.Status = *req.Status
+	}
+
+	if !event.EndTime.After(event.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be after start_time"})
+		return
+	}
+
+	if err := database.DB.Save(&event).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update event"})
+		return
+	}
+
+	services.OnEventUpdated(c.Request.Context(), &old, &event)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
+
+// API for Frontend - Cancel Event (coaches)
+func CancelEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var event models.Event
+	if err := database.DB.First(&event, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can cancel events"})
+		return
+	}
+
+	old := event
+	event.Status = models.EventStatusCancelled
+	if err := database.DB.Model(&event).Update("status", event.Status).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel event"})
+		return
+	}
+
+	services.OnEventUpdated(c.Request.Context(), &old, &event)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
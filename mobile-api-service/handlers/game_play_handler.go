@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Sync Play-by-Play (coaches); pushes queued plays and pulls changes since the last sync
+func SyncGamePlays(c *gin.Context) {
+	event, ok := loadStatsEvent(c, true)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can track games"})
+		return
+	}
+
+	var req models.SyncGamePlaysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := services.SyncGamePlays(c.Request.Context(), event, middleware.CurrentUserID(c), &req)
+	switch {
+	case errors.Is(err, services.ErrPlayByPlayBasketballOnly), errors.Is(err, services.ErrSportNotSupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sync plays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// API for Frontend - Get Play-by-Play of a Game (team members and parents)
+func GetGamePlayList(c *gin.Context) {
+	event, ok := loadStatsEvent(c, false)
+	if !ok {
+		return
+	}
+	if !canViewTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this team's stats"})
+		return
+	}
+
+	since, _ := strconv.ParseUint(c.DefaultQuery("since", "0"), 10, 64)
+	plays, revision, err := services.ListGamePlays(c.Request.Context(), event, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     plays,
+		"revision": revision,
+	})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadStatsEvent parses the event ID and loads the game, writing the error
+// response when it fails. Cancelled games are only rejected for writes.
+func loadStatsEvent(c *gin.Context, forWrite bool) (*models.Event, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, false
+	}
+
+	event, err := services.LoadGameEvent(c.Request.Context(), uint(id))
+	switch {
+	case err == nil, errors.Is(err, services.ErrEventCancelled) && !forWrite:
+		return event, true
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	default:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	}
+	return nil, false
+}
+
+// API for Frontend - Enter Box Score Stats (coaches); upserts the lines of the whole roster at once
+func SaveGameStats(c *gin.Context) {
+	event, ok := loadStatsEvent(c, true)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can enter stats"})
+		return
+	}
+
+	var req models.BulkGameStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	written, err := services.SaveGameStats(ctx, event, middleware.CurrentUserID(c), req.Stats)
+	var invalid *services.GameStatValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"rows":  invalid.Rows,
+		})
+		return
+	case errors.Is(err, services.ErrStatsFromPlayLog):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save stats"})
+		return
+	}
+
+	box, err := services.BuildBoxScore(ctx, event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stats saved but the box score could not be built"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    box,
+		"written": written,
+	})
+}
+
+// API for Frontend - Get Box Score of a Game (team members and parents)
+func GetGameBoxScore(c *gin.Context) {
+	event, ok := loadStatsEvent(c, false)
+	if !ok {
+		return
+	}
+	if !canViewTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this team's stats"})
+		return
+	}
+
+	box, err := services.BuildBoxScore(c.Request.Context(), event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build box score"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    box,
+	})
+}
+
+// API for Frontend - Get Stat Edit History of a Game (coaches)
+func GetGameStatHistory(c *gin.Context) {
+	event, ok := loadStatsEvent(c, false)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can view the stat history"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	edits, total, err := services.ListGameStatEdits(c.Request.Context(), event.ID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stat history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    edits,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
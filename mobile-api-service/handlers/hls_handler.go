@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const hlsContentType = "application/vnd.apple.mpegurl"
+
+// verifyPlaylistSignature checks the signed URL a playlist was requested with
+func verifyPlaylistSignature(c *gin.Context) (time.Time, bool) {
+	expires, ok := services.VerifyAPIPath(c.Request.URL.Path, c.Query("expires"), c.Query("signature"))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Link is invalid or has expired"})
+	}
+	return expires, ok
+}
+
+func writePlaylist(c *gin.Context, playlist string, err error) {
+	switch {
+	case err == nil:
+		c.Header("Cache-Control", "private, no-store")
+		c.Data(http.StatusOK, hlsContentType, []byte(playlist))
+	case errors.Is(err, services.ErrNoRenditions), errors.Is(err, services.ErrRenditionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build playlist"})
+	}
+}
+
+func loadPlaylistTag(c *gin.Context) (*models.VideoTag, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return nil, false
+	}
+	var tag models.VideoTag
+	if err := database.DB.First(&tag, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return nil, false
+	}
+	return &tag, true
+}
+
+// API for Players - Get Video Master Playlist (signed URL from hls_url)
+func GetVideoMasterPlaylist(c *gin.Context) {
+	expires, ok := verifyPlaylistSignature(c)
+	if !ok {
+		return
+	}
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+	playlist, err := services.RenderVideoMaster(c.Request.Context(), videoID, expires)
+	writePlaylist(c, playlist, err)
+}
+
+// API for Players - Get Video Variant Playlist with signed segment URLs
+func GetVideoVariantPlaylist(c *gin.Context) {
+	if _, ok := verifyPlaylistSignature(c); !ok {
+		return
+	}
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+	playlist, err := services.RenderVideoVariant(c.Request.Context(), videoID, c.Param("rendition"))
+	writePlaylist(c, playlist, err)
+}
+
+// API for Players - Get Clip Master Playlist of a video tag
+func GetClipMasterPlaylist(c *gin.Context) {
+	expires, ok := verifyPlaylistSignature(c)
+	if !ok {
+		return
+	}
+	tag, ok := loadPlaylistTag(c)
+	if !ok {
+		return
+	}
+	playlist, err := services.RenderClipMaster(c.Request.Context(), tag, expires)
+	writePlaylist(c, playlist, err)
+}
+
+// API for Players - Get Clip Variant Playlist holding only the tag's segments
+func GetClipVariantPlaylist(c *gin.Context) {
+	if _, ok := verifyPlaylistSignature(c); !ok {
+		return
+	}
+	tag, ok := loadPlaylistTag(c)
+	if !ok {
+		return
+	}
+	playlist, err := services.RenderClipVariant(c.Request.Context(), tag, c.Param("rendition"))
+	writePlaylist(c, playlist, err)
+}
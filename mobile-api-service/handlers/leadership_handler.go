@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// noteViewer checks the caller may look at the player and describes what
+// notes they may see: every note on the player's teams they manage, and
+// otherwise only the notes shared with them as the player or a parent
+func noteViewer(c *gin.Context, playerID uint) (services.NoteViewer, bool) {
+	if !canViewPlayer(c, playerID) {
+		return services.NoteViewer{}, false
+	}
+	ctx := c.Request.Context()
+	userID := middleware.CurrentUserID(c)
+	viewer := services.NoteViewer{
+		UserID: userID,
+		Parent: services.IsParentOf(ctx, userID, playerID),
+	}
+	teamIDs, err := services.PlayerTeamIDs(ctx, playerID)
+	if err != nil {
+		return viewer, true
+	}
+	for _, teamID := range teamIDs {
+		if canManageTeam(c, teamID) {
+			viewer.ManagedTeamIDs = append(viewer.ManagedTeamIDs, teamID)
+		}
+	}
+	return viewer, true
+}
+
+// loadLeadershipNote loads the note of the route and checks the caller coaches its team
+func loadLeadershipNote(c *gin.Context) (*models.LeadershipNote, bool) {
+	noteID, err := strconv.ParseUint(c.Param("noteId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return nil, false
+	}
+
+	note, err := services.GetLeadershipNote(c.Request.Context(), uint(noteID))
+	switch {
+	case errors.Is(err, services.ErrNoteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Note not found"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch note"})
+		return nil, false
+	}
+	if !canManageTeam(c, note.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can change leadership notes"})
+		return nil, false
+	}
+	return note, true
+}
+
+// API for Frontend - Create Leadership Note (coaches); scored comment about a player, coach-only unless shared with the player or parents
+func CreateLeadershipNote(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	var req models.CreateLeadershipNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !canManageTeam(c, req.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can write leadership notes"})
+		return
+	}
+
+	note, err := services.CreateLeadershipNote(c.Request.Context(), uint(playerID), middleware.CurrentUserID(c), &req)
+	switch {
+	case errors.Is(err, services.ErrPlayerNotOnTeam), errors.Is(err, services.ErrEventNotOnTeam):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save note"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// API for Frontend - Get Leadership Notes of a Player (coaches, player, parents); players and parents only get the notes shared with them
+func GetLeadershipNotes(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	viewer, ok := noteViewer(c, uint(playerID))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this player"})
+		return
+	}
+
+	teamID, _ := strconv.ParseUint(c.Query("team_id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	notes, total, err := services.ListLeadershipNotes(c.Request.Context(), uint(playerID), viewer, uint(teamID), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    notes,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// API for Frontend - Update Leadership Note (coaches); score, comment or visibility
+func UpdateLeadershipNote(c *gin.Context) {
+	note, ok := loadLeadershipNote(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateLeadershipNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.UpdateLeadershipNote(c.Request.Context(), note, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// API for Frontend - Delete Leadership Note (coaches)
+func DeleteLeadershipNote(c *gin.Context) {
+	note, ok := loadLeadershipNote(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteLeadershipNote(c.Request.Context(), note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Note deleted",
+	})
+}
+
+// API for Frontend - Get Player Timeline (coaches, player, parents); leadership notes, effort scores and attendance, newest first
+// ?from and ?to are dates (YYYY-MM-DD), defaulting to the last 90 days
+func GetPlayerTimeline(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	viewer, ok := noteViewer(c, uint(playerID))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this player"})
+		return
+	}
+
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+
+	teamID, _ := strconv.ParseUint(c.Query("team_id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	entries, total, err := services.GetPlayerTimeline(c.Request.Context(), uint(playerID), viewer, services.TimelineFilter{
+		TeamID: uint(teamID),
+		From:   from,
+		To:     to,
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build timeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadLifeGoal loads the goal of the route and checks the caller may view its player
+func loadLifeGoal(c *gin.Context) (*models.LifeGoal, bool) {
+	goalID, err := strconv.ParseUint(c.Param("goalId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return nil, false
+	}
+
+	goal, err := services.GetLifeGoal(c.Request.Context(), uint(goalID))
+	switch {
+	case errors.Is(err, services.ErrGoalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal"})
+		return nil, false
+	}
+	if !canViewPlayer(c, goal.PlayerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this goal"})
+		return nil, false
+	}
+	return goal, true
+}
+
+// loadOwnLifeGoal loads the goal of the route for its player, the only one who edits it
+func loadOwnLifeGoal(c *gin.Context) (*models.LifeGoal, bool) {
+	goal, ok := loadLifeGoal(c)
+	if !ok {
+		return nil, false
+	}
+	if goal.PlayerID != middleware.CurrentUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player can change their goals"})
+		return nil, false
+	}
+	return goal, true
+}
+
+func milestoneID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("milestoneId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func milestoneError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrMilestoneNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save milestone"})
+}
+
+// API for Frontend - Get Life Goals of a Player (player, parents, coaches); ?status=1|2|3
+func GetLifeGoals(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this player's goals"})
+		return
+	}
+
+	status, _ := strconv.ParseUint(c.Query("status"), 10, 8)
+	goals, err := services.ListLifeGoals(c.Request.Context(), uint(playerID), uint8(status))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    goals,
+	})
+}
+
+// API for Frontend - Create Life Goal (player); title, description, target date (YYYY-MM-DD) and milestones
+func CreateLifeGoal(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if uint(playerID) != middleware.CurrentUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Players can only create their own goals"})
+		return
+	}
+
+	var req models.CreateLifeGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	goal, err := services.CreateLifeGoal(c.Request.Context(), uint(playerID), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    goal,
+	})
+}
+
+// API for Frontend - Get Life Goal (player, parents, coaches); milestones, check-ins and coach comments
+func GetLifeGoal(c *gin.Context) {
+	goal, ok := loadLifeGoal(c)
+	if !ok {
+		return
+	}
+
+	detail, err := services.GetLifeGoalDetail(c.Request.Context(), goal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    detail,
+	})
+}
+
+// API for Frontend - Update Life Goal (player); status 2 marks it complete and sets completed_at
+func UpdateLifeGoal(c *gin.Context) {
+	goal, ok := loadOwnLifeGoal(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateLifeGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := services.UpdateLifeGoal(c.Request.Context(), goal, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// API for Frontend - Delete Life Goal (player)
+func DeleteLifeGoal(c *gin.Context) {
+	goal, ok := loadOwnLifeGoal(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteLifeGoal(c.Request.Context(), goal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Goal deleted",
+	})
+}
+
+// API for Frontend - Add Goal Milestone (player)
+func AddGoalMilestone(c *gin.Context) {
+	goal, ok := loadOwnLifeGoal(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := services.AddGoalMilestone(c.Request.Context(), goal, &req)
+	if err != nil {
+		milestoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// API for Frontend - Update Goal Milestone (player); completing the last open milestone sets ready_to_complete and prompts the player
+func UpdateGoalMilestone(c *gin.Context) {
+	goal, ok := loadOwnLifeGoal(c)
+	if !ok {
+		return
+	}
+	id, ok := milestoneID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := services.UpdateGoalMilestone(c.Request.Context(), goal, id, &req)
+	if err != nil {
+		milestoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// API for Frontend - Delete Goal Milestone (player)
+func DeleteGoalMilestone(c *gin.Context) {
+	goal, ok := loadOwnLifeGoal(c)
+	if !ok {
+		return
+	}
+	id, ok := milestoneID(c)
+	if !ok {
+		return
+	}
+
+	updated, err := services.DeleteGoalMilestone(c.Request.Context(), goal, id)
+	if err != nil {
+		milestoneError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// API for Frontend - Add Goal Check-in (player); progress percentage and comment
+func AddGoalCheckIn(c *gin.Context) {
+	goal, ok := loadOwnLifeGoal(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateGoalCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	checkIn, err := services.AddGoalCheckIn(c.Request.Context(), goal, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save check-in"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    checkIn,
+	})
+}
+
+// API for Frontend - Get Goal Check-ins (player, parents, coaches)
+func GetGoalCheckIns(c *gin.Context) {
+	goal, ok := loadLifeGoal(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	checkIns, total, err := services.ListGoalCheckIns(c.Request.Context(), goal.ID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch check-ins"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    checkIns,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// API for Frontend - Comment on Life Goal (coaches); the player is notified
+func AddGoalComment(c *gin.Context) {
+	goal, ok := loadLifeGoal(c)
+	if !ok {
+		return
+	}
+	if !canManagePlayer(c, goal.PlayerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player's coaches can comment on goals"})
+		return
+	}
+
+	var req models.CreateGoalCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := services.AddGoalComment(c.Request.Context(), goal, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save comment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    comment,
+	})
+}
+
+// API for Frontend - Delete Goal Comment (comment author)
+func DeleteGoalComment(c *gin.Context) {
+	goal, ok := loadLifeGoal(c)
+	if !ok {
+		return
+	}
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	err = services.DeleteGoalComment(c.Request.Context(), goal, uint(commentID), middleware.CurrentUserID(c))
+	if errors.Is(err, services.ErrGoalCommentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Comment deleted",
+	})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inAdminOrganizations reports whether every user belongs to one of the
+// caller's organizations; super admins reach everyone
+func inAdminOrganizations(c *gin.Context, userIDs []uint) (bool, error) {
+	if middleware.HasRole(c, models.RoleSuperAdmin) {
+		return true, nil
+	}
+	members, err := services.OrganizationUserIDs(c.Request.Context(), middleware.OrganizationIDs(c), userIDs)
+	if err != nil {
+		return false, err
+	}
+	found := make(map[uint]bool, len(members))
+	for _, id := range members {
+		found[id] = true
+	}
+	for _, id := range userIDs {
+		if !found[id] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// API for Admin - Send a Notification to a set of users over their enabled channels (SuperAdmins, OrgAdmins to members of their organizations)
+func SendNotification(c *gin.Context) {
+	var intent models.NotificationIntent
+	if err := c.ShouldBindJSON(&intent); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	allowed, err := inAdminOrganizations(c, intent.UserIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check recipients"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "OrgAdmins can only notify members of their organizations"})
+		return
+	}
+
+	// Validate the template up front so a bad request fails here instead of in the background
+	if _, err := services.RenderNotification(c.Request.Context(), intent.Type, intent.Locale, intent.Variables); err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	services.NotificationDispatcher.DispatchAsync(&intent)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Notification queued for delivery",
+	})
+}
+
+// API for Admin - Get Delivery Attempts of a Notification (SuperAdmins, OrgAdmins for members of their organizations)
+func GetNotificationDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	var notification models.Notification
+	if err := database.DB.Select("id", "user_id").First(&notification, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	allowed, err := inAdminOrganizations(c, []uint{notification.UserID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	var deliveries []models.NotificationDelivery
+	if err := database.DB.Where("notification_id = ?", id).Order("id").Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deliveries,
+	})
+}
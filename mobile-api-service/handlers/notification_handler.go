@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Get Notification Inbox (cursor paginated, newest first)
+func GetNotificationList(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	cursor, _ := strconv.ParseUint(c.Query("cursor"), 10, 64)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, nextCursor, err := services.ListNotifications(c.Request.Context(), userID, uint(cursor), limit, unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	pagination := gin.H{
+		"limit":    limit,
+		"has_more": nextCursor != 0,
+	}
+	if nextCursor != 0 {
+		pagination["next_cursor"] = nextCursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       notifications,
+		"pagination": pagination,
+	})
+}
+
+// API for Frontend - Get Unread Notification Count (app badge)
+func GetUnreadNotificationCount(c *gin.Context) {
+	count, err := services.UnreadCount(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unread count"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"unread_count": count,
+		},
+	})
+}
+
+// API for Frontend - Mark Notification As Read
+func MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = services.MarkNotificationRead(c.Request.Context(), middleware.CurrentUserID(c), uint(id))
+	if errors.Is(err, services.ErrNotificationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// API for Frontend - Mark All Notifications As Read
+func MarkAllNotificationsRead(c *gin.Context) {
+	updated, err := services.MarkAllNotificationsRead(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"updated": updated,
+		},
+	})
+}
+
+// API for Frontend - Delete Notification
+func DeleteNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = services.DeleteNotification(c.Request.Context(), middleware.CurrentUserID(c), uint(id))
+	if errors.Is(err, services.ErrNotificationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Get Notification Preferences of the current user
+func GetNotificationPreferences(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+
+	prefs, err := services.LoadNotificationPreferences(c.Request.Context(), []uint{userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prefs[userID],
+	})
+}
+
+// API for Frontend - Update Notification Preferences of the current user
+func UpdateNotificationPreferences(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+
+	var req models.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefs, err := services.LoadNotificationPreferences(c.Request.Context(), []uint{userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences"})
+		return
+	}
+	pref := prefs[userID]
+
+	apply := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	apply(&pref.PushEnabled, req.PushEnabled)
+	apply(&pref.EmailEnabled, req.EmailEnabled)
+	apply(&pref.SMSEnabled, req.SMSEnabled)
+	apply(&pref.GameDayNotifications, req.GameDayNotifications)
+	apply(&pref.ScheduleChangeNotifications, req.ScheduleChangeNotifications)
+	apply(&pref.AnnouncementNotifications, req.AnnouncementNotifications)
+	apply(&pref.MediaNotifications, req.MediaNotifications)
+	apply(&pref.QuietHoursEnabled, req.QuietHoursEnabled)
+	apply(&pref.DigestEnabled, req.DigestEnabled)
+
+	applyString := func(dst *string, src *string) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	applyString(&pref.TimeZone, req.TimeZone)
+	applyString(&pref.QuietHoursStart, req.QuietHoursStart)
+	applyString(&pref.QuietHoursEnd, req.QuietHoursEnd)
+	applyString(&pref.DigestTime, req.DigestTime)
+
+	if err := services.ValidateQuietHours(&pref); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Save(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pref,
+	})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Admin - Get Notification Template List
+func GetNotificationTemplateList(c *gin.Context) {
+	var templates []models.NotificationTemplate
+
+	query := database.DB.Order("type, locale")
+	if templateType := c.Query("type"); templateType != "" {
+		query = query.Where("type = ?", templateType)
+	}
+	if err := query.Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification templates"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(templates))
+	for i := range templates {
+		data = append(data, gin.H{
+			"template":  templates[i],
+			"variables": services.TemplateVariables(&templates[i]),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// API for Admin - Preview a Notification Template with sample variables
+func PreviewNotificationTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req models.PreviewNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var template models.NotificationTemplate
+	if err := database.DB.First(&template, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification template not found"})
+		return
+	}
+
+	rendered, err := services.RenderTemplate(&template, req.Variables)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rendered,
+	})
+}
+
+// API for Admin - Preview the template a user would receive for a type and locale
+func PreviewNotificationTemplateByType(c *gin.Context) {
+	var req struct {
+		Type      string            `json:"type" binding:"required"`
+		Locale    string            `json:"locale"`
+		Variables map[string]string `json:"variables"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rendered, err := services.RenderNotification(c.Request.Context(), req.Type, req.Locale, req.Variables)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"rendered":       rendered,
+			"fallback_chain": services.LocaleFallbackChain(req.Locale),
+		},
+	})
+}
+
+func respondTemplateError(c *gin.Context, err error) {
+	var varErr *services.TemplateVariableError
+	switch {
+	case errors.As(err, &varErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     varErr.Error(),
+			"missing":   varErr.Missing,
+			"extra":     varErr.Extra,
+			"malformed": varErr.Malformed,
+		})
+	case errors.Is(err, services.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification template not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render notification template"})
+	}
+}
@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// canApproveProfileAccess allows the player to decide on access to their
+// profile, and only their approved parents while the player is a minor. The
+// player cannot set their own date of birth, so they cannot make themselves
+// an adult to take over.
+func canApproveProfileAccess(c *gin.Context, profile *models.RecruitingProfile) bool {
+	userID := middleware.CurrentUserID(c)
+	if services.IsMinor(profile) {
+		return services.IsParentOf(c.Request.Context(), userID, profile.PlayerID)
+	}
+	return userID == profile.PlayerID
+}
+
+// loadDecidableProfilePermission loads the permission of the route with its
+// profile and checks the caller may decide on it
+func loadDecidableProfilePermission(c *gin.Context) (*models.ProfilePermission, *models.RecruitingProfile, bool) {
+	permissionID, err := strconv.ParseUint(c.Param("permissionId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid permission ID"})
+		return nil, nil, false
+	}
+
+	ctx := c.Request.Context()
+	permission, err := services.GetProfilePermission(ctx, uint(permissionID))
+	switch {
+	case errors.Is(err, services.ErrProfilePermissionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Permission not found"})
+		return nil, nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch permission"})
+		return nil, nil, false
+	}
+	profile, err := services.GetRecruitingProfileByID(ctx, permission.ProfileID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return nil, nil, false
+	}
+	if !canApproveProfileAccess(c, profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player, or their parents while they are a minor, can decide on profile access"})
+		return nil, nil, false
+	}
+	return permission, profile, true
+}
+
+// loadRecruiterAccount returns the caller's recruiter account
+func loadRecruiterAccount(c *gin.Context) (*models.RecruiterAccount, bool) {
+	account, err := services.GetRecruiterAccount(c.Request.Context(), middleware.CurrentUserID(c))
+	switch {
+	case errors.Is(err, services.ErrRecruiterAccountRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiter account"})
+		return nil, false
+	}
+	return account, true
+}
+
+// pageParams reads page and limit, defaulting to 50 rows per page
+func pageParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	return page, limit
+}
+
+// API for Frontend - Get Recruiter Account (recruiters)
+func GetRecruiterAccount(c *gin.Context) {
+	account, ok := loadRecruiterAccount(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    account,
+	})
+}
+
+// API for Frontend - Save Recruiter Account (recruiters); account_type 2=coach, 3=college, 4=recruiter is the access their requests ask for
+func SaveRecruiterAccount(c *gin.Context) {
+	var req models.SaveRecruiterAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := services.SaveRecruiterAccount(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save recruiter account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    account,
+	})
+}
+
+// API for Frontend - Search Recruiter Directory (recruiters); profiles shared publicly or granted to the recruiter, filtered by sport, graduation_year, position, height_min and height_max (inches)
+func SearchRecruiterDirectory(c *gin.Context) {
+	account, ok := loadRecruiterAccount(c)
+	if !ok {
+		return
+	}
+
+	filter := models.DirectoryFilter{
+		Sport:    c.Query("sport"),
+		Position: c.Query("position"),
+	}
+	for param, target := range map[string]*int{
+		"graduation_year": &filter.GraduationYear,
+		"height_min":      &filter.MinHeight,
+		"height_max":      &filter.MaxHeight,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return
+		}
+		*target = parsed
+	}
+	if filter.MinHeight > 0 && filter.MaxHeight > 0 && filter.MinHeight > filter.MaxHeight {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "height_min must not exceed height_max"})
+		return
+	}
+
+	page, limit := pageParams(c)
+	profiles, total, err := services.SearchRecruiterDirectory(c.Request.Context(), account.UserID, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search profiles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    profiles,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// API for Frontend - Get Recruiter Profile View (recruiters with access); every view is logged for the player
+func GetRecruiterProfile(c *gin.Context) {
+	account, ok := loadRecruiterAccount(c)
+	if !ok {
+		return
+	}
+	profileID, err := strconv.ParseUint(c.Param("profileId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	profile, err := services.GetVisibleRecruitingProfile(ctx, account.UserID, uint(profileID))
+	switch {
+	case errors.Is(err, services.ErrRecruitingProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return
+	}
+
+	public, err := services.GetPublicRecruitingProfile(ctx, profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return
+	}
+	if err := services.RecordRecruiterView(ctx, profile.ID, account.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log profile view"})
+		return
+	}
+	services.SignClipURLs(ctx, public.Highlights)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    public,
+	})
+}
+
+// API for Frontend - Request Profile Access (recruiters); the player approves, or a parent while the player is a minor
+func RequestProfileAccess(c *gin.Context) {
+	account, ok := loadRecruiterAccount(c)
+	if !ok {
+		return
+	}
+	profileID, err := strconv.ParseUint(c.Param("profileId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+
+	var req models.RequestProfileAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	profile, err := services.GetRecruitingProfileByID(ctx, uint(profileID))
+	switch {
+	case errors.Is(err, services.ErrRecruitingProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return
+	}
+
+	permission, err := services.RequestProfileAccess(ctx, profile, account, &req)
+	switch {
+	case errors.Is(err, services.ErrRecruitingProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	case errors.Is(err, services.ErrProfileAccessExists), errors.Is(err, services.ErrNoProfileApprover):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request access"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    permission,
+	})
+}
+
+// API for Frontend - Get Recruiter Access Requests (recruiters); with status 1=pending, 2=approved, 3=denied, 4=revoked
+func GetRecruiterAccessRequests(c *gin.Context) {
+	permissions, err := services.ListRecruiterAccessRequests(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch access requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    permissions,
+	})
+}
+
+// API for Frontend - Withdraw Profile Access (recruiters); removes their own request or grant
+func WithdrawProfileAccess(c *gin.Context) {
+	permissionID, err := strconv.ParseUint(c.Param("permissionId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid permission ID"})
+		return
+	}
+
+	err = services.WithdrawProfileAccess(c.Request.Context(), middleware.CurrentUserID(c), uint(permissionID))
+	switch {
+	case errors.Is(err, services.ErrProfilePermissionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access request not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw access request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Access request withdrawn",
+	})
+}
+
+// API for Frontend - Get Profile Permissions (player, parents of minors); access requests and grants, pending first
+func GetProfilePermissions(c *gin.Context) {
+	_, profile, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	}
+
+	permissions, err := services.ListProfilePermissions(c.Request.Context(), profile.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch permissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    permissions,
+	})
+}
+
+// API for Frontend - Grant Profile Permission (player, or parents while the player is a minor); permission_type 1=public needs no user_id, 2-4 grant a recruiter account of that type
+func GrantProfilePermission(c *gin.Context) {
+	_, profile, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	}
+	if !canApproveProfileAccess(c, profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player, or their parents while they are a minor, can grant profile access"})
+		return
+	}
+
+	var req models.GrantProfilePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	permission, err := services.GrantProfilePermission(c.Request.Context(), profile, middleware.CurrentUserID(c), &req)
+	switch {
+	case errors.Is(err, services.ErrInvalidProfileGrant):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant access"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    permission,
+	})
+}
+
+// API for Frontend - Decide Profile Access (player, or parents while the player is a minor); approves or denies a pending request
+func DecideProfileAccess(c *gin.Context) {
+	permission, profile, ok := loadDecidableProfilePermission(c)
+	if !ok {
+		return
+	}
+
+	var req models.DecideProfileAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := services.DecideProfileAccess(c.Request.Context(), permission, profile, middleware.CurrentUserID(c), req.Decision == "approve")
+	switch {
+	case errors.Is(err, services.ErrProfileAccessNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decide access request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// API for Frontend - Revoke Profile Permission (player, or parents while the player is a minor); takes effect on the recruiter's next request
+func RevokeProfilePermission(c *gin.Context) {
+	permission, _, ok := loadDecidableProfilePermission(c)
+	if !ok {
+		return
+	}
+
+	err := services.RevokeProfilePermission(c.Request.Context(), permission, middleware.CurrentUserID(c))
+	switch {
+	case errors.Is(err, services.ErrProfilePermissionNotActive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke access"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Access revoked",
+	})
+}
+
+// API for Frontend - Get Recruiter Profile Views (player, parents of minors); every recruiter view with who viewed, plus views per recruiter
+func GetRecruiterProfileViews(c *gin.Context) {
+	_, profile, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	}
+
+	page, limit := pageParams(c)
+	ctx := c.Request.Context()
+	views, total, err := services.ListRecruiterViews(ctx, profile.ID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch views"})
+		return
+	}
+	recruiters, err := services.RecruiterViewSummaries(ctx, profile.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch views"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"views":      views,
+			"recruiters": recruiters,
+		},
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadEditableProfileShare loads the share of the route with its profile and
+// checks the caller may edit the profile
+func loadEditableProfileShare(c *gin.Context) (*models.ProfileShare, *models.RecruitingProfile, bool) {
+	shareID, err := strconv.ParseUint(c.Param("shareId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share ID"})
+		return nil, nil, false
+	}
+
+	ctx := c.Request.Context()
+	share, err := services.GetProfileShare(ctx, uint(shareID))
+	switch {
+	case errors.Is(err, services.ErrProfileShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share not found"})
+		return nil, nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch share"})
+		return nil, nil, false
+	}
+	profile, err := services.GetRecruitingProfileByID(ctx, share.ProfileID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return nil, nil, false
+	}
+	if !canEditRecruitingProfile(c, profile.PlayerID, profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player, or their parents while they are a minor, can manage profile shares"})
+		return nil, nil, false
+	}
+	return share, profile, true
+}
+
+// API for Frontend - Create Profile Share (adult player, parents of minors); returns an unguessable public link with optional expires_at
+func CreateProfileShare(c *gin.Context) {
+	_, profile, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	}
+	// A share link is a public grant, so only who approves access may create one
+	if !canApproveProfileAccess(c, profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player, or their parents while they are a minor, can publish profile shares"})
+		return
+	}
+
+	var req models.CreateProfileShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	share, err := services.CreateProfileShare(c.Request.Context(), profile, middleware.CurrentUserID(c), &req)
+	switch {
+	case errors.Is(err, services.ErrShareExpiryPast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    share,
+	})
+}
+
+// API for Frontend - Get Profile Shares (player, parents of minors); with view counts
+func GetProfileShares(c *gin.Context) {
+	_, profile, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	}
+
+	shares, err := services.ListProfileShares(c.Request.Context(), profile.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shares"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shares,
+	})
+}
+
+// API for Frontend - Update Profile Share (player, parents of minors); is_active=false revokes the link immediately, re-activating or extending it needs a parent while the player is a minor
+func UpdateProfileShare(c *gin.Context) {
+	share, profile, ok := loadEditableProfileShare(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateProfileShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// A live link is a public grant, so only who approves access may publish it
+	publishes := (req.IsActive != nil && *req.IsActive && !share.IsActive) || req.ExpiresAt != nil || req.NeverExpires
+	if publishes && !canApproveProfileAccess(c, profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player, or their parents while they are a minor, can publish profile shares"})
+		return
+	}
+
+	updated, err := services.UpdateProfileShare(c.Request.Context(), share, &req)
+	switch {
+	case errors.Is(err, services.ErrShareExpiryPast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update share"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// API for Frontend - Delete Profile Share (player, parents of minors); removes the link and its view log
+func DeleteProfileShare(c *gin.Context) {
+	share, _, ok := loadEditableProfileShare(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteProfileShare(c.Request.Context(), share.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete share"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Share deleted",
+	})
+}
+
+// API for Frontend - Get Profile Share Views (player, parents of minors); each counted view with its time and referrer, plus views per referring site
+func GetProfileShareViews(c *gin.Context) {
+	share, _, ok := loadEditableProfileShare(c)
+	if !ok {
+		return
+	}
+
+	page, limit := pageParams(c)
+	ctx := c.Request.Context()
+	views, total, err := services.ListProfileViews(ctx, share.ID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch views"})
+		return
+	}
+	referrers, err := services.ProfileReferrers(ctx, share.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch views"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"view_count": share.ViewCount,
+			"views":      views,
+			"referrers":  referrers,
+		},
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// loadSharedProfile resolves the token of a public request, records the view
+// and returns the profile with signed clip URLs. Responses are never cached
+// by browsers or proxies, so revoking a share hides the profile at once.
+func loadSharedProfile(c *gin.Context) (*models.PublicRecruitingProfile, bool) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+
+	ctx := c.Request.Context()
+	share, profile, err := services.ResolveShare(ctx, c.Param("token"))
+	switch {
+	case errors.Is(err, services.ErrShareUnavailable):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch profile"})
+		return nil, false
+	}
+
+	public, err := services.GetPublicRecruitingProfile(ctx, profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch profile"})
+		return nil, false
+	}
+	services.SignClipURLs(ctx, public.Highlights)
+
+	visitor := services.ProfileVisitorHash(share.ID, c.ClientIP(), c.Request.UserAgent())
+	if err := services.RecordProfileView(ctx, share, visitor, c.Request.Referer()); err != nil {
+		// The profile is still shown when the view cannot be logged
+		c.Error(err)
+	}
+	return public, true
+}
+
+// API for Frontend - Get Shared Profile (public, by share token); read-only JSON view
+func GetSharedProfile(c *gin.Context) {
+	profile, ok := loadSharedProfile(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// Shared Profile Page (public, by share token); read-only HTML view
+func GetSharedProfilePage(c *gin.Context) {
+	profile, ok := loadSharedProfile(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := services.RenderSharedProfilePage(&buf, profile); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render profile"})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadEditableRecruitingProfile loads the route player's profile, nil when
+// it does not exist yet, and checks the caller may edit it
+func loadEditableRecruitingProfile(c *gin.Context) (uint, *models.RecruitingProfile, bool) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return 0, nil, false
+	}
+
+	ctx := c.Request.Context()
+	profile, err := services.GetRecruitingProfile(ctx, uint(playerID))
+	if err != nil && !errors.Is(err, services.ErrRecruitingProfileNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return 0, nil, false
+	}
+
+	if !canEditRecruitingProfile(c, uint(playerID), profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player, or their parents while they are a minor, can edit the recruiting profile"})
+		return 0, nil, false
+	}
+	return uint(playerID), profile, true
+}
+
+// canEditRecruitingProfile allows the player, and approved parents while the
+// player is a minor; profile is nil before it is created
+func canEditRecruitingProfile(c *gin.Context, playerID uint, profile *models.RecruitingProfile) bool {
+	userID := middleware.CurrentUserID(c)
+	if userID == playerID {
+		return true
+	}
+	return services.IsParentOf(c.Request.Context(), userID, playerID) && services.IsMinor(profile)
+}
+
+// canSetDateOfBirth allows the player's coaches and OrgAdmins, and approved
+// parents while the player is a minor. The date of birth decides who controls
+// the profile, so the player cannot set it.
+func canSetDateOfBirth(c *gin.Context, profile *models.RecruitingProfile) bool {
+	userID := middleware.CurrentUserID(c)
+	if services.IsParentOf(c.Request.Context(), userID, profile.PlayerID) && services.IsMinor(profile) {
+		return true
+	}
+	return canManagePlayer(c, profile.PlayerID)
+}
+
+// respondRecruitingProfile writes the profile with its stats and signed highlight clips
+func respondRecruitingProfile(c *gin.Context, profile *models.RecruitingProfile) {
+	ctx := c.Request.Context()
+	view, err := services.BuildRecruitingProfileView(ctx, profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return
+	}
+	services.SignClipURLs(ctx, view.Highlights)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    view,
+	})
+}
+
+// API for Frontend - Get Recruiting Profile Schemas (additional_info fields of every sport)
+func GetRecruitingProfileSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    services.ProfileSchemas(),
+	})
+}
+
+// API for Frontend - Get Recruiting Profile (player, parents, coaches, OrgAdmins); embeds season stats in the profile's sport and highlight clips
+func GetRecruitingProfile(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot view this player's recruiting profile"})
+		return
+	}
+
+	profile, err := services.GetRecruitingProfile(c.Request.Context(), uint(playerID))
+	switch {
+	case errors.Is(err, services.ErrRecruitingProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return
+	}
+	respondRecruitingProfile(c, profile)
+}
+
+// API for Frontend - Save Recruiting Profile (player, parents of minors); creates the profile or updates the given fields, additional_info is validated against the sport's schema
+func SaveRecruitingProfile(c *gin.Context) {
+	playerID, _, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveRecruitingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := services.SaveRecruitingProfile(c.Request.Context(), playerID, middleware.CurrentUserID(c), &req)
+	var invalid *services.ProfileInfoValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  err.Error(),
+			"fields": invalid.Problems,
+		})
+		return
+	case errors.Is(err, services.ErrRecruitingSportRequired), errors.Is(err, services.ErrRecruitingSportNotSupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrDateOfBirthLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save recruiting profile"})
+		return
+	}
+	respondRecruitingProfile(c, profile)
+}
+
+// API for Frontend - Set Recruiting Profile Date of Birth (parents of minors, coaches, OrgAdmins); decides whether parents still control the profile
+func SetRecruitingDateOfBirth(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	profile, err := services.GetRecruitingProfile(ctx, uint(playerID))
+	switch {
+	case errors.Is(err, services.ErrRecruitingProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recruiting profile"})
+		return
+	}
+	if !canSetDateOfBirth(c, profile) {
+		c.JSON(http.StatusForbidden, gin.H{"error": services.ErrDateOfBirthLocked.Error()})
+		return
+	}
+
+	var req models.SetDateOfBirthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.SetProfileDateOfBirth(ctx, profile, middleware.CurrentUserID(c), req.DateOfBirth); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save date of birth"})
+		return
+	}
+	respondRecruitingProfile(c, profile)
+}
+
+// API for Frontend - Set Recruiting Profile Highlights (player, parents of minors); replaces the clips with video_tag_ids in display order
+func SetProfileHighlights(c *gin.Context) {
+	_, profile, ok := loadEditableRecruitingProfile(c)
+	if !ok {
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recruiting profile not found"})
+		return
+	}
+
+	var req models.SetProfileHighlightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	clips, err := services.SetProfileHighlights(ctx, profile, videoViewer(c), req.VideoTagIDs)
+	switch {
+	case errors.Is(err, services.ErrHighlightNotAllowed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save highlights"})
+		return
+	}
+	services.SignClipURLs(ctx, clips)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    clips,
+	})
+}
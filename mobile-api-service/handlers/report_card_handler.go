@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dayRange reads the from/to days (YYYY-MM-DD) of a query; defaults to the last 90 days
+func dayRange(c *gin.Context) (time.Time, time.Time, bool) {
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = day.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	from := to.AddDate(0, 0, -90)
+	if value := c.Query("from"); value != "" {
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = day
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// reportTeam picks the team of a player's report card: the requested one, or
+// the first of the player's teams the caller coaches. Either way the caller
+// must manage the team, since the card holds its notes and stats.
+func reportTeam(c *gin.Context, playerID uint) (*models.Team, bool) {
+	var teamID uint
+	if value := c.Query("team_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return nil, false
+		}
+		teamID = uint(id)
+		if !canManageTeam(c, teamID) {
+			teamID = 0
+		}
+	} else {
+		teamIDs, err := services.PlayerTeamIDs(c.Request.Context(), playerID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player teams"})
+			return nil, false
+		}
+		if len(teamIDs) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player is not on any team"})
+			return nil, false
+		}
+		for _, id := range teamIDs {
+			if canManageTeam(c, id) {
+				teamID = id
+				break
+			}
+		}
+	}
+	if teamID == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the team's coaches and OrgAdmins can generate its report cards"})
+		return nil, false
+	}
+
+	var team models.Team
+	if err := database.DB.First(&team, teamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return nil, false
+	}
+	return &team, true
+}
+
+// API for Frontend - Download Player Report Card PDF (coaches, OrgAdmins); team_id defaults to a team the caller coaches, from/to default to the last 90 days
+func GetPlayerReportCard(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canManagePlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player's coaches and OrgAdmins can generate report cards"})
+		return
+	}
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+	team, ok := reportTeam(c, uint(playerID))
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	branding, err := services.LoadReportBranding(ctx, team.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load organization"})
+		return
+	}
+	card, err := services.BuildReportCard(ctx, uint(playerID), team, branding.Organization, from, to)
+	switch {
+	case errors.Is(err, services.ErrReportPlayerNotOnTeam):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report card"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := services.RenderReportCard(card, branding, &buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render report card"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.ReportCardFileName(card)))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// API for Frontend - Download Team Report Cards (coaches, OrgAdmins); zip with one PDF per active player, from/to default to the last 90 days
+func GetTeamReportCards(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can generate report cards"})
+		return
+	}
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := database.DB.First(&team, teamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	branding, err := services.LoadReportBranding(ctx, team.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load organization"})
+		return
+	}
+
+	// Built in memory so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	if _, err := services.WriteTeamReportCards(ctx, &team, branding, from, to, &buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate report cards"})
+		return
+	}
+	fileName := fmt.Sprintf("report-cards-team-%d-%s.zip", team.ID, to.Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Record Shot Chart (coaches); re-sending a client_id corrects that shot
+func RecordGameShots(c *gin.Context) {
+	event, ok := loadStatsEvent(c, true)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can chart shots"})
+		return
+	}
+
+	var req models.RecordGameShotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := services.RecordGameShots(ctx, event, middleware.CurrentUserID(c), req.Shots)
+	var invalid *services.GameStatValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"rows":  invalid.Rows,
+		})
+		return
+	case errors.Is(err, services.ErrShotChartBasketballOnly), errors.Is(err, services.ErrSportNotSupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record shots"})
+		return
+	}
+
+	shots, err := services.ListGameShots(ctx, event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Shots saved but could not be listed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shots,
+	})
+}
+
+// API for Frontend - Get Shots of a Game (team members and parents)
+func GetGameShots(c *gin.Context) {
+	event, ok := loadStatsEvent(c, false)
+	if !ok {
+		return
+	}
+	if !canViewTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this team's stats"})
+		return
+	}
+
+	shots, err := services.ListGameShots(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shots,
+	})
+}
+
+// API for Frontend - Delete a Charted Shot (coaches)
+func DeleteGameShot(c *gin.Context) {
+	event, ok := loadStatsEvent(c, false)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can chart shots"})
+		return
+	}
+	shotID, err := strconv.ParseUint(c.Param("shotId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shot ID"})
+		return
+	}
+
+	err = services.DeleteGameShot(c.Request.Context(), event, uint(shotID))
+	switch {
+	case errors.Is(err, services.ErrShotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shot not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shot"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Shot deleted",
+	})
+}
+
+// API for Frontend - Get Team Shot Chart (zones, shot types and heat-map grid for the season, a player or a game)
+func GetTeamShotChart(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canViewTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this team's stats"})
+		return
+	}
+	playerID, _ := strconv.ParseUint(c.Query("player_id"), 10, 64)
+	eventID, _ := strconv.ParseUint(c.Query("event_id"), 10, 64)
+
+	chart, err := services.GetShotChart(c.Request.Context(), services.ShotChartFilter{
+		TeamID:   uint(teamID),
+		PlayerID: uint(playerID),
+		EventID:  uint(eventID),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shot chart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    chart,
+	})
+}
+
+// API for Frontend - Get Player Shot Chart (all teams, or one team or season)
+func GetPlayerShotChart(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this player's stats"})
+		return
+	}
+	teamID, _ := strconv.ParseUint(c.Query("team_id"), 10, 64)
+	seasonID, _ := strconv.ParseUint(c.Query("season_id"), 10, 64)
+
+	chart, err := services.GetShotChart(c.Request.Context(), services.ShotChartFilter{
+		PlayerID: uint(playerID),
+		SeasonID: uint(seasonID),
+		TeamID:   uint(teamID),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shot chart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    chart,
+	})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Get Stat Definitions (fields, rules and derived metrics of every sport)
+func GetStatDefinitions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    services.StatDefinitions(),
+	})
+}
+
+// API for Frontend - Get Team Stat Definition (drives the stat entry form of the team's sport)
+func GetTeamStatDefinition(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canViewTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this team"})
+		return
+	}
+
+	definition, err := services.TeamStatDefinition(c.Request.Context(), uint(teamID))
+	switch {
+	case errors.Is(err, services.ErrSportNotSupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    definition,
+	})
+}
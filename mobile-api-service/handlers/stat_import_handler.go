@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statImportError answers the errors shared by import preview and confirmation
+func statImportError(c *gin.Context, err error) {
+	var mapping *services.StatImportMappingError
+	var invalid *services.GameStatValidationError
+	switch {
+	case errors.As(err, &mapping):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    err.Error(),
+			"problems": mapping.Problems,
+		})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"rows":  invalid.Rows,
+		})
+	case errors.Is(err, services.ErrStatImportProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import profile not found"})
+	case errors.Is(err, services.ErrStatsFromPlayLog):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrStatImportEmpty), errors.Is(err, services.ErrSportNotSupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import stats"})
+	}
+}
+
+// API for Frontend - Preview Stat Import (coaches); parses a scorebook CSV and matches rows to the roster without saving
+func PreviewStatImport(c *gin.Context) {
+	event, ok := loadStatsEvent(c, true)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can enter stats"})
+		return
+	}
+
+	var req models.StatImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, _, err := services.PreviewStatImport(c.Request.Context(), event, &req)
+	if err != nil {
+		statImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preview,
+	})
+}
+
+// API for Frontend - Import Stats from CSV (coaches); upserts the previewed rows into the game's stats
+func ImportGameStats(c *gin.Context) {
+	event, ok := loadStatsEvent(c, true)
+	if !ok {
+		return
+	}
+	if !canManageTeam(c, event.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can enter stats"})
+		return
+	}
+
+	var req models.StatImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	preview, written, err := services.ImportGameStats(ctx, event, middleware.CurrentUserID(c), &req)
+	if errors.Is(err, services.ErrStatImportInvalid) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"data":  preview,
+		})
+		return
+	}
+	if err != nil {
+		statImportError(c, err)
+		return
+	}
+
+	box, err := services.BuildBoxScore(ctx, event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stats imported but the box score could not be built"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    box,
+		"import":  preview,
+		"written": written,
+	})
+}
+
+// API for Frontend - Get Stat Import Profiles of a Team (coaches)
+func GetStatImportProfiles(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can manage import profiles"})
+		return
+	}
+
+	profiles, err := services.ListStatImportProfiles(c.Request.Context(), uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import profiles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    profiles,
+	})
+}
+
+// API for Frontend - Create Stat Import Profile (coaches); saves a CSV column mapping for the team
+func CreateStatImportProfile(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can manage import profiles"})
+		return
+	}
+
+	var req models.StatImportProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile := &models.StatImportProfile{TeamID: uint(teamID), CreatedBy: middleware.CurrentUserID(c)}
+	if err := services.SaveStatImportProfile(c.Request.Context(), profile, &req); err != nil {
+		statImportProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// API for Frontend - Update Stat Import Profile (coaches)
+func UpdateStatImportProfile(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	profileID, err := strconv.ParseUint(c.Param("profileId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can manage import profiles"})
+		return
+	}
+
+	var req models.StatImportProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	profile, err := services.GetStatImportProfile(ctx, uint(teamID), uint(profileID))
+	if err == nil {
+		err = services.SaveStatImportProfile(ctx, profile, &req)
+	}
+	if err != nil {
+		statImportProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// API for Frontend - Delete Stat Import Profile (coaches)
+func DeleteStatImportProfile(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	profileID, err := strconv.ParseUint(c.Param("profileId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can manage import profiles"})
+		return
+	}
+
+	if err := services.DeleteStatImportProfile(c.Request.Context(), uint(teamID), uint(profileID)); err != nil {
+		statImportProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Import profile deleted",
+	})
+}
+
+func statImportProfileError(c *gin.Context, err error) {
+	var mapping *services.StatImportMappingError
+	switch {
+	case errors.As(err, &mapping):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    err.Error(),
+			"problems": mapping.Problems,
+		})
+	case errors.Is(err, services.ErrStatImportProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import profile not found"})
+	case errors.Is(err, services.ErrStatImportProfileExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSportNotSupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save import profile"})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Get Player Stats (game log, seasons, career and advanced metrics)
+func GetPlayerStats(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this player's stats"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	teamID, _ := strconv.ParseUint(c.Query("team_id"), 10, 64)
+	seasonID, _ := strconv.ParseUint(c.Query("season_id"), 10, 64)
+
+	stats, err := services.GetPlayerStats(c.Request.Context(), uint(playerID), services.PlayerStatsFilter{
+		TeamID:   uint(teamID),
+		SeasonID: uint(seasonID),
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": stats.GamesTotal,
+		},
+	})
+}
+
+// API for Frontend - Get Team Stats (per-game totals, player season lines and season summary)
+func GetTeamStats(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canViewTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this team's stats"})
+		return
+	}
+
+	stats, err := services.GetTeamStats(c.Request.Context(), uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// API for Admin - Rebuild a Team's Season Stats from its game stats
+func RebuildTeamStats(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to manage this team"})
+		return
+	}
+
+	if err := services.RebuildTeamSeasonStats(c.Request.Context(), uint(teamID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rebuild stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Season stats rebuilt",
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Get Video Processing Progress (per video_permissions)
+func GetVideoProcessing(c *gin.Context) {
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+
+	var video models.Video
+	if err := database.DB.Select("id", "team_id", "processing_status").First(&video, videoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		return
+	}
+	if !canViewVideo(c, video.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this video"})
+		return
+	}
+
+	job, err := services.GetLatestVideoJob(c.Request.Context(), video.ID)
+	if err != nil && !errors.Is(err, services.ErrVideoJobNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch processing job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"processing_status": video.ProcessingStatus,
+			"job":               job,
+		},
+	})
+}
+
+// API for Admin - Get Video Processing Jobs (filter by status, 5 = dead-lettered); OrgAdmins see the jobs of their organizations' videos
+func GetVideoJobList(c *gin.Context) {
+	query := database.DB.Model(&models.VideoProcessingJob{}).Select("video_processing_jobs.*")
+	if !middleware.HasRole(c, models.RoleSuperAdmin) {
+		query = query.
+			Joins("JOIN videos ON videos.id = video_processing_jobs.video_id").
+			Joins("JOIN teams ON teams.id = videos.team_id").
+			Where("teams.organization_id IN ?", middleware.OrganizationIDs(c))
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("video_processing_jobs.status = ?", status)
+	}
+	if videoID := c.Query("video_id"); videoID != "" {
+		query = query.Where("video_processing_jobs.video_id = ?", videoID)
+	}
+
+	var jobs []models.VideoProcessingJob
+	if err := query.Order("video_processing_jobs.id DESC").Limit(100).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    jobs,
+	})
+}
+
+// API for Admin - Retry a Dead-Lettered Video Processing Job (SuperAdmins, OrgAdmins of the video's team)
+func RetryVideoJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	var video models.Video
+	if err := database.DB.Select("videos.id", "videos.team_id").
+		Joins("JOIN video_processing_jobs ON video_processing_jobs.video_id = videos.id").
+		Where("video_processing_jobs.id = ?", id).
+		First(&video).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": services.ErrVideoJobNotFound.Error()})
+		return
+	}
+	if !canManageTeam(c, video.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only OrgAdmins of the video's organization can retry its jobs"})
+		return
+	}
+
+	job, err := services.RetryDeadVideoJob(c.Request.Context(), uint(id))
+	switch {
+	case err == nil:
+		c.JSON(http.StatusAccepted, gin.H{
+			"success": true,
+			"data":    job,
+		})
+	case errors.Is(err, services.ErrVideoJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrVideoJobNotDead):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry job"})
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+	"mobile-api-service/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Get Team Video List (per video_permissions; URLs are signed and expire)
+func GetTeamVideoList(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	videos, total, err := services.ListTeamVideos(c.Request.Context(), videoViewer(c), uint(teamID), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch videos"})
+		return
+	}
+	for i := range videos {
+		services.SignVideoURLs(c.Request.Context(), &videos[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    videos,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// API for Frontend - Get Video Permissions (coaches)
+func GetVideoPermissionList(c *gin.Context) {
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+
+	var video models.Video
+	if err := database.DB.Select("id", "team_id").First(&video, videoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		return
+	}
+	if !canManageTeam(c, video.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can view video permissions"})
+		return
+	}
+
+	permissions, err := services.ListVideoPermissions(c.Request.Context(), video.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch permissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    permissions,
+	})
+}
+
+// loadManagedVideos binds a bulk permission request and loads its videos,
+// failing unless the caller manages the team of every one of them
+func loadManagedVideos(c *gin.Context) (*models.BulkVideoPermissionRequest, []models.Video, bool) {
+	var req models.BulkVideoPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+
+	var videos []models.Video
+	if err := database.DB.Select("id", "team_id").Where("id IN ?", req.VideoIDs).Find(&videos).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch videos"})
+		return nil, nil, false
+	}
+	found := make(map[uint]bool, len(videos))
+	for _, video := range videos {
+		found[video.ID] = true
+	}
+	for _, id := range req.VideoIDs {
+		if !found[id] {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found", "video_id": id})
+			return nil, nil, false
+		}
+	}
+
+	managed := make(map[uint]bool)
+	for _, video := range videos {
+		if _, checked := managed[video.TeamID]; !checked {
+			managed[video.TeamID] = canManageTeam(c, video.TeamID)
+		}
+		if !managed[video.TeamID] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can change video permissions", "video_id": video.ID})
+			return nil, nil, false
+		}
+	}
+	return &req, videos, true
+}
+
+// API for Frontend - Grant Video Access in Bulk (coaches)
+func GrantVideoPermissions(c *gin.Context) {
+	req, videos, ok := loadManagedVideos(c)
+	if !ok {
+		return
+	}
+
+	created, err := services.GrantVideoAccess(c.Request.Context(), videos, req.Grants, middleware.CurrentUserID(c))
+	if errors.Is(err, services.ErrInvalidVideoGrant) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Player and parent grants need a user_id of a player on the video's team or an approved parent of one; public and team grants take none"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant access"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"granted": created},
+	})
+}
+
+// API for Frontend - Revoke Video Access in Bulk (coaches)
+func RevokeVideoPermissions(c *gin.Context) {
+	req, _, ok := loadManagedVideos(c)
+	if !ok {
+		return
+	}
+
+	removed, err := services.RevokeVideoAccess(c.Request.Context(), req.VideoIDs, req.Grants)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke access"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"revoked": removed},
+	})
+}
+
+// ServeMedia serves locally stored media behind a valid, unexpired signature
+func ServeMedia(c *gin.Context) {
+	local, ok := storage.Default.(*storage.LocalBackend)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	key := c.Param("key")
+	if !local.VerifySignature(key, c.Query("expires"), c.Query("signature")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Link is invalid or has expired"})
+		return
+	}
+	path, err := local.ObjectPath(key)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.File(path)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API for Frontend - Tag a Player in a Video (coaches)
+func CreateVideoTag(c *gin.Context) {
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateVideoTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var video models.Video
+	if err := database.DB.Select("id", "team_id").First(&video, videoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		return
+	}
+	if !canManageTeam(c, video.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can tag videos"})
+		return
+	}
+
+	tag, err := services.CreateVideoTag(c.Request.Context(), videoID, middleware.CurrentUserID(c), &req)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusCreated, gin.H{
+			"success": true,
+			"data":    tag,
+		})
+	case errors.Is(err, services.ErrVideoNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrVideoNotProcessed), errors.Is(err, services.ErrVideoTagOverlap):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrVideoTagOutOfRange), errors.Is(err, services.ErrVideoTagNotPlayer):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to tag video"})
+	}
+}
+
+// API for Frontend - Get Tags of a Video (per video_permissions)
+func GetVideoTagList(c *gin.Context) {
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+
+	var video models.Video
+	if err := database.DB.Select("id", "team_id").First(&video, videoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		return
+	}
+	if !canViewVideo(c, video.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this video"})
+		return
+	}
+
+	tags, err := services.ListVideoTags(c.Request.Context(), videoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+	services.SignTagURLs(tags)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tags,
+	})
+}
+
+// API for Frontend - Delete Video Tag (coaches)
+func DeleteVideoTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var tag models.VideoTag
+	if err := database.DB.First(&tag, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+	var video models.Video
+	if err := database.DB.Unscoped().Select("id", "team_id").First(&video, tag.VideoID).Error; err != nil || !canManageTeam(c, video.TeamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can delete tags"})
+		return
+	}
+
+	if err := services.DeleteVideoTag(c.Request.Context(), tag.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag deleted",
+	})
+}
+
+// API for Frontend - Get a Player's Tagged Clips across all videos
+func GetPlayerVideoList(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("playerId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	if !canViewPlayer(c, uint(playerID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this player's videos"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	seasonID, _ := strconv.ParseUint(c.Query("season_id"), 10, 64)
+
+	clips, total, err := services.ListPlayerClips(c.Request.Context(), videoViewer(c), uint(playerID), services.PlayerClipFilter{
+		Label:    c.Query("label"),
+		SeasonID: uint(seasonID),
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch videos"})
+		return
+	}
+	services.SignClipURLs(c.Request.Context(), clips)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    clips,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
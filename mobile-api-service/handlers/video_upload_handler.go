@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mobile-api-service/database"
+	"mobile-api-service/middleware"
+	"mobile-api-service/models"
+	"mobile-api-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const tusVersion = "1.0.0"
+
+// statusChecksumMismatch is the tus checksum extension's "460 Checksum Mismatch"
+const statusChecksumMismatch = 460
+
+func setTusHeaders(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", "creation,checksum,termination")
+	c.Header("Tus-Checksum-Algorithm", services.SupportedChecksumAlgorithms)
+	c.Header("Tus-Max-Size", strconv.FormatInt(services.MaxVideoUploadBytes(), 10))
+}
+
+// checkTusVersion rejects clients speaking another tus protocol version
+func checkTusVersion(c *gin.Context) bool {
+	setTusHeaders(c)
+	if v := c.GetHeader("Tus-Resumable"); v != "" && v != tusVersion {
+		c.AbortWithStatus(http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// parseUploadMetadata decodes the tus Upload-Metadata header ("key base64,key2 base64")
+func parseUploadMetadata(header string) (map[string]string, error) {
+	meta := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, " ", 2)
+		value := ""
+		if len(parts) == 2 {
+			decoded, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid Upload-Metadata value for %q", parts[0])
+			}
+			value = string(decoded)
+		}
+		meta[parts[0]] = value
+	}
+	return meta, nil
+}
+
+func uploadVideoID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid video ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// loadOwnUpload returns the upload of the video when the current user created it
+func loadOwnUpload(c *gin.Context, videoID uint) (*models.VideoUpload, bool) {
+	upload, err := services.GetVideoUpload(c.Request.Context(), videoID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+		return nil, false
+	}
+	if upload.CreatedBy != middleware.CurrentUserID(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Only the uploader can resume this upload"})
+		return nil, false
+	}
+	return upload, true
+}
+
+// API for Frontend - Create Video Upload (tus creation, coaches)
+func CreateVideoUpload(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if !canManageTeam(c, uint(teamID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team coaches can upload videos"})
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Length header is required"})
+		return
+	}
+	if length > services.MaxVideoUploadBytes() {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Video exceeds the maximum upload size"})
+		return
+	}
+
+	meta, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	title := strings.TrimSpace(meta["title"])
+	if title == "" {
+		title = meta["filename"]
+	}
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Metadata must include a title or filename"})
+		return
+	}
+
+	input := &services.CreateVideoUploadInput{
+		TeamID:      uint(teamID),
+		Title:       title,
+		Description: meta["description"],
+		FileName:    meta["filename"],
+		FileType:    meta["filetype"],
+		Length:      length,
+		SHA256:      meta["sha256"],
+		UploadedBy:  middleware.CurrentUserID(c),
+	}
+	if raw := meta["event_id"]; raw != "" {
+		eventID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event_id"})
+			return
+		}
+		var event models.Event
+		if err := database.DB.Select("id", "team_id").First(&event, eventID).Error; err != nil || event.TeamID != uint(teamID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Event does not belong to this team"})
+			return
+		}
+		id := uint(eventID)
+		input.EventID = &id
+	}
+
+	video, upload, err := services.CreateVideoUpload(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/videos/%d/upload", video.ID))
+	c.Header("Upload-Offset", "0")
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"video":  video,
+			"upload": upload,
+		},
+	})
+}
+
+// API for Frontend - Get Video Upload Offset (tus HEAD)
+func GetVideoUploadOffset(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+	upload, ok := loadOwnUpload(c, videoID)
+	if !ok {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Upload-Offset", strconv.FormatInt(upload.UploadOffset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.UploadLength, 10))
+	c.Status(http.StatusOK)
+}
+
+// API for Frontend - Upload Video Chunk (tus PATCH)
+func UploadVideoChunk(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	if c.ContentType() != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/offset+octet-stream"})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset header is required"})
+		return
+	}
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+	if _, ok := loadOwnUpload(c, videoID); !ok {
+		return
+	}
+
+	upload, err := services.WriteVideoChunk(c.Request.Context(), videoID, offset, c.Request.Body, c.GetHeader("Upload-Checksum"))
+	if upload != nil {
+		c.Header("Upload-Offset", strconv.FormatInt(upload.UploadOffset, 10))
+	}
+	switch {
+	case err == nil:
+		c.Status(http.StatusNoContent)
+	case errors.Is(err, services.ErrUploadOffsetMismatch), errors.Is(err, services.ErrUploadFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUploadLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrChecksumMismatch):
+		c.JSON(statusChecksumMismatch, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUploadTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrChecksumAlgorithm), errors.Is(err, services.ErrUploadChunkTooSmall):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store upload chunk"})
+	}
+}
+
+// API for Frontend - Terminate Video Upload (tus DELETE)
+func DeleteVideoUpload(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+	if _, ok := loadOwnUpload(c, videoID); !ok {
+		return
+	}
+
+	switch err := services.AbortVideoUpload(c.Request.Context(), videoID); {
+	case err == nil:
+		c.Status(http.StatusNoContent)
+	case errors.Is(err, services.ErrUploadFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUploadLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to terminate upload"})
+	}
+}
+
+// API for Frontend - Get Video (per video_permissions; URLs are signed and expire)
+func GetVideo(c *gin.Context) {
+	videoID, ok := uploadVideoID(c)
+	if !ok {
+		return
+	}
+
+	var video models.Video
+	if err := database.DB.First(&video, videoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+		return
+	}
+	if !canViewVideo(c, video.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this video"})
+		return
+	}
+
+	services.SignVideoURLs(c.Request.Context(), &video)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    video,
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"mobile-api-service/config"
 	"mobile-api-service/database"
 	"mobile-api-service/routes"
+	"mobile-api-service/services"
+	"mobile-api-service/storage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,6 +25,19 @@ func main() {
 	// Connect to Redis
 	database.ConnectRedis()
 
+	// Select media storage
+	storage.Init()
+
+	// Wire notification channels
+	services.InitNotificationDispatcher()
+	services.StartDevicePruner()
+	services.StartDigestWorker()
+	services.StartScheduleChangeWorker()
+	services.StartGoalReminderWorker()
+
+	// Process uploaded videos (unless cmd/video-worker does)
+	services.StartVideoWorkers()
+
 	// Setup routes
 	r := routes.SetupRoutes()
 
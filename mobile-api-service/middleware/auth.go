@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"mobile-api-service/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Context keys set by Auth
+const (
+	ContextUserID = "user_id"
+	ContextRoles  = "roles"
+	ContextOrgIDs = "org_ids"
+)
+
+// Claims carried by access tokens issued by the auth service
+type Claims struct {
+	UserID uint     `json:"user_id"`
+	Email  string   `json:"email"`
+	Roles  []string `json:"roles"`
+	OrgIDs []uint   `json:"org_ids"`
+	jwt.RegisteredClaims
+}
+
+// Auth middleware validates the bearer token and stores the caller in the context
+func Auth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString := strings.TrimPrefix(header, "Bearer ")
+		if header == "" || tokenString == header {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing bearer token"})
+			return
+		}
+
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+			return []byte(config.AppConfig.JWTSecret), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+		if err != nil || !token.Valid || claims.UserID == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		c.Set(ContextUserID, claims.UserID)
+		c.Set(ContextRoles, claims.Roles)
+		c.Set(ContextOrgIDs, claims.OrgIDs)
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the authenticated user ID
+func CurrentUserID(c *gin.Context) uint {
+	return c.GetUint(ContextUserID)
+}
+
+// HasRole reports whether the authenticated user carries the given role
+func HasRole(c *gin.Context, role string) bool {
+	for _, r := range c.GetStringSlice(ContextRoles) {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole middleware rejects callers that carry none of the given roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range roles {
+			if HasRole(c, role) {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+// OrganizationIDs returns the organizations listed in the token
+func OrganizationIDs(c *gin.Context) []uint {
+	orgIDs, _ := c.Get(ContextOrgIDs)
+	ids, _ := orgIDs.([]uint)
+	return ids
+}
+
+// InOrganization reports whether the token lists the organization
+func InOrganization(c *gin.Context, organizationID uint) bool {
+	for _, id := range OrganizationIDs(c) {
+		if id == organizationID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AcademicEntry is a player's grades for one term. GPA data is sensitive and
+// only shown to the player, their parents, their coaches and OrgAdmins.
+// Terms are ordered by TermEndDate; entries saved before it was recorded have
+// none and are ordered by when they were entered.
+type AcademicEntry struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	PlayerID      uint       `json:"player_id" gorm:"not null;uniqueIndex:unique_player_term;index"`
+	Term          string     `json:"term" gorm:"size:100;not null;uniqueIndex:unique_player_term;index"`
+	TermEndDate   *time.Time `json:"term_end_date" gorm:"type:date;index"`
+	GPA           *float64   `json:"gpa" gorm:"column:gpa;type:decimal(3,2)"`
+	FailingGrades *int       `json:"failing_grades"`
+	Notes         string     `json:"notes" gorm:"type:text"`
+	EnteredBy     uint       `json:"entered_by" gorm:"not null"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type CreateAcademicEntryRequest struct {
+	Term          string   `json:"term" binding:"required,max=100"`
+	TermEndDate   string   `json:"term_end_date" binding:"required,datetime=2006-01-02"`
+	GPA           *float64 `json:"gpa" binding:"omitempty,min=0,max=5"`
+	FailingGrades *int     `json:"failing_grades" binding:"omitempty,min=0,max=20"`
+	Notes         string   `json:"notes"`
+}
+
+type UpdateAcademicEntryRequest struct {
+	Term          *string  `json:"term" binding:"omitempty,min=1,max=100"`
+	TermEndDate   *string  `json:"term_end_date" binding:"omitempty,datetime=2006-01-02"`
+	GPA           *float64 `json:"gpa" binding:"omitempty,min=0,max=5"`
+	FailingGrades *int     `json:"failing_grades" binding:"omitempty,min=0,max=20"`
+	Notes         *string  `json:"notes"`
+}
+
+// EligibilityRule is an organization's academic eligibility rule set. A
+// player is ineligible below MinGPA or with more than MaxFailingGrades
+// failing grades, and on watch below WatchGPA or with at least
+// WatchFailingGrades failing grades. Unset thresholds are not checked.
+type EligibilityRule struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID     uint      `json:"organization_id" gorm:"not null;uniqueIndex"`
+	MinGPA             *float64  `json:"min_gpa" gorm:"column:min_gpa;type:decimal(3,2)"`
+	WatchGPA           *float64  `json:"watch_gpa" gorm:"column:watch_gpa;type:decimal(3,2)"`
+	MaxFailingGrades   *int      `json:"max_failing_grades"`
+	WatchFailingGrades *int      `json:"watch_failing_grades"`
+	UpdatedBy          uint      `json:"updated_by" gorm:"not null"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type EligibilityRuleRequest struct {
+	MinGPA             *float64 `json:"min_gpa" binding:"omitempty,min=0,max=5"`
+	WatchGPA           *float64 `json:"watch_gpa" binding:"omitempty,min=0,max=5"`
+	MaxFailingGrades   *int     `json:"max_failing_grades" binding:"omitempty,min=0,max=20"`
+	WatchFailingGrades *int     `json:"watch_failing_grades" binding:"omitempty,min=0,max=20"`
+}
+
+// Eligibility status (player_eligibility.status)
+const (
+	EligibilityEligible   uint8 = 1
+	EligibilityWatch      uint8 = 2
+	EligibilityIneligible uint8 = 3
+)
+
+// EligibilityStatusName is the wording of a status in notifications
+func EligibilityStatusName(status uint8) string {
+	switch status {
+	case EligibilityWatch:
+		return "on academic watch"
+	case EligibilityIneligible:
+		return "academically ineligible"
+	}
+	return "academically eligible"
+}
+
+// PlayerEligibility is the latest evaluation of a player against one
+// organization's rule set, based on their most recent academic entry
+type PlayerEligibility struct {
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	PlayerID       uint            `json:"player_id" gorm:"not null;uniqueIndex:unique_player_org"`
+	OrganizationID uint            `json:"organization_id" gorm:"not null;uniqueIndex:unique_player_org;index"`
+	Status         uint8           `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	Term           string          `json:"term" gorm:"size:100"`
+	Reasons        json.RawMessage `json:"reasons" gorm:"type:json"`
+	EvaluatedAt    time.Time       `json:"evaluated_at"`
+	ChangedAt      time.Time       `json:"changed_at"`
+}
+
+func (PlayerEligibility) TableName() string {
+	return "player_eligibility"
+}
+
+// TeamEligibilityLine is one roster player's eligibility in the team's organization
+type TeamEligibilityLine struct {
+	PlayerID     uint               `json:"player_id"`
+	PlayerName   string             `json:"player_name"`
+	JerseyNumber *int               `json:"jersey_number"`
+	Eligibility  *PlayerEligibility `json:"eligibility"`
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"time"
+)
+
+// EffortMetric is a coach's hustle and engagement rating of a player for one
+// practice or game, each from 1 to 10
+type EffortMetric struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	EventID         uint      `json:"event_id" gorm:"not null;uniqueIndex:unique_event_player;index"`
+	PlayerID        uint      `json:"player_id" gorm:"not null;uniqueIndex:unique_event_player;index"`
+	HustleScore     int       `json:"hustle_score" gorm:"not null"`
+	EngagementScore int       `json:"engagement_score" gorm:"not null"`
+	Notes           string    `json:"notes" gorm:"type:text"`
+	EnteredBy       uint      `json:"entered_by" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// BuyInScore rates from 1 to 10 how bought in a player was at an event
+type BuyInScore struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	EventID   uint      `json:"event_id" gorm:"not null;uniqueIndex:unique_event_player;index"`
+	PlayerID  uint      `json:"player_id" gorm:"not null;uniqueIndex:unique_event_player;index"`
+	Score     int       `json:"score" gorm:"not null"`
+	Notes     string    `json:"notes" gorm:"type:text"`
+	EnteredBy uint      `json:"entered_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type EffortLine struct {
+	PlayerID        uint   `json:"player_id" binding:"required"`
+	HustleScore     int    `json:"hustle_score" binding:"required,min=1,max=10"`
+	EngagementScore int    `json:"engagement_score" binding:"required,min=1,max=10"`
+	Notes           string `json:"notes"`
+}
+
+type BulkEffortRequest struct {
+	Scores []EffortLine `json:"scores" binding:"required,min=1,max=50,dive"`
+}
+
+type BuyInLine struct {
+	PlayerID uint   `json:"player_id" binding:"required"`
+	Score    int    `json:"score" binding:"required,min=1,max=10"`
+	Notes    string `json:"notes"`
+}
+
+type BulkBuyInRequest struct {
+	Scores []BuyInLine `json:"scores" binding:"required,min=1,max=50,dive"`
+}
+
+// EventEffortLine is one roster player's scores for an event; nil when not rated
+type EventEffortLine struct {
+	PlayerID     uint          `json:"player_id"`
+	PlayerName   string        `json:"player_name"`
+	JerseyNumber *int          `json:"jersey_number"`
+	Effort       *EffortMetric `json:"effort"`
+	BuyIn        *BuyInScore   `json:"buy_in"`
+}
+
+// Scored metrics of trends and alerts
+const (
+	ScoreMetricHustle     = "hustle"
+	ScoreMetricEngagement = "engagement"
+	ScoreMetricBuyIn      = "buy_in"
+)
+
+// Kinds of score alerts
+const (
+	ScoreAlertDeclineStreak = "decline_streak"
+	ScoreAlertAverageDrop   = "average_drop"
+	ScoreAlertSharpDrop     = "sharp_drop"
+)
+
+// ScorePoint is one rated event of a trend; RollingAverage covers the
+// trend's window ending at this event
+type ScorePoint struct {
+	EventID        uint      `json:"event_id"`
+	EventType      uint8     `json:"event_type"`
+	Title          string    `json:"title"`
+	StartTime      time.Time `json:"start_time"`
+	Score          int       `json:"score"`
+	RollingAverage float64   `json:"rolling_average"`
+}
+
+// ScoreStreak counts the latest consecutive rises ("up") or declines ("down")
+type ScoreStreak struct {
+	Direction string `json:"direction"`
+	Length    int    `json:"length"`
+}
+
+// ScoreAlert is a significant drop of one metric; From and To are scores or
+// averages depending on the kind
+type ScoreAlert struct {
+	Metric  string  `json:"metric"`
+	Kind    string  `json:"kind"`
+	From    float64 `json:"from"`
+	To      float64 `json:"to"`
+	Message string  `json:"message"`
+}
+
+type ScoreTrend struct {
+	Metric         string       `json:"metric"`
+	Points         []ScorePoint `json:"points"`
+	Latest         *int         `json:"latest"`
+	Average        *float64     `json:"average"`
+	RollingAverage *float64     `json:"rolling_average"`
+	Streak         ScoreStreak  `json:"streak"`
+	Alerts         []ScoreAlert `json:"alerts"`
+}
+
+type PlayerEffortTrend struct {
+	PlayerID     uint       `json:"player_id"`
+	PlayerName   string     `json:"player_name"`
+	JerseyNumber *int       `json:"jersey_number"`
+	Hustle       ScoreTrend `json:"hustle"`
+	Engagement   ScoreTrend `json:"engagement"`
+	BuyIn        ScoreTrend `json:"buy_in"`
+}
+
+// CheckInPlayer is a player whose scores dropped significantly
+type CheckInPlayer struct {
+	PlayerID     uint          `json:"player_id"`
+	PlayerName   string        `json:"player_name"`
+	JerseyNumber *int          `json:"jersey_number"`
+	Alerts       []ScoreAlert  `json:"alerts"`
+	Latest       CheckInScores `json:"latest"`
+}
+
+type CheckInScores struct {
+	Hustle     *int `json:"hustle"`
+	Engagement *int `json:"engagement"`
+	BuyIn      *int `json:"buy_in"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event types (events.type)
+const (
+	EventTypePractice uint8 = 1
+	EventTypeGame     uint8 = 2
+	EventTypeMeeting  uint8 = 3
+	EventTypeOther    uint8 = 4
+)
+
+// Event status (events.status)
+const (
+	EventStatusScheduled uint8 = 1
+	EventStatusCancelled uint8 = 2
+	EventStatusCompleted uint8 = 3
+)
+
+type Event struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	TeamID      uint           `json:"team_id" gorm:"not null;index"`
+	Type        uint8          `json:"type" gorm:"type:tinyint unsigned;not null"`
+	Title       string         `json:"title" gorm:"size:255;not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	StartTime   time.Time      `json:"start_time" gorm:"not null;index"`
+	EndTime     time.Time      `json:"end_time" gorm:"not null"`
+	Location    string         `json:"location" gorm:"size:500"`
+	Opponent    string         `json:"opponent" gorm:"size:255"`
+	IsHomeGame  bool           `json:"is_home_game"`
+	Status      uint8          `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	CreatedBy   uint           `json:"created_by" gorm:"not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type UpdateEventRequest struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	StartTime   *time.Time `json:"start_time"`
+	EndTime     *time.Time `json:"end_time"`
+	Location    *string    `json:"location"`
+	Opponent    *string    `json:"opponent"`
+	IsHomeGame  *bool      `json:"is_home_game"`
+	Status      *uint8     `json:"status" binding:"omitempty,oneof=1 2 3"`
+}
+
+// RSVP status (event_rsvps.status)
+const (
+	RSVPAttending    uint8 = 1
+	RSVPNotAttending uint8 = 2
+	RSVPMaybe        uint8 = 3
+)
+
+type EventRSVP struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	EventID     uint      `json:"event_id" gorm:"not null;uniqueIndex:unique_event_user"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:unique_event_user;index"`
+	Status      uint8     `json:"status" gorm:"type:tinyint unsigned;not null"`
+	Notes       string    `json:"notes" gorm:"type:text"`
+	RespondedAt time.Time `json:"responded_at" gorm:"autoCreateTime"`
+}
+
+func (EventRSVP) TableName() string {
+	return "event_rsvps"
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+)
+
+// Play types (game_plays.play_type)
+const (
+	PlayTwoPointMade    uint8 = 1
+	PlayTwoPointMissed  uint8 = 2
+	PlayThreeMade       uint8 = 3
+	PlayThreeMissed     uint8 = 4
+	PlayFreeThrowMade   uint8 = 5
+	PlayFreeThrowMissed uint8 = 6
+	PlayRebound         uint8 = 7
+	PlayAssist          uint8 = 8
+	PlaySteal           uint8 = 9
+	PlayBlock           uint8 = 10
+	PlayTurnover        uint8 = 11
+	PlayFoul            uint8 = 12
+	PlaySubIn           uint8 = 13
+	PlaySubOut          uint8 = 14
+	PlayOpponentScore   uint8 = 15
+)
+
+// GamePlay is one entry of a game's play-by-play log. Plays are created on
+// the bench devices, possibly offline, and identified by the device's
+// client_id so re-sending them is harmless.
+type GamePlay struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	EventID  uint   `json:"event_id" gorm:"not null;uniqueIndex:unique_event_client;index:idx_event_revision"`
+	ClientID string `json:"client_id" gorm:"size:64;not null;uniqueIndex:unique_event_client"`
+	DeviceID string `json:"device_id" gorm:"size:100;not null"`
+	PlayType uint8  `json:"play_type" gorm:"type:tinyint unsigned;not null"`
+	PlayerID *uint  `json:"player_id" gorm:"index"`
+	Period   uint8  `json:"period" gorm:"type:tinyint unsigned;not null"`
+	// ClockSeconds is the game clock, counting down within the period
+	ClockSeconds int `json:"clock_seconds" gorm:"not null"`
+	// Points is only set for opponent scores
+	Points uint8 `json:"points" gorm:"type:tinyint unsigned;not null;default:0"`
+	Voided bool  `json:"voided" gorm:"not null;default:false"`
+	// DuplicateOf points at the play another device already recorded for the same action
+	DuplicateOf *uint `json:"duplicate_of"`
+	// Version counts the edits of this play; Revision orders all changes of the game for pulls
+	Version         int       `json:"version" gorm:"not null;default:1"`
+	Revision        uint64    `json:"revision" gorm:"not null;index:idx_event_revision"`
+	RecordedBy      uint      `json:"recorded_by" gorm:"not null"`
+	ClientUpdatedAt time.Time `json:"client_updated_at" gorm:"type:datetime(3);not null"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Description reads like "made 3PT by #12 at 4:31 Q2"
+	Description string `json:"description,omitempty" gorm:"-"`
+}
+
+// Counts reports whether the play is part of the game record
+func (p *GamePlay) Counts() bool {
+	return !p.Voided && p.DuplicateOf == nil
+}
+
+// GamePlayInput is a play as queued on a device. BaseVersion is the version
+// the device last saw (0 for a new play); UpdatedAt is the device clock at
+// the time of the change and settles conflicting edits.
+type GamePlayInput struct {
+	ClientID     string    `json:"client_id" binding:"required,max=64"`
+	PlayType     uint8     `json:"play_type" binding:"required,min=1,max=15"`
+	PlayerID     *uint     `json:"player_id"`
+	Period       uint8     `json:"period" binding:"required,min=1,max=10"`
+	ClockSeconds *int      `json:"clock_seconds" binding:"required,min=0,max=3600"`
+	Points       uint8     `json:"points" binding:"omitempty,min=1,max=3"`
+	Voided       bool      `json:"voided"`
+	BaseVersion  int       `json:"base_version" binding:"min=0"`
+	UpdatedAt    time.Time `json:"updated_at" binding:"required"`
+}
+
+type SyncGamePlaysRequest struct {
+	DeviceID string `json:"device_id" binding:"required,max=100"`
+	// Since is the last revision the device pulled
+	Since uint64          `json:"since"`
+	Plays []GamePlayInput `json:"plays" binding:"max=500,dive"`
+}
+
+// Play sync outcomes
+const (
+	PlaySyncCreated   = "created"
+	PlaySyncUpdated   = "updated"
+	PlaySyncUnchanged = "unchanged"
+	PlaySyncDuplicate = "duplicate"
+	PlaySyncConflict  = "conflict"
+	PlaySyncRejected  = "rejected"
+)
+
+// GamePlaySyncResult reports what happened to one pushed play, with the
+// server's copy when there is one
+type GamePlaySyncResult struct {
+	ClientID string    `json:"client_id"`
+	Status   string    `json:"status"`
+	Reason   string    `json:"reason,omitempty"`
+	Play     *GamePlay `json:"play,omitempty"`
+}
+
+type GamePlaySyncResponse struct {
+	Results []GamePlaySyncResult `json:"results"`
+	// Changes are the plays changed after the request's since revision,
+	// including the ones just pushed
+	Changes  []GamePlay `json:"changes"`
+	Revision uint64     `json:"revision"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+)
+
+// Shot types (game_shots.shot_type)
+const (
+	ShotJumpShot uint8 = 1
+	ShotLayup    uint8 = 2
+	ShotDunk     uint8 = 3
+	ShotHook     uint8 = 4
+	ShotTipIn    uint8 = 5
+	ShotFloater  uint8 = 6
+)
+
+// Court geometry in feet on a half court: x runs from sideline to sideline
+// with the basket at 0 (negative is the left side seen from half court), y
+// runs from the baseline (0) to half court (47).
+const (
+	CourtHalfWidth  = 25.0
+	CourtHalfLength = 47.0
+	BasketY         = 5.25
+)
+
+// GameShot is one field goal attempt of a shot chart. Shots are charted
+// alongside the box score; they do not change game_stats. ClientID lets the
+// charting device re-send a shot without recording it twice.
+type GameShot struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	EventID  uint   `json:"event_id" gorm:"not null;uniqueIndex:unique_event_client"`
+	ClientID string `json:"client_id" gorm:"size:64;not null;uniqueIndex:unique_event_client"`
+	// TeamID is the event's team, kept on the shot for season charts
+	TeamID     uint    `json:"team_id" gorm:"not null;index"`
+	PlayerID   uint    `json:"player_id" gorm:"not null;index"`
+	X          float64 `json:"x" gorm:"type:decimal(4,1);not null"`
+	Y          float64 `json:"y" gorm:"type:decimal(4,1);not null"`
+	ShotType   uint8   `json:"shot_type" gorm:"type:tinyint unsigned;not null"`
+	PointValue uint8   `json:"point_value" gorm:"type:tinyint unsigned;not null"`
+	Made       bool    `json:"made" gorm:"not null"`
+	Period     uint8   `json:"period" gorm:"type:tinyint unsigned;not null"`
+	// ClockSeconds is the game clock, counting down within the period
+	ClockSeconds int `json:"clock_seconds" gorm:"not null"`
+	// Zone is derived from the location and point value when the shot is saved
+	Zone       string    `json:"zone" gorm:"size:30;not null;index"`
+	RecordedBy uint      `json:"recorded_by" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GameShotInput is a charted shot; re-sending a client_id corrects the shot
+type GameShotInput struct {
+	ClientID     string   `json:"client_id" binding:"required,max=64"`
+	PlayerID     uint     `json:"player_id" binding:"required"`
+	X            *float64 `json:"x" binding:"required,min=-25,max=25"`
+	Y            *float64 `json:"y" binding:"required,min=0,max=47"`
+	ShotType     uint8    `json:"shot_type" binding:"required,min=1,max=6"`
+	PointValue   uint8    `json:"point_value" binding:"required,oneof=2 3"`
+	Made         bool     `json:"made"`
+	Period       uint8    `json:"period" binding:"required,min=1,max=10"`
+	ClockSeconds *int     `json:"clock_seconds" binding:"required,min=0,max=3600"`
+}
+
+type RecordGameShotsRequest struct {
+	Shots []GameShotInput `json:"shots" binding:"required,min=1,max=200,dive"`
+}
+
+// ShotZoneStat is one court zone of a shot chart. X and Y place the zone's
+// label; Share is the zone's fraction of all attempts.
+type ShotZoneStat struct {
+	Zone          string   `json:"zone"`
+	Label         string   `json:"label"`
+	PointValue    uint8    `json:"point_value"`
+	X             float64  `json:"x"`
+	Y             float64  `json:"y"`
+	Attempts      int      `json:"attempts"`
+	Made          int      `json:"made"`
+	Pct           *float64 `json:"pct"`
+	PointsPerShot *float64 `json:"points_per_shot"`
+	Share         float64  `json:"share"`
+}
+
+// ShotHeatCell is one square of the heat-map grid; X and Y are its center
+type ShotHeatCell struct {
+	X        float64  `json:"x"`
+	Y        float64  `json:"y"`
+	Attempts int      `json:"attempts"`
+	Made     int      `json:"made"`
+	Pct      *float64 `json:"pct"`
+}
+
+type ShotTypeStat struct {
+	ShotType uint8    `json:"shot_type"`
+	Label    string   `json:"label"`
+	Attempts int      `json:"attempts"`
+	Made     int      `json:"made"`
+	Pct      *float64 `json:"pct"`
+}
+
+// ShotChart aggregates the shots of a player or a team. Zones always lists
+// every zone; Cells only the grid squares with attempts.
+type ShotChart struct {
+	TeamID    uint           `json:"team_id,omitempty"`
+	PlayerID  uint           `json:"player_id,omitempty"`
+	SeasonID  uint           `json:"season_id,omitempty"`
+	EventID   uint           `json:"event_id,omitempty"`
+	Games     int            `json:"games"`
+	Attempts  int            `json:"attempts"`
+	Made      int            `json:"made"`
+	Pct       *float64       `json:"pct"`
+	Zones     []ShotZoneStat `json:"zones"`
+	CellSize  float64        `json:"cell_size"`
+	Cells     []ShotHeatCell `json:"cells"`
+	ShotTypes []ShotTypeStat `json:"shot_types"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GameStatCounts are the basketball counting stats of one stat line
+type GameStatCounts struct {
+	Points                 int `json:"points" gorm:"default:0" binding:"min=0"`
+	Rebounds               int `json:"rebounds" gorm:"default:0" binding:"min=0"`
+	Assists                int `json:"assists" gorm:"default:0" binding:"min=0"`
+	Steals                 int `json:"steals" gorm:"default:0" binding:"min=0"`
+	Blocks                 int `json:"blocks" gorm:"default:0" binding:"min=0"`
+	Turnovers              int `json:"turnovers" gorm:"default:0" binding:"min=0"`
+	Fouls                  int `json:"fouls" gorm:"default:0" binding:"min=0"`
+	FieldGoalsMade         int `json:"field_goals_made" gorm:"default:0" binding:"min=0"`
+	FieldGoalsAttempted    int `json:"field_goals_attempted" gorm:"default:0" binding:"min=0"`
+	ThreePointersMade      int `json:"three_pointers_made" gorm:"default:0" binding:"min=0"`
+	ThreePointersAttempted int `json:"three_pointers_attempted" gorm:"default:0" binding:"min=0"`
+	FreeThrowsMade         int `json:"free_throws_made" gorm:"default:0" binding:"min=0"`
+	FreeThrowsAttempted    int `json:"free_throws_attempted" gorm:"default:0" binding:"min=0"`
+}
+
+// Add accumulates another stat line
+func (c *GameStatCounts) Add(o GameStatCounts) {
+	c.Points += o.Points
+	c.Rebounds += o.Rebounds
+	c.Assists += o.Assists
+	c.Steals += o.Steals
+	c.Blocks += o.Blocks
+	c.Turnovers += o.Turnovers
+	c.Fouls += o.Fouls
+	c.FieldGoalsMade += o.FieldGoalsMade
+	c.FieldGoalsAttempted += o.FieldGoalsAttempted
+	c.ThreePointersMade += o.ThreePointersMade
+	c.ThreePointersAttempted += o.ThreePointersAttempted
+	c.FreeThrowsMade += o.FreeThrowsMade
+	c.FreeThrowsAttempted += o.FreeThrowsAttempted
+}
+
+type GameStat struct {
+	ID       uint `json:"id" gorm:"primaryKey"`
+	EventID  uint `json:"event_id" gorm:"not null;uniqueIndex:unique_event_player;index"`
+	PlayerID uint `json:"player_id" gorm:"not null;uniqueIndex:unique_event_player;index"`
+	GameStatCounts
+	MinutesPlayed   *int            `json:"minutes_played"`
+	PlusMinus       *int            `json:"plus_minus"`
+	AdditionalStats json.RawMessage `json:"additional_stats" gorm:"type:json"`
+	Notes           string          `json:"notes" gorm:"type:text"`
+	EnteredBy       uint            `json:"entered_by" gorm:"not null"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// GameStatLine is one player's line of a bulk stat entry, and the snapshot
+// kept in the edit history
+type GameStatLine struct {
+	PlayerID uint `json:"player_id" binding:"required"`
+	GameStatCounts
+	MinutesPlayed *int `json:"minutes_played" binding:"omitempty,min=0"`
+	// PlusMinus is only derived from play-by-play
+	PlusMinus       *int            `json:"plus_minus,omitempty"`
+	AdditionalStats json.RawMessage `json:"additional_stats,omitempty"`
+	Notes           string          `json:"notes"`
+}
+
+type BulkGameStatsRequest struct {
+	Stats []GameStatLine `json:"stats" binding:"required,min=1,max=50,dive"`
+}
+
+// Game stat edit actions (game_stat_edits.action)
+const (
+	GameStatEditCreated uint8 = 1
+	GameStatEditUpdated uint8 = 2
+	GameStatEditDerived uint8 = 3
+)
+
+// GameStatEdit records one change to a stat line with the values before and after
+type GameStatEdit struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	GameStatID uint            `json:"game_stat_id" gorm:"not null;index"`
+	EventID    uint            `json:"event_id" gorm:"not null;index"`
+	PlayerID   uint            `json:"player_id" gorm:"not null"`
+	Action     uint8           `json:"action" gorm:"type:tinyint unsigned;not null"`
+	Before     json.RawMessage `json:"before" gorm:"column:before_values;type:json"`
+	After      json.RawMessage `json:"after" gorm:"column:after_values;type:json"`
+	EditedBy   uint            `json:"edited_by" gorm:"not null"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ShootingSplits are nil when there were no attempts
+type ShootingSplits struct {
+	FieldGoalPct  *float64 `json:"field_goal_pct"`
+	ThreePointPct *float64 `json:"three_point_pct"`
+	FreeThrowPct  *float64 `json:"free_throw_pct"`
+}
+
+// BoxScoreLine is a player's stat line with their name and jersey
+type BoxScoreLine struct {
+	GameStat
+	PlayerName   string `json:"player_name"`
+	JerseyNumber *int   `json:"jersey_number"`
+	ShootingSplits
+}
+
+type BoxScoreTotals struct {
+	GameStatCounts
+	MinutesPlayed int `json:"minutes_played"`
+	ShootingSplits
+}
+
+type BoxScore struct {
+	EventID uint           `json:"event_id"`
+	TeamID  uint           `json:"team_id"`
+	Players []BoxScoreLine `json:"players"`
+	Totals  BoxScoreTotals `json:"totals"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// Leadership note visibility (leadership_notes.visibility). Players and
+// parents only see notes shared with them specifically: a note shared with
+// parents is not shown to the player, and the other way around.
+const (
+	NoteVisibilityCoach   uint8 = 1
+	NoteVisibilityPlayer  uint8 = 2
+	NoteVisibilityParents uint8 = 3
+)
+
+// LeadershipNote is a coach's scored comment about a player on one of their
+// teams, optionally about a specific event
+type LeadershipNote struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	PlayerID   uint      `json:"player_id" gorm:"not null;index"`
+	TeamID     uint      `json:"team_id" gorm:"not null;index"`
+	CoachID    uint      `json:"coach_id" gorm:"not null;index"`
+	Score      int       `json:"score" gorm:"not null"`
+	Comment    string    `json:"comment" gorm:"type:text;not null"`
+	EventID    *uint     `json:"event_id" gorm:"index"`
+	Visibility uint8     `json:"visibility" gorm:"type:tinyint unsigned;not null;default:1"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	CoachName string `json:"coach_name,omitempty" gorm:"-"`
+}
+
+type CreateLeadershipNoteRequest struct {
+	TeamID     uint   `json:"team_id" binding:"required"`
+	Score      int    `json:"score" binding:"required,min=1,max=10"`
+	Comment    string `json:"comment" binding:"required,max=5000"`
+	EventID    *uint  `json:"event_id"`
+	Visibility uint8  `json:"visibility" binding:"omitempty,oneof=1 2 3"`
+}
+
+type UpdateLeadershipNoteRequest struct {
+	Score      *int    `json:"score" binding:"omitempty,min=1,max=10"`
+	Comment    *string `json:"comment" binding:"omitempty,min=1,max=5000"`
+	Visibility *uint8  `json:"visibility" binding:"omitempty,oneof=1 2 3"`
+}
+
+// Player timeline entry types
+const (
+	TimelineTypeNote       = "leadership_note"
+	TimelineTypeEffort     = "effort"
+	TimelineTypeAttendance = "attendance"
+)
+
+// Attendance of a timeline entry, from the player's RSVP to a past event
+const (
+	AttendanceAttending    = "attending"
+	AttendanceNotAttending = "not_attending"
+	AttendanceMaybe        = "maybe"
+	AttendanceNoResponse   = "no_response"
+)
+
+// TimelineEffort holds the scores of one event; either part may be missing
+type TimelineEffort struct {
+	HustleScore     *int   `json:"hustle_score"`
+	EngagementScore *int   `json:"engagement_score"`
+	BuyInScore      *int   `json:"buy_in_score"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+type TimelineAttendance struct {
+	Status string `json:"status"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+// TimelineEntry is one item of a player's timeline; exactly one of Note,
+// Effort and Attendance is set, matching Type
+type TimelineEntry struct {
+	Type       string              `json:"type"`
+	OccurredAt time.Time           `json:"occurred_at"`
+	TeamID     uint                `json:"team_id"`
+	EventID    *uint               `json:"event_id"`
+	EventType  uint8               `json:"event_type,omitempty"`
+	EventTitle string              `json:"event_title,omitempty"`
+	Note       *LeadershipNote     `json:"note,omitempty"`
+	Effort     *TimelineEffort     `json:"effort,omitempty"`
+	Attendance *TimelineAttendance `json:"attendance,omitempty"`
+}
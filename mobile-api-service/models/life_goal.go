@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+)
+
+// Life goal status (life_goals.status)
+const (
+	GoalStatusActive    uint8 = 1
+	GoalStatusCompleted uint8 = 2
+	GoalStatusPaused    uint8 = 3
+)
+
+// LifeGoal is a personal goal a player sets and tracks; coaches follow and
+// comment on it. ReminderSentAt is when the player was last reminded of an
+// approaching target date.
+type LifeGoal struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	PlayerID       uint       `json:"player_id" gorm:"not null;index"`
+	Title          string     `json:"title" gorm:"size:255;not null"`
+	Description    string     `json:"description" gorm:"type:text"`
+	Status         uint8      `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	TargetDate     *time.Time `json:"target_date" gorm:"type:date"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	ReminderSentAt *time.Time `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Milestones []GoalMilestone `json:"milestones" gorm:"foreignKey:GoalID"`
+	// Progress is the percentage of the latest check-in
+	Progress      *int       `json:"progress" gorm:"-"`
+	LastCheckInAt *time.Time `json:"last_check_in_at" gorm:"-"`
+	// ReadyToComplete prompts the player to mark the goal complete once every milestone is done
+	ReadyToComplete bool `json:"ready_to_complete" gorm:"-"`
+}
+
+type GoalMilestone struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	GoalID      uint       `json:"goal_id" gorm:"not null;index"`
+	Title       string     `json:"title" gorm:"size:255;not null"`
+	SortOrder   int        `json:"sort_order" gorm:"not null;default:0"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// GoalCheckIn is a player's progress update on a goal
+type GoalCheckIn struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	GoalID    uint      `json:"goal_id" gorm:"not null;index"`
+	Progress  int       `json:"progress" gorm:"not null"`
+	Comment   string    `json:"comment" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// GoalComment is a coach's comment on a player's goal
+type GoalComment struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	GoalID    uint      `json:"goal_id" gorm:"not null;index"`
+	CoachID   uint      `json:"coach_id" gorm:"not null"`
+	Comment   string    `json:"comment" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+
+	CoachName string `json:"coach_name,omitempty" gorm:"-"`
+}
+
+// TargetDate fields are dates (YYYY-MM-DD); an empty string clears the date on update
+type CreateLifeGoalRequest struct {
+	Title       string   `json:"title" binding:"required,max=255"`
+	Description string   `json:"description"`
+	TargetDate  string   `json:"target_date" binding:"omitempty,datetime=2006-01-02"`
+	Milestones  []string `json:"milestones" binding:"max=50,dive,required,max=255"`
+}
+
+type UpdateLifeGoalRequest struct {
+	Title       *string `json:"title" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+	TargetDate  *string `json:"target_date" binding:"omitempty,datetime=2006-01-02"`
+	Status      *uint8  `json:"status" binding:"omitempty,oneof=1 2 3"`
+}
+
+type CreateMilestoneRequest struct {
+	Title     string `json:"title" binding:"required,max=255"`
+	SortOrder *int   `json:"sort_order"`
+}
+
+type UpdateMilestoneRequest struct {
+	Title     *string `json:"title" binding:"omitempty,min=1,max=255"`
+	SortOrder *int    `json:"sort_order"`
+	Completed *bool   `json:"completed"`
+}
+
+type CreateGoalCheckInRequest struct {
+	Progress *int   `json:"progress" binding:"required,min=0,max=100"`
+	Comment  string `json:"comment" binding:"max=2000"`
+}
+
+type CreateGoalCommentRequest struct {
+	Comment string `json:"comment" binding:"required,max=2000"`
+}
+
+// LifeGoalDetail is a goal with its check-in history and coach comments
+type LifeGoalDetail struct {
+	LifeGoal
+	CheckIns []GoalCheckIn `json:"check_ins"`
+	Comments []GoalComment `json:"comments"`
+}
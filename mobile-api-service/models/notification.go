@@ -0,0 +1,18 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Notification struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	UserID    uint            `json:"user_id" gorm:"not null;index"`
+	Type      string          `json:"type" gorm:"size:100;not null;index"`
+	Title     string          `json:"title" gorm:"size:255;not null"`
+	Body      string          `json:"body" gorm:"type:text;not null"`
+	Data      json.RawMessage `json:"data" gorm:"type:json"`
+	ReadAt    *time.Time      `json:"read_at" gorm:"index"`
+	SentAt    *time.Time      `json:"sent_at"`
+	CreatedAt time.Time       `json:"created_at" gorm:"index"`
+}
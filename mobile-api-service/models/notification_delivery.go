@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NotificationChannel values (notification_deliveries.channel)
+type NotificationChannel uint8
+
+const (
+	ChannelInApp NotificationChannel = 1
+	ChannelPush  NotificationChannel = 2
+	ChannelEmail NotificationChannel = 3
+	ChannelSMS   NotificationChannel = 4
+)
+
+func (c NotificationChannel) String() string {
+	switch c {
+	case ChannelInApp:
+		return "in_app"
+	case ChannelPush:
+		return "push"
+	case ChannelEmail:
+		return "email"
+	case ChannelSMS:
+		return "sms"
+	}
+	return "unknown"
+}
+
+// NotificationCategory is the preference flag a notification type is gated by
+type NotificationCategory string
+
+const (
+	CategoryGameDay        NotificationCategory = "game_day"
+	CategoryScheduleChange NotificationCategory = "schedule_change"
+	CategoryAnnouncement   NotificationCategory = "announcement"
+	CategoryMedia          NotificationCategory = "media"
+)
+
+// Notification types seeded in notification_templates
+const (
+	NotificationTypeGameDayPublished = "GameDayPublished"
+	NotificationTypeAnnouncement     = "Announcement"
+	NotificationTypeScheduleChange   = "ScheduleChange"
+	NotificationTypeVideoTagged      = "VideoTagged"
+	NotificationTypeDigest           = "Digest"
+	NotificationTypeEligibility      = "EligibilityChange"
+	NotificationTypeGoalReminder     = "GoalReminder"
+	NotificationTypeGoalReady        = "GoalReadyToComplete"
+	NotificationTypeGoalComment      = "GoalComment"
+	NotificationTypeProfileAccess    = "ProfileAccessRequested"
+	NotificationTypeProfileDecision  = "ProfileAccessDecided"
+)
+
+// CategoryForType maps a notification type to its preference category.
+// Types without a category are always delivered.
+func CategoryForType(notificationType string) NotificationCategory {
+	switch notificationType {
+	case NotificationTypeGameDayPublished:
+		return CategoryGameDay
+	case NotificationTypeScheduleChange:
+		return CategoryScheduleChange
+	case NotificationTypeAnnouncement:
+		return CategoryAnnouncement
+	case NotificationTypeVideoTagged:
+		return CategoryMedia
+	}
+	return ""
+}
+
+// Delivery attempt status (notification_deliveries.status)
+const (
+	DeliveryStatusSent   uint8 = 1
+	DeliveryStatusFailed uint8 = 2
+)
+
+// NotificationDelivery records a single attempt to deliver a notification over one channel
+type NotificationDelivery struct {
+	ID             uint                `json:"id" gorm:"primaryKey"`
+	NotificationID uint                `json:"notification_id" gorm:"not null;index"`
+	UserID         uint                `json:"user_id" gorm:"not null;index"`
+	Channel        NotificationChannel `json:"channel" gorm:"type:tinyint unsigned;not null"`
+	Attempt        int                 `json:"attempt" gorm:"not null"`
+	Status         uint8               `json:"status" gorm:"type:tinyint unsigned;not null;index"`
+	Error          string              `json:"error" gorm:"type:text"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// NotificationIntent describes a notification to fan out to a set of users
+type NotificationIntent struct {
+	UserIDs   []uint            `json:"user_ids" binding:"required,min=1"`
+	Type      string            `json:"type" binding:"required"`
+	Locale    string            `json:"locale"`
+	Variables map[string]string `json:"variables"`
+	Data      json.RawMessage   `json:"data"`
+	// Urgent notifications bypass quiet hours and digests
+	Urgent bool `json:"urgent"`
+}
+
+// IsUrgent reports whether the intent must ping immediately. Game
+// cancellations are always urgent.
+func (i *NotificationIntent) IsUrgent() bool {
+	if i.Urgent {
+		return true
+	}
+	return i.Type == NotificationTypeScheduleChange && i.Variables["change_type"] == "cancelled"
+}
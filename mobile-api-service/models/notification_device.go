@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Device platforms (notification_devices.platform)
+const (
+	PlatformIOS     uint8 = 1
+	PlatformAndroid uint8 = 2
+	PlatformWeb     uint8 = 3
+)
+
+type NotificationDevice struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	DeviceToken string    `json:"device_token" gorm:"size:500;not null;index"`
+	Platform    uint8     `json:"platform" gorm:"type:tinyint unsigned;not null"`
+	DeviceID    string    `json:"device_id" gorm:"size:255"`
+	IsActive    bool      `json:"is_active" gorm:"index"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RegisterDeviceRequest struct {
+	DeviceToken string `json:"device_token" binding:"required,max=500"`
+	Platform    uint8  `json:"platform" binding:"required,oneof=1 2 3"`
+	DeviceID    string `json:"device_id" binding:"max=255"`
+}
+
+type RefreshDeviceRequest struct {
+	DeviceToken string `json:"device_token" binding:"required,max=500"`
+}
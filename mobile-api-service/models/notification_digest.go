@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationDigestItem is a notification whose external pings are held for
+// the recipient's quiet hours or daily digest
+type NotificationDigestItem struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"not null;index"`
+	NotificationID uint       `json:"notification_id" gorm:"not null"`
+	ReleaseAt      time.Time  `json:"release_at" gorm:"not null;index"`
+	DeliveredAt    *time.Time `json:"delivered_at" gorm:"index"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+type NotificationPreference struct {
+	ID                          uint      `json:"id" gorm:"primaryKey"`
+	UserID                      uint      `json:"user_id" gorm:"uniqueIndex;not null"`
+	PushEnabled                 bool      `json:"push_enabled"`
+	EmailEnabled                bool      `json:"email_enabled"`
+	SMSEnabled                  bool      `json:"sms_enabled" gorm:"column:sms_enabled"`
+	GameDayNotifications        bool      `json:"game_day_notifications"`
+	ScheduleChangeNotifications bool      `json:"schedule_change_notifications"`
+	AnnouncementNotifications   bool      `json:"announcement_notifications"`
+	MediaNotifications          bool      `json:"media_notifications"`
+	TimeZone                    string    `json:"time_zone" gorm:"size:64"`
+	QuietHoursEnabled           bool      `json:"quiet_hours_enabled"`
+	QuietHoursStart             string    `json:"quiet_hours_start" gorm:"size:5"`
+	QuietHoursEnd               string    `json:"quiet_hours_end" gorm:"size:5"`
+	DigestEnabled               bool      `json:"digest_enabled"`
+	DigestTime                  string    `json:"digest_time" gorm:"size:5"`
+	CreatedAt                   time.Time `json:"created_at"`
+	UpdatedAt                   time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreference mirrors the column defaults of notification_preferences
+func DefaultNotificationPreference(userID uint) NotificationPreference {
+	return NotificationPreference{
+		UserID:                      userID,
+		PushEnabled:                 true,
+		EmailEnabled:                false,
+		SMSEnabled:                  false,
+		GameDayNotifications:        true,
+		ScheduleChangeNotifications: true,
+		AnnouncementNotifications:   true,
+		MediaNotifications:          true,
+		TimeZone:                    "UTC",
+		QuietHoursEnabled:           false,
+		QuietHoursStart:             "22:00",
+		QuietHoursEnd:               "07:00",
+		DigestEnabled:               false,
+		DigestTime:                  "07:00",
+	}
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	PushEnabled                 *bool   `json:"push_enabled"`
+	EmailEnabled                *bool   `json:"email_enabled"`
+	SMSEnabled                  *bool   `json:"sms_enabled"`
+	GameDayNotifications        *bool   `json:"game_day_notifications"`
+	ScheduleChangeNotifications *bool   `json:"schedule_change_notifications"`
+	AnnouncementNotifications   *bool   `json:"announcement_notifications"`
+	MediaNotifications          *bool   `json:"media_notifications"`
+	TimeZone                    *string `json:"time_zone"`
+	QuietHoursEnabled           *bool   `json:"quiet_hours_enabled"`
+	QuietHoursStart             *string `json:"quiet_hours_start"`
+	QuietHoursEnd               *string `json:"quiet_hours_end"`
+	DigestEnabled               *bool   `json:"digest_enabled"`
+	DigestTime                  *string `json:"digest_time"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// DefaultLocale is the last entry of every template locale fallback chain
+const DefaultLocale = "en"
+
+type NotificationTemplate struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"size:255;uniqueIndex;not null"`
+	Type         string    `json:"type" gorm:"size:100;not null;index:idx_type_locale"`
+	Locale       string    `json:"locale" gorm:"size:20;not null;default:en;index:idx_type_locale"`
+	Subject      string    `json:"subject" gorm:"size:255"`
+	BodyTemplate string    `json:"body_template" gorm:"type:text;not null"`
+	IsActive     bool      `json:"is_active" gorm:"default:true;index"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type PreviewNotificationTemplateRequest struct {
+	Variables map[string]string `json:"variables"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+)
+
+// Profile permission types (profile_permissions.permission_type). Coach,
+// college and recruiter grants go to one recruiter account of that type;
+// public grants let every recruiter account see the profile.
+const (
+	ProfilePermissionPublic    uint8 = 1
+	ProfilePermissionCoach     uint8 = 2
+	ProfilePermissionCollege   uint8 = 3
+	ProfilePermissionRecruiter uint8 = 4
+)
+
+// Profile permission status (profile_permissions.status)
+const (
+	ProfilePermissionPending  uint8 = 1
+	ProfilePermissionApproved uint8 = 2
+	ProfilePermissionDenied   uint8 = 3
+	ProfilePermissionRevoked  uint8 = 4
+)
+
+// RecruiterAccount describes a user with the Recruiter role. AccountType is
+// the profile permission type their access requests ask for: coach (club,
+// prep and academy coaches), college or recruiter (scouting services).
+type RecruiterAccount struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	UserID           uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	AccountType      uint8     `json:"account_type" gorm:"type:tinyint unsigned;not null;index"`
+	OrganizationName string    `json:"organization_name" gorm:"size:255;not null"`
+	Title            string    `json:"title" gorm:"size:255"`
+	Division         string    `json:"division" gorm:"size:100"`
+	Website          string    `json:"website" gorm:"size:500"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type SaveRecruiterAccountRequest struct {
+	AccountType      uint8  `json:"account_type" binding:"required,oneof=2 3 4"`
+	OrganizationName string `json:"organization_name" binding:"required,max=255"`
+	Title            string `json:"title" binding:"max=255"`
+	Division         string `json:"division" binding:"max=100"`
+	Website          string `json:"website" binding:"omitempty,url,max=500"`
+}
+
+// ProfilePermission is an access request of a recruiter account or a grant
+// made by the player's side. GrantedTo is nil for public grants.
+type ProfilePermission struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	ProfileID      uint       `json:"profile_id" gorm:"not null;uniqueIndex:unique_profile_grantee"`
+	GrantedTo      *uint      `json:"granted_to" gorm:"uniqueIndex:unique_profile_grantee;index"`
+	PermissionType uint8      `json:"permission_type" gorm:"type:tinyint unsigned;not null"`
+	Status         uint8      `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	Message        string     `json:"message" gorm:"size:500"`
+	GrantedBy      *uint      `json:"granted_by"`
+	DecidedAt      *time.Time `json:"decided_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Filled on listings
+	PlayerName       string `json:"player_name,omitempty" gorm:"-"`
+	GranteeName      string `json:"grantee_name,omitempty" gorm:"-"`
+	OrganizationName string `json:"organization_name,omitempty" gorm:"-"`
+	Title            string `json:"title,omitempty" gorm:"-"`
+}
+
+type RequestProfileAccessRequest struct {
+	Message string `json:"message" binding:"max=500"`
+}
+
+// GrantProfilePermissionRequest grants access without a request; UserID is
+// the recruiter account's user and is required unless the grant is public
+type GrantProfilePermissionRequest struct {
+	PermissionType uint8 `json:"permission_type" binding:"required,oneof=1 2 3 4"`
+	UserID         *uint `json:"user_id"`
+}
+
+type DecideProfileAccessRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve deny"`
+}
+
+// DirectoryFilter narrows the recruiter directory; zero values do not filter
+type DirectoryFilter struct {
+	Sport          string
+	GraduationYear int
+	Position       string
+	MinHeight      int
+	MaxHeight      int
+}
+
+// DirectoryProfile is one row of the recruiter directory
+type DirectoryProfile struct {
+	ProfileID       uint   `json:"profile_id"`
+	PlayerID        uint   `json:"player_id"`
+	PlayerName      string `json:"player_name"`
+	Sport           string `json:"sport"`
+	Position        string `json:"position"`
+	GraduationYear  *int   `json:"graduation_year"`
+	HeightInches    *int   `json:"height_inches"`
+	Weight          *int   `json:"weight"`
+	ProfilePhotoURL string `json:"profile_photo_url"`
+}
+
+// RecruiterProfileView logs one time a recruiter account opened a profile
+type RecruiterProfileView struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProfileID   uint      `json:"profile_id" gorm:"not null;index:idx_profile_viewed"`
+	RecruiterID uint      `json:"recruiter_id" gorm:"not null;index"`
+	ViewedAt    time.Time `json:"viewed_at" gorm:"not null;index:idx_profile_viewed"`
+
+	// Filled on listings
+	RecruiterName    string `json:"recruiter_name,omitempty" gorm:"-"`
+	AccountType      uint8  `json:"account_type,omitempty" gorm:"-"`
+	OrganizationName string `json:"organization_name,omitempty" gorm:"-"`
+	Title            string `json:"title,omitempty" gorm:"-"`
+}
+
+// RecruiterViewSummary counts the views of one recruiter on a profile
+type RecruiterViewSummary struct {
+	RecruiterID      uint      `json:"recruiter_id"`
+	RecruiterName    string    `json:"recruiter_name"`
+	OrganizationName string    `json:"organization_name"`
+	Views            int       `json:"views"`
+	LastViewedAt     time.Time `json:"last_viewed_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProfileShare is a link that shows a recruiting profile without signing in
+type ProfileShare struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ProfileID  uint       `json:"profile_id" gorm:"not null;index"`
+	ShareToken string     `json:"share_token" gorm:"size:255;not null;uniqueIndex"`
+	Label      string     `json:"label" gorm:"size:255"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	IsActive   bool       `json:"is_active" gorm:"not null;default:true;index"`
+	ViewCount  int        `json:"view_count" gorm:"not null;default:0"`
+	CreatedBy  uint       `json:"created_by" gorm:"not null"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	PageURL string `json:"page_url" gorm:"-"`
+	JSONURL string `json:"json_url" gorm:"-"`
+}
+
+type CreateProfileShareRequest struct {
+	Label     string     `json:"label" binding:"max=255"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateProfileShareRequest toggles a share or changes its expiry;
+// never_expires removes the expiry
+type UpdateProfileShareRequest struct {
+	Label        *string    `json:"label" binding:"omitempty,max=255"`
+	IsActive     *bool      `json:"is_active"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	NeverExpires bool       `json:"never_expires"`
+}
+
+// ProfileView is one counted view of a share; refreshes by the same visitor
+// within the dedupe window are not recorded
+type ProfileView struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ShareID      uint      `json:"share_id" gorm:"not null;index:idx_share_visitor"`
+	VisitorHash  string    `json:"-" gorm:"size:64;not null;index:idx_share_visitor"`
+	Referrer     string    `json:"referrer" gorm:"size:500"`
+	ReferrerHost string    `json:"referrer_host" gorm:"size:255;index"`
+	ViewedAt     time.Time `json:"viewed_at" gorm:"not null;index"`
+}
+
+// ProfileReferrer summarizes the views that came from one site
+type ProfileReferrer struct {
+	ReferrerHost string    `json:"referrer_host"`
+	Views        int       `json:"views"`
+	LastViewedAt time.Time `json:"last_viewed_at"`
+}
+
+// PublicRecruitingProfile is what a share link and recruiter accounts see:
+// the profile without the date of birth and editing details. Recruiters use
+// profile_id to request access.
+type PublicRecruitingProfile struct {
+	ProfileID       uint             `json:"profile_id"`
+	PlayerName      string           `json:"player_name"`
+	Sport           string           `json:"sport"`
+	Bio             string           `json:"bio"`
+	HeightInches    *int             `json:"height_inches"`
+	Weight          *int             `json:"weight"`
+	GraduationYear  *int             `json:"graduation_year"`
+	Position        string           `json:"position"`
+	AdditionalInfo  json.RawMessage  `json:"additional_info"`
+	ProfilePhotoURL string           `json:"profile_photo_url"`
+	SeasonStats     []SeasonStatLine `json:"season_stats"`
+	Highlights      []PlayerClip     `json:"highlights"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
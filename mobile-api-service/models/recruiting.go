@@ -0,0 +1,99 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RecruitingProfile is the page a player shows to college coaches and
+// recruiters. AdditionalInfo holds the measurables of the profile's sport.
+type RecruitingProfile struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	PlayerID       uint   `json:"player_id" gorm:"not null;uniqueIndex"`
+	Sport          string `json:"sport" gorm:"size:50;not null"`
+	Bio            string `json:"bio" gorm:"type:text"`
+	HeightInches   *int   `json:"height_inches"`
+	Weight         *int   `json:"weight"`
+	GraduationYear *int   `json:"graduation_year" gorm:"index"`
+	Position       string `json:"position" gorm:"size:100;index"`
+	// DateOfBirth decides whether parents may still edit the profile; only
+	// parents, coaches and OrgAdmins set it
+	DateOfBirth      *time.Time      `json:"date_of_birth" gorm:"type:date"`
+	AdditionalInfo   json.RawMessage `json:"additional_info" gorm:"type:json"`
+	ProfilePhotoURL  string          `json:"profile_photo_url" gorm:"size:500"`
+	EmbedSeasonStats bool            `json:"embed_season_stats" gorm:"not null;default:true"`
+	IsActive         bool            `json:"is_active" gorm:"not null;default:true;index"`
+	UpdatedBy        uint            `json:"updated_by"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// SaveRecruitingProfileRequest creates the profile or changes the given
+// fields; sport is required when the profile is created
+type SaveRecruitingProfileRequest struct {
+	Sport            *string         `json:"sport" binding:"omitempty,min=1,max=50"`
+	Bio              *string         `json:"bio" binding:"omitempty,max=5000"`
+	HeightInches     *int            `json:"height_inches" binding:"omitempty,min=36,max=96"`
+	Weight           *int            `json:"weight" binding:"omitempty,min=50,max=500"`
+	GraduationYear   *int            `json:"graduation_year" binding:"omitempty,min=2000,max=2100"`
+	Position         *string         `json:"position" binding:"omitempty,max=100"`
+	DateOfBirth      *string         `json:"date_of_birth" binding:"omitempty,datetime=2006-01-02"`
+	AdditionalInfo   json.RawMessage `json:"additional_info"`
+	ProfilePhotoURL  *string         `json:"profile_photo_url" binding:"omitempty,url,max=500"`
+	EmbedSeasonStats *bool           `json:"embed_season_stats"`
+	IsActive         *bool           `json:"is_active"`
+}
+
+// ProfileHighlight is a tagged clip the player chose to show on their profile
+type ProfileHighlight struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProfileID  uint      `json:"profile_id" gorm:"not null;uniqueIndex:unique_profile_tag"`
+	VideoTagID uint      `json:"video_tag_id" gorm:"not null;uniqueIndex:unique_profile_tag;index"`
+	SortOrder  int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SetDateOfBirthRequest records the player's date of birth
+type SetDateOfBirthRequest struct {
+	DateOfBirth string `json:"date_of_birth" binding:"required,datetime=2006-01-02"`
+}
+
+// SetProfileHighlightsRequest replaces the highlights, in display order
+type SetProfileHighlightsRequest struct {
+	VideoTagIDs []uint `json:"video_tag_ids" binding:"max=20"`
+}
+
+// RecruitingProfileView is a profile with the player's name, their season
+// stat lines in the profile's sport when embedded, and the highlight clips
+type RecruitingProfileView struct {
+	RecruitingProfile
+	PlayerName  string           `json:"player_name"`
+	SeasonStats []SeasonStatLine `json:"season_stats"`
+	Highlights  []PlayerClip     `json:"highlights"`
+}
+
+// Recruiting field types besides the numeric and boolean stat field types
+const (
+	ProfileFieldText   = "text"
+	ProfileFieldChoice = "choice"
+)
+
+// ProfileField is one entry a sport's recruiting profiles may carry in
+// additional_info, such as wingspan or vertical leap
+type ProfileField struct {
+	Key       string   `json:"key"`
+	Label     string   `json:"label"`
+	Type      string   `json:"type"`
+	Unit      string   `json:"unit,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Options   []string `json:"options,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+}
+
+// SportProfileSchema lists the additional_info fields of a sport
+type SportProfileSchema struct {
+	Sport  string         `json:"sport"`
+	Label  string         `json:"label"`
+	Fields []ProfileField `json:"fields"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+// ReportAttendance counts the player's RSVPs to the team's past events in
+// the report's range; Rate is the attending share, nil without events
+type ReportAttendance struct {
+	Events       int      `json:"events"`
+	Attending    int      `json:"attending"`
+	NotAttending int      `json:"not_attending"`
+	Maybe        int      `json:"maybe"`
+	NoResponse   int      `json:"no_response"`
+	Rate         *float64 `json:"rate"`
+}
+
+// ReportCard gathers a player's development over a date range on one team.
+// Leadership notes are limited to the ones shared with the player or parents.
+type ReportCard struct {
+	PlayerID     uint               `json:"player_id"`
+	PlayerName   string             `json:"player_name"`
+	JerseyNumber *int               `json:"jersey_number"`
+	Team         Team               `json:"team"`
+	Organization Organization       `json:"organization"`
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	GeneratedAt  time.Time          `json:"generated_at"`
+	Stats        *StatSummary       `json:"stats"`
+	Hustle       ScoreTrend         `json:"hustle"`
+	Engagement   ScoreTrend         `json:"engagement"`
+	BuyIn        ScoreTrend         `json:"buy_in"`
+	Attendance   ReportAttendance   `json:"attendance"`
+	Notes        []LeadershipNote   `json:"notes"`
+	Academics    []AcademicEntry    `json:"academics"`
+	Eligibility  *PlayerEligibility `json:"eligibility"`
+	Goals        []LifeGoal         `json:"goals"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Role names carried in access tokens (user_roles.role: 1=SuperAdmin, 2=OrgAdmin,
+// 3=Coach, 4=AssistantCoach, 5=Player, 6=Parent, 7=Recruiter)
+const (
+	RoleSuperAdmin     = "SuperAdmin"
+	RoleOrgAdmin       = "OrgAdmin"
+	RoleCoach          = "Coach"
+	RoleAssistantCoach = "AssistantCoach"
+	RolePlayer         = "Player"
+	RoleParent         = "Parent"
+	RoleRecruiter      = "Recruiter"
+)
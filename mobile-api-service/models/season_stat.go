@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PlayerSeasonStat is the materialized total of a player's game_stats on one
+// team, refreshed whenever a game of that team is saved
+type PlayerSeasonStat struct {
+	ID       uint `json:"-" gorm:"primaryKey"`
+	PlayerID uint `json:"player_id" gorm:"not null;uniqueIndex:unique_player_team"`
+	TeamID   uint `json:"team_id" gorm:"not null;uniqueIndex:unique_player_team;index"`
+	SeasonID uint `json:"season_id" gorm:"not null;index"`
+	// Sport is the team's normalized sport_type
+	Sport       string `json:"sport" gorm:"size:50"`
+	GamesPlayed int    `json:"games_played" gorm:"not null;default:0"`
+	GameStatCounts
+	MinutesPlayed int `json:"minutes_played" gorm:"not null;default:0"`
+	// MinutesGames and PlusMinusGames count the games that recorded them
+	MinutesGames   int `json:"-" gorm:"not null;default:0"`
+	PlusMinus      int `json:"plus_minus" gorm:"not null;default:0"`
+	PlusMinusGames int `json:"-" gorm:"not null;default:0"`
+	// AdditionalTotals sums the sport's additional_stats fields
+	AdditionalTotals json.RawMessage `json:"-" gorm:"type:json"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// StatAverages are per-game averages
+type StatAverages struct {
+	Points        float64 `json:"points"`
+	Rebounds      float64 `json:"rebounds"`
+	Assists       float64 `json:"assists"`
+	Steals        float64 `json:"steals"`
+	Blocks        float64 `json:"blocks"`
+	Turnovers     float64 `json:"turnovers"`
+	Fouls         float64 `json:"fouls"`
+	MinutesPlayed float64 `json:"minutes_played"`
+}
+
+type Per36Stats struct {
+	Points    float64 `json:"points"`
+	Rebounds  float64 `json:"rebounds"`
+	Assists   float64 `json:"assists"`
+	Steals    float64 `json:"steals"`
+	Blocks    float64 `json:"blocks"`
+	Turnovers float64 `json:"turnovers"`
+}
+
+// AdvancedStats are nil when their inputs are missing: per-36 needs minutes in
+// every game and plus/minus needs play-by-play
+type AdvancedStats struct {
+	ShootingSplits
+	EffectiveFieldGoalPct *float64    `json:"effective_field_goal_pct"`
+	TrueShootingPct       *float64    `json:"true_shooting_pct"`
+	AssistTurnoverRatio   *float64    `json:"assist_turnover_ratio"`
+	Per36                 *Per36Stats `json:"per_36"`
+	PlusMinus             *int        `json:"plus_minus"`
+	PlusMinusPerGame      *float64    `json:"plus_minus_per_game"`
+}
+
+type StatSummary struct {
+	GamesPlayed   int            `json:"games_played"`
+	Totals        GameStatCounts `json:"totals"`
+	MinutesPlayed int            `json:"minutes_played"`
+	Averages      StatAverages   `json:"averages"`
+	Advanced      AdvancedStats  `json:"advanced"`
+	// SportStats holds the fields and metrics of the sport's stat definition
+	SportStats *SportStatSummary `json:"sport_stats,omitempty"`
+}
+
+type SeasonStatLine struct {
+	TeamID   uint   `json:"team_id"`
+	TeamName string `json:"team_name"`
+	SeasonID uint   `json:"season_id"`
+	Sport    string `json:"sport"`
+	StatSummary
+}
+
+// CareerStatLine totals a player's seasons in one sport
+type CareerStatLine struct {
+	Sport string `json:"sport"`
+	StatSummary
+}
+
+// PlayerGameLine is one game of a player's log
+type PlayerGameLine struct {
+	GameStat
+	TeamID    uint      `json:"team_id"`
+	Title     string    `json:"title"`
+	Opponent  string    `json:"opponent"`
+	StartTime time.Time `json:"start_time"`
+	ShootingSplits
+}
+
+type PlayerStats struct {
+	PlayerID uint             `json:"player_id"`
+	Seasons  []SeasonStatLine `json:"seasons"`
+	Careers  []CareerStatLine `json:"careers"`
+	Games    []PlayerGameLine `json:"games"`
+	// GamesTotal is the number of games matching the filter, for paging Games
+	GamesTotal int64 `json:"games_total"`
+}
+
+// TeamGameLine is a team's total for one game; OpponentPoints is only known
+// for games tracked play by play
+type TeamGameLine struct {
+	EventID        uint           `json:"event_id"`
+	Title          string         `json:"title"`
+	Opponent       string         `json:"opponent"`
+	StartTime      time.Time      `json:"start_time"`
+	Totals         GameStatCounts `json:"totals"`
+	OpponentPoints *int           `json:"opponent_points"`
+	ShootingSplits
+	SportTotals map[string]float64 `json:"sport_totals,omitempty"`
+}
+
+type TeamPlayerStatLine struct {
+	PlayerID     uint   `json:"player_id"`
+	PlayerName   string `json:"player_name"`
+	JerseyNumber *int   `json:"jersey_number"`
+	StatSummary
+}
+
+type TeamStats struct {
+	TeamID   uint                 `json:"team_id"`
+	SeasonID uint                 `json:"season_id"`
+	Sport    string               `json:"sport"`
+	Season   StatSummary          `json:"season"`
+	Players  []TeamPlayerStatLine `json:"players"`
+	Games    []TeamGameLine       `json:"games"`
+}
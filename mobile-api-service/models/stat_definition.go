@@ -0,0 +1,61 @@
+package models
+
+// Stat field types
+const (
+	StatFieldInteger = "integer"
+	StatFieldDecimal = "decimal"
+	StatFieldBoolean = "boolean"
+)
+
+// StatField is one stat a sport records. Column fields are stored in the
+// game_stats columns (basketball only); the rest go in additional_stats.
+type StatField struct {
+	Key    string   `json:"key"`
+	Label  string   `json:"label"`
+	Type   string   `json:"type"`
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+	Column bool     `json:"column,omitempty"`
+}
+
+// StatRule requires the sum of Fields not to exceed the sum of Limit
+type StatRule struct {
+	Fields []string `json:"fields"`
+	Limit  []string `json:"limit"`
+}
+
+// StatTerm is Coefficient × Field
+type StatTerm struct {
+	Field       string  `json:"field"`
+	Coefficient float64 `json:"coefficient"`
+}
+
+// StatFormula derives a metric as sum(Numerator) / sum(Denominator) × Scale;
+// an empty Denominator divides by one. Formula is the human-readable form.
+type StatFormula struct {
+	Key         string     `json:"key"`
+	Label       string     `json:"label"`
+	Formula     string     `json:"formula"`
+	Numerator   []StatTerm `json:"numerator"`
+	Denominator []StatTerm `json:"denominator,omitempty"`
+	Scale       float64    `json:"scale"`
+	Decimals    int        `json:"decimals"`
+}
+
+// SportStatDefinition is what a sport records per player per game, the
+// consistency rules between the fields and the metrics derived from them
+type SportStatDefinition struct {
+	Sport   string        `json:"sport"`
+	Label   string        `json:"label"`
+	Fields  []StatField   `json:"fields"`
+	Rules   []StatRule    `json:"rules"`
+	Derived []StatFormula `json:"derived"`
+}
+
+// SportStatSummary totals the fields of a sport's definition over several
+// games; booleans count the games they were true
+type SportStatSummary struct {
+	Totals   map[string]float64  `json:"totals"`
+	Averages map[string]float64  `json:"averages"`
+	Derived  map[string]*float64 `json:"derived"`
+}
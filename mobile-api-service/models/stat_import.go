@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StatImportProfile maps the columns of a scorebook CSV export to stat fields
+// and is saved per team so coaches only map their export once. Columns is an
+// object of stat field → CSV header.
+type StatImportProfile struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	TeamID    uint            `json:"team_id" gorm:"not null;uniqueIndex:unique_team_name"`
+	Name      string          `json:"name" gorm:"size:100;not null;uniqueIndex:unique_team_name"`
+	Delimiter string          `json:"delimiter" gorm:"size:1;not null;default:','"`
+	Columns   json.RawMessage `json:"columns" gorm:"type:json;not null"`
+	CreatedBy uint            `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type StatImportProfileRequest struct {
+	Name      string            `json:"name" binding:"required,max=100"`
+	Delimiter string            `json:"delimiter" binding:"omitempty,len=1"`
+	Columns   map[string]string `json:"columns" binding:"required,min=1"`
+}
+
+// StatImportRequest carries the CSV text of an export. Columns (or the saved
+// profile) maps it; without either, headers are recognized by their common
+// scorebook names. Assignments pins CSV lines to players the matcher could
+// not resolve; SkipInvalid imports the valid rows when others have errors.
+type StatImportRequest struct {
+	CSV         string            `json:"csv" binding:"required,max=1048576"`
+	ProfileID   *uint             `json:"profile_id"`
+	Delimiter   string            `json:"delimiter" binding:"omitempty,len=1"`
+	Columns     map[string]string `json:"columns"`
+	Assignments map[int]uint      `json:"assignments"`
+	SkipInvalid bool              `json:"skip_invalid"`
+}
+
+// Ways a CSV row was matched to a player
+const (
+	StatImportMatchJersey     = "jersey"
+	StatImportMatchName       = "name"
+	StatImportMatchAssignment = "assignment"
+)
+
+// StatImportRow is one CSV line of an import preview. Line is the line number
+// in the file; Update marks players whose stats for the game are replaced.
+type StatImportRow struct {
+	Line         int           `json:"line"`
+	JerseyNumber *int          `json:"jersey_number"`
+	Name         string        `json:"name"`
+	PlayerID     *uint         `json:"player_id"`
+	PlayerName   string        `json:"player_name,omitempty"`
+	MatchedBy    string        `json:"matched_by,omitempty"`
+	Stats        *GameStatLine `json:"stats,omitempty"`
+	Update       bool          `json:"update"`
+	Errors       []string      `json:"errors"`
+}
+
+// StatImportPreview is what an import would write; SkippedLines are blank and
+// total rows
+type StatImportPreview struct {
+	EventID         uint              `json:"event_id"`
+	Columns         map[string]string `json:"columns"`
+	UnmappedColumns []string          `json:"unmapped_columns"`
+	Rows            []StatImportRow   `json:"rows"`
+	ValidRows       int               `json:"valid_rows"`
+	InvalidRows     int               `json:"invalid_rows"`
+	SkippedLines    []int             `json:"skipped_lines"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Team struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint           `json:"organization_id" gorm:"not null;index"`
+	SeasonID       uint           `json:"season_id" gorm:"not null;index"`
+	Name           string         `json:"name" gorm:"size:255;not null"`
+	SportType      string         `json:"sport_type" gorm:"size:100"`
+	Division       string         `json:"division" gorm:"size:100"`
+	Description    string         `json:"description" gorm:"type:text"`
+	Status         uint8          `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Organization is read for branding; organizations are managed by the org service
+type Organization struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"size:255;not null"`
+	LogoURL   string         `json:"logo_url" gorm:"size:500"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Team member types (team_members.member_type)
+const (
+	MemberTypeCoach  uint8 = 1
+	MemberTypePlayer uint8 = 2
+)
+
+// Team member status (team_members.status)
+const (
+	MemberStatusActive    uint8 = 1
+	MemberStatusInactive  uint8 = 2
+	MemberStatusRemoved   uint8 = 3
+	MemberStatusGraduated uint8 = 4
+)
+
+type TeamMember struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	TeamID       uint       `json:"team_id" gorm:"not null;uniqueIndex:unique_team_user"`
+	UserID       uint       `json:"user_id" gorm:"not null;uniqueIndex:unique_team_user;index"`
+	MemberType   uint8      `json:"member_type" gorm:"type:tinyint unsigned;not null"`
+	JerseyNumber *int       `json:"jersey_number"`
+	Position     string     `json:"position" gorm:"size:100"`
+	Status       uint8      `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	JoinedAt     time.Time  `json:"joined_at"`
+	RemovedAt    *time.Time `json:"removed_at"`
+	RemovedBy    *uint      `json:"removed_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Parent link status (parent_players.status)
+const (
+	ParentLinkPending  uint8 = 1
+	ParentLinkApproved uint8 = 2
+	ParentLinkRejected uint8 = 3
+)
+
+type ParentPlayer struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ParentID     uint      `json:"parent_id" gorm:"not null;uniqueIndex:unique_parent_player"`
+	PlayerID     uint      `json:"player_id" gorm:"not null;uniqueIndex:unique_parent_player;index"`
+	Relationship string    `json:"relationship" gorm:"size:50"`
+	Status       uint8     `json:"status" gorm:"type:tinyint unsigned;not null;default:1"`
+	ApprovedBy   *uint     `json:"approved_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
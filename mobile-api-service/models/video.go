@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Video processing status (videos.processing_status)
+const (
+	VideoStatusPending    uint8 = 1
+	VideoStatusProcessing uint8 = 2
+	VideoStatusCompleted  uint8 = 3
+	VideoStatusFailed     uint8 = 4
+)
+
+type Video struct {
+	ID               uint            `json:"id" gorm:"primaryKey"`
+	TeamID           uint            `json:"team_id" gorm:"not null;index"`
+	EventID          *uint           `json:"event_id" gorm:"index"`
+	Title            string          `json:"title" gorm:"size:255;not null"`
+	Description      string          `json:"description" gorm:"type:text"`
+	StorageURL       string          `json:"storage_url" gorm:"size:500;not null"`
+	ThumbnailURL     string          `json:"thumbnail_url" gorm:"size:500"`
+	Duration         *int            `json:"duration"`
+	DurationMs       *int64          `json:"duration_ms"`
+	FileSize         int64           `json:"file_size"`
+	FileType         string          `json:"file_type" gorm:"size:100"`
+	Metadata         json.RawMessage `json:"metadata,omitempty" gorm:"type:json"`
+	ProcessingStatus uint8           `json:"processing_status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	UploadedBy       uint            `json:"uploaded_by" gorm:"not null"`
+	UploadedAt       time.Time       `json:"uploaded_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt  `json:"-" gorm:"index"`
+
+	// URLExpiresAt is set when storage_url, thumbnail_url and hls_url hold signed playback URLs
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty" gorm:"-"`
+	HLSURL       string     `json:"hls_url,omitempty" gorm:"-"`
+}
+
+// VideoUpload tracks a resumable (tus) upload of a video file
+type VideoUpload struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	VideoID        uint       `json:"video_id" gorm:"not null;uniqueIndex"`
+	UploadKey      string     `json:"-" gorm:"size:100;not null"`
+	ObjectKey      string     `json:"-" gorm:"size:255;not null"`
+	UploadLength   int64      `json:"upload_length" gorm:"not null"`
+	UploadOffset   int64      `json:"upload_offset" gorm:"not null"`
+	ExpectedSHA256 string     `json:"expected_sha256" gorm:"column:expected_sha256;size:64"`
+	HashState      []byte     `json:"-" gorm:"type:blob"`
+	CreatedBy      uint       `json:"created_by" gorm:"not null"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Video processing job status (video_processing_jobs.status)
+const (
+	VideoJobQueued    uint8 = 1
+	VideoJobRunning   uint8 = 2
+	VideoJobSucceeded uint8 = 3
+	VideoJobRetrying  uint8 = 4
+	VideoJobDead      uint8 = 5
+)
+
+// VideoProcessingJob is the progress record of a queued processing run
+type VideoProcessingJob struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	VideoID    uint       `json:"video_id" gorm:"not null;index"`
+	Status     uint8      `json:"status" gorm:"type:tinyint unsigned;not null;default:1;index"`
+	Stage      string     `json:"stage" gorm:"size:50"`
+	Progress   uint8      `json:"progress" gorm:"type:tinyint unsigned;not null;default:0"`
+	Attempts   int        `json:"attempts" gorm:"not null;default:0"`
+	LastError  string     `json:"last_error" gorm:"type:text"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	// LeaseExpiresAt is extended by the worker running the job; a running job
+	// whose lease has lapsed may be taken over
+	LeaseExpiresAt *time.Time `json:"-" gorm:"index"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// HLSSegment is one media segment of a variant playlist
+type HLSSegment struct {
+	URI      string  `json:"uri"`
+	Duration float64 `json:"duration"`
+}
+
+// VideoRendition is one HLS bitrate variant of a processed video
+type VideoRendition struct {
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	VideoID        uint            `json:"video_id" gorm:"not null;index"`
+	Name           string          `json:"name" gorm:"size:20;not null"`
+	Width          int             `json:"width"`
+	Height         int             `json:"height"`
+	Bandwidth      int             `json:"bandwidth"`
+	PlaylistKey    string          `json:"-" gorm:"size:255;not null"`
+	TargetDuration int             `json:"target_duration"`
+	Segments       json.RawMessage `json:"-" gorm:"type:json"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
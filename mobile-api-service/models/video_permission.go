@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// Video permission types (video_permissions.permission_type)
+const (
+	VideoPermissionPublic uint8 = 1
+	VideoPermissionTeam   uint8 = 2
+	VideoPermissionPlayer uint8 = 3
+	VideoPermissionParent uint8 = 4
+)
+
+type VideoPermission struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	VideoID        uint      `json:"video_id" gorm:"not null;index"`
+	UserID         *uint     `json:"user_id" gorm:"index"`
+	PermissionType uint8     `json:"permission_type" gorm:"type:tinyint unsigned;not null;index"`
+	GrantedBy      uint      `json:"granted_by" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// VideoGrant is one grant of a bulk request; UserID is required for player and parent grants
+type VideoGrant struct {
+	PermissionType uint8 `json:"permission_type" binding:"required,oneof=1 2 3 4"`
+	UserID         *uint `json:"user_id"`
+}
+
+type BulkVideoPermissionRequest struct {
+	VideoIDs []uint       `json:"video_ids" binding:"required,min=1,max=100"`
+	Grants   []VideoGrant `json:"grants" binding:"required,min=1,max=100,dive"`
+}
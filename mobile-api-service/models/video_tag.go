@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+type VideoTag struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	VideoID     uint      `json:"video_id" gorm:"not null;index"`
+	PlayerID    uint      `json:"player_id" gorm:"not null;index"`
+	StartTime   float64   `json:"start_time" gorm:"type:decimal(10,2);not null"`
+	EndTime     float64   `json:"end_time" gorm:"type:decimal(10,2);not null"`
+	Label       string    `json:"label" gorm:"size:255"`
+	Description string    `json:"description" gorm:"type:text"`
+	CreatedBy   uint      `json:"created_by" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// HLSURL is the signed clip playlist covering only this tag's range
+	HLSURL string `json:"hls_url,omitempty" gorm:"-"`
+}
+
+type CreateVideoTagRequest struct {
+	PlayerID    uint     `json:"player_id" binding:"required"`
+	StartTime   *float64 `json:"start_time" binding:"required,min=0"`
+	EndTime     *float64 `json:"end_time" binding:"required,min=0"`
+	Label       string   `json:"label" binding:"max=255"`
+	Description string   `json:"description"`
+}
+
+// PlayerClip is a tag joined with the video it was made on
+type PlayerClip struct {
+	VideoTag
+	VideoTitle   string    `json:"video_title"`
+	TeamID       uint      `json:"team_id"`
+	SeasonID     uint      `json:"season_id"`
+	EventID      *uint     `json:"event_id"`
+	StorageURL   string    `json:"storage_url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty" gorm:"-"`
+}
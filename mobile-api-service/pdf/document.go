@@ -0,0 +1,290 @@
+// Package pdf writes simple PDF documents: text in the standard Helvetica
+// fonts, lines, filled rectangles and JPEG or PNG images. Positions are in
+// points (1/72 inch) from the top-left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Page sizes in points
+var (
+	A4     = Size{595.28, 841.89}
+	Letter = Size{612, 792}
+)
+
+type Size struct {
+	Width, Height float64
+}
+
+type Document struct {
+	size   Size
+	pages  []*Page
+	images []*Image
+	info   map[string]string
+}
+
+func New(size Size) *Document {
+	return &Document{size: size, info: map[string]string{}}
+}
+
+// SetInfo sets a document information entry such as Title or Author
+func (d *Document) SetInfo(key, value string) {
+	d.info[key] = value
+}
+
+func (d *Document) Size() Size {
+	return d.size
+}
+
+// AddPage appends a blank page and returns it for drawing
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d, images: map[*Image]string{}}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Page returns the nth page, counting from 1, to draw on it again
+func (d *Document) Page(n int) *Page {
+	return d.pages[n-1]
+}
+
+// Page accumulates the drawing operators of one page
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	font    Font
+	size    float64
+	images  map[*Image]string
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// y converts a distance from the top of the page to PDF's bottom-up coordinates
+func (p *Page) y(top float64) float64 {
+	return p.doc.size.Height - top
+}
+
+func (p *Page) SetFont(font Font, size float64) {
+	p.font, p.size = font, size
+}
+
+// SetFillColor sets the color of text and filled shapes (0-255 components)
+func (p *Page) SetFillColor(r, g, b uint8) {
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", num(float64(r)/255), num(float64(g)/255), num(float64(b)/255))
+}
+
+// SetStrokeColor sets the color of lines and outlines (0-255 components)
+func (p *Page) SetStrokeColor(r, g, b uint8) {
+	fmt.Fprintf(&p.content, "%s %s %s RG\n", num(float64(r)/255), num(float64(g)/255), num(float64(b)/255))
+}
+
+func (p *Page) SetLineWidth(width float64) {
+	fmt.Fprintf(&p.content, "%s w\n", num(width))
+}
+
+// Text draws text with its baseline at top
+func (p *Page) Text(x, top float64, text string) {
+	if p.size == 0 {
+		p.size = 12
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (", p.font.resourceName(), num(p.size), num(x), num(p.y(top)))
+	for _, b := range encode(text) {
+		switch {
+		case b == '(' || b == ')' || b == '\\':
+			p.content.WriteByte('\\')
+			p.content.WriteByte(b)
+		case b < 32 || b > 126:
+			fmt.Fprintf(&p.content, "\\%03o", b)
+		default:
+			p.content.WriteByte(b)
+		}
+	}
+	p.content.WriteString(") Tj ET\n")
+}
+
+// TextRight draws text ending at x
+func (p *Page) TextRight(x, top float64, text string) {
+	p.Text(x-TextWidth(p.font, p.size, text), top, text)
+}
+
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%s %s m %s %s l S\n", num(x1), num(p.y(y1)), num(x2), num(p.y(y2)))
+}
+
+// Rect draws a rectangle whose top-left corner is at x, top; filled with the
+// fill color or outlined with the stroke color
+func (p *Page) Rect(x, top, width, height float64, fill bool) {
+	op := "S"
+	if fill {
+		op = "f"
+	}
+	fmt.Fprintf(&p.content, "%s %s %s %s re %s\n", num(x), num(p.y(top+height)), num(width), num(height), op)
+}
+
+// Image draws img scaled into the box whose top-left corner is at x, top
+func (p *Page) Image(img *Image, x, top, width, height float64) {
+	name, ok := p.images[img]
+	if !ok {
+		index := -1
+		for i, known := range p.doc.images {
+			if known == img {
+				index = i
+			}
+		}
+		if index < 0 {
+			p.doc.images = append(p.doc.images, img)
+			index = len(p.doc.images) - 1
+		}
+		name = "Im" + strconv.Itoa(index+1)
+		p.images[img] = name
+	}
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /%s Do Q\n", num(width), num(height), num(x), num(p.y(top+height)), name)
+}
+
+// writer numbers the objects of a document and records their offsets
+type writer struct {
+	out     *bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+func (w *writer) begin(id int) {
+	w.offsets[id-1] = w.out.Len()
+	fmt.Fprintf(w.out, "%d 0 obj\n", id)
+}
+
+func (w *writer) object(id int, body string) {
+	w.begin(id)
+	w.out.WriteString(body)
+	w.out.WriteString("\nendobj\n")
+}
+
+func (w *writer) stream(id int, dict string, data []byte) {
+	w.begin(id)
+	fmt.Fprintf(w.out, "<< %s /Length %d >>\nstream\n", dict, len(data))
+	w.out.Write(data)
+	w.out.WriteString("\nendstream\nendobj\n")
+}
+
+func literal(text string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	for _, b := range encode(text) {
+		if b == '(' || b == ')' || b == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(b)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// WriteTo writes the finished document
+func (d *Document) WriteTo(out io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	w := &writer{out: &bytes.Buffer{}}
+	w.out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	catalog, pagesID := w.reserve(), w.reserve()
+	fonts := []int{w.reserve(), w.reserve()}
+	imageIDs := make([]int, len(d.images))
+	maskIDs := make([]int, len(d.images))
+	for i, img := range d.images {
+		imageIDs[i] = w.reserve()
+		if img.mask != nil {
+			maskIDs[i] = w.reserve()
+		}
+	}
+	pageIDs := make([]int, len(d.pages))
+	contentIDs := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageIDs[i], contentIDs[i] = w.reserve(), w.reserve()
+	}
+	infoID := w.reserve()
+
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+	kids := ""
+	for _, id := range pageIDs {
+		kids += fmt.Sprintf("%d 0 R ", id)
+	}
+	w.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		kids, len(pageIDs), num(d.size.Width), num(d.size.Height)))
+	for i, font := range []Font{Helvetica, HelveticaBold} {
+		w.object(fonts[i], fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.baseName()))
+	}
+
+	for i, img := range d.images {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s",
+			img.Width, img.Height, img.colorSpace, img.filter)
+		if img.mask != nil {
+			dict += fmt.Sprintf(" /SMask %d 0 R", maskIDs[i])
+		}
+		w.stream(imageIDs[i], dict, img.data)
+		if img.mask != nil {
+			w.stream(maskIDs[i], fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode",
+				img.Width, img.Height), img.mask)
+		}
+	}
+
+	for i, page := range d.pages {
+		xobjects := ""
+		for img, name := range page.images {
+			for j, known := range d.images {
+				if known == img {
+					xobjects += fmt.Sprintf("/%s %d 0 R ", name, imageIDs[j])
+				}
+			}
+		}
+		resources := fmt.Sprintf("/Font << /F1 %d 0 R /F2 %d 0 R >>", fonts[0], fonts[1])
+		if xobjects != "" {
+			resources += " /XObject << " + xobjects + ">>"
+		}
+		w.object(pageIDs[i], fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << %s >> /Contents %d 0 R >>",
+			pagesID, resources, contentIDs[i]))
+
+		content, err := deflate(page.content.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		w.stream(contentIDs[i], "/Filter /FlateDecode", content)
+	}
+
+	keys := make([]string, 0, len(d.info))
+	for key := range d.info {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	info := "<< /Producer (mobile-api-service)"
+	for _, key := range keys {
+		info += fmt.Sprintf(" /%s %s", key, literal(d.info[key]))
+	}
+	w.object(infoID, info+" >>")
+
+	xref := w.out.Len()
+	fmt.Fprintf(w.out, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(w.out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(w.out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, catalog, infoID, xref)
+
+	n, err := out.Write(w.out.Bytes())
+	return int64(n), err
+}
@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// testDocument has two pages, text that needs escaping and a PNG with
+// transparency, so it holds every kind of object the writer emits
+func testDocument(t *testing.T) []byte {
+	t.Helper()
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		src.Set(x, 0, color.NRGBA{R: 200, A: 255})
+		src.Set(x, 1, color.NRGBA{B: 200, A: 80})
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, src); err != nil {
+		t.Fatal(err)
+	}
+	logo, err := LoadImage(encoded.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := New(A4)
+	doc.SetInfo("Title", "Report (draft)")
+	first := doc.AddPage()
+	first.SetFont(HelveticaBold, 18)
+	first.Text(40, 60, `Scores (Q1) \ café – 10→12`)
+	first.Image(logo, 40, 80, 40, 20)
+	first.Rect(40, 120, 100, 10, true)
+	second := doc.AddPage()
+	second.Line(40, 40, 200, 40)
+	second.Image(logo, 40, 80, 40, 20)
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+var objectHeader = regexp.MustCompile(`(?m)^(\d+) 0 obj\n`)
+
+func TestWriteToCrossReference(t *testing.T) {
+	out := testDocument(t)
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) {
+		t.Fatalf("header = %q", out[:12])
+	}
+	tail := regexp.MustCompile(`trailer\n<< /Size (\d+) /Root (\d+) 0 R /Info (\d+) 0 R >>\nstartxref\n(\d+)\n%%EOF\n$`).FindSubmatch(out)
+	if tail == nil {
+		t.Fatalf("trailer not found at the end of %q", out[max(0, len(out)-120):])
+	}
+	size, _ := strconv.Atoi(string(tail[1]))
+	xref, _ := strconv.Atoi(string(tail[4]))
+
+	table := out[xref:]
+	header := fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", size)
+	if !bytes.HasPrefix(table, []byte(header)) {
+		t.Fatalf("startxref %d points at %q, want %q", xref, table[:min(len(table), len(header))], header)
+	}
+	entries := table[len(header):]
+	for id := 1; id < size; id++ {
+		entry := string(entries[(id-1)*20 : id*20])
+		var offset int
+		if _, err := fmt.Sscanf(entry, "%010d 00000 n \n", &offset); err != nil {
+			t.Fatalf("xref entry %d = %q: %v", id, entry, err)
+		}
+		if want := fmt.Sprintf("%d 0 obj\n", id); !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", id, out[offset:offset+len(want)], want)
+		}
+	}
+
+	// Every object is in the table, and nothing else is
+	if objects := objectHeader.FindAll(out, -1); len(objects) != size-1 {
+		t.Errorf("%d objects, xref lists %d", len(objects), size-1)
+	}
+
+	root := object(t, out, string(tail[2]))
+	if !bytes.Contains(root, []byte("/Type /Catalog")) {
+		t.Errorf("root = %q, want the catalog", root)
+	}
+	if info := object(t, out, string(tail[3])); !bytes.Contains(info, []byte(`/Title (Report \(draft\))`)) {
+		t.Errorf("info = %q", info)
+	}
+	if !bytes.Contains(out, []byte("/Type /Pages /Kids [")) || !bytes.Contains(out, []byte("/Count 2 ")) {
+		t.Error("page tree does not list two pages")
+	}
+}
+
+// object returns the body of object id
+func object(t *testing.T, out []byte, id string) []byte {
+	t.Helper()
+	start := bytes.Index(out, []byte("\n"+id+" 0 obj\n"))
+	if start < 0 {
+		t.Fatalf("object %s not found", id)
+	}
+	body := out[start+len(id)+7:]
+	return body[:bytes.Index(body, []byte("\nendobj\n"))]
+}
+
+var streamPattern = regexp.MustCompile(`<< ([^\n]*)/Length (\d+) >>\nstream\n`)
+
+func TestWriteToStreams(t *testing.T) {
+	out := testDocument(t)
+
+	matches := streamPattern.FindAllSubmatchIndex(out, -1)
+	// Two page contents, the image and its soft mask; the image is shared by both pages
+	if len(matches) != 4 {
+		t.Fatalf("%d streams, want 4", len(matches))
+	}
+	var contents [][]byte
+	for _, m := range matches {
+		length, _ := strconv.Atoi(string(out[m[4]:m[5]]))
+		data := out[m[1] : m[1]+length]
+		if !bytes.HasPrefix(out[m[1]+length:], []byte("\nendstream\nendobj\n")) {
+			t.Errorf("stream %q: /Length %d does not end at endstream", out[m[2]:m[3]], length)
+			continue
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("stream %q is not zlib data: %v", out[m[2]:m[3]], err)
+			continue
+		}
+		inflated, err := io.ReadAll(reader)
+		if err != nil {
+			t.Errorf("stream %q: %v", out[m[2]:m[3]], err)
+			continue
+		}
+		if bytes.HasPrefix(out[m[2]:m[3]], []byte("/Filter /FlateDecode ")) {
+			contents = append(contents, inflated)
+		}
+	}
+
+	if len(contents) != 2 {
+		t.Fatalf("%d page contents, want 2", len(contents))
+	}
+	// Parentheses and backslashes are escaped, WinAnsi bytes written in octal
+	text := `BT /F2 18.00 Tf 40.00 781.89 Td (Scores \(Q1\) \\ caf\351 \226 10>12) Tj ET`
+	if !bytes.Contains(contents[0], []byte(text)) {
+		t.Errorf("first page = %q, want %q", contents[0], text)
+	}
+	if !bytes.Contains(contents[1], []byte("/Im1 Do")) {
+		t.Errorf("second page = %q, want the shared image", contents[1])
+	}
+}
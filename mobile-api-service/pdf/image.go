@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// Image is a picture that can be drawn on any page of a document. JPEGs are
+// embedded as they are; other formats are decoded and stored compressed,
+// with their transparency as a soft mask.
+type Image struct {
+	Width, Height int
+
+	colorSpace string
+	filter     string
+	data       []byte
+	mask       []byte
+}
+
+// LoadImage reads a JPEG or PNG
+func LoadImage(data []byte) (*Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	if format == "jpeg" {
+		switch config.ColorModel {
+		case color.GrayModel:
+			return &Image{Width: config.Width, Height: config.Height, colorSpace: "DeviceGray", filter: "DCTDecode", data: data}, nil
+		case color.YCbCrModel, color.RGBAModel:
+			return &Image{Width: config.Width, Height: config.Height, colorSpace: "DeviceRGB", filter: "DCTDecode", data: data}, nil
+		}
+		// CMYK JPEGs are converted below
+		decoded, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return fromImage(decoded)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	return fromImage(decoded)
+}
+
+func fromImage(src image.Image) (*Image, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	rgb := make([]byte, 0, width*height*3)
+	alpha := make([]byte, 0, width*height)
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 0xFF
+		}
+	}
+
+	img := &Image{Width: width, Height: height, colorSpace: "DeviceRGB", filter: "FlateDecode"}
+	var err error
+	if img.data, err = deflate(rgb); err != nil {
+		return nil, err
+	}
+	if !opaque {
+		if img.mask, err = deflate(alpha); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"mobile-api-service/handlers"
 	"mobile-api-service/middleware"
+	"mobile-api-service/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,6 +23,12 @@ func SetupRoutes() *gin.Engine {
 		})
 	})
 
+	// Locally stored media behind signed URLs (S3 objects are served by the bucket)
+	r.GET("/media/*key", handlers.ServeMedia)
+
+	// Public page of a shared recruiting profile
+	r.GET("/share/:token", handlers.GetSharedProfilePage)
+
 	// API Routes for Frontend (Flutter/Mobile/Web)
 	api := r.Group("/api")
 	{
@@ -33,8 +40,176 @@ func SetupRoutes() *gin.Engine {
 
 		// Store endpoints
 		api.GET("/stores", handlers.GetStoreList)
+
+		// HLS playlist endpoints, authorized by the signature in the URL
+		api.GET("/videos/:id/hls/master.m3u8", handlers.GetVideoMasterPlaylist)
+		api.GET("/videos/:id/hls/:rendition/index.m3u8", handlers.GetVideoVariantPlaylist)
+		api.GET("/video-tags/:id/hls/master.m3u8", handlers.GetClipMasterPlaylist)
+		api.GET("/video-tags/:id/hls/:rendition/index.m3u8", handlers.GetClipVariantPlaylist)
+
+		// Shared recruiting profiles, authorized by the share token
+		api.GET("/shared-profiles/:token", handlers.GetSharedProfile)
+	}
+
+	// Authenticated API Routes
+	auth := api.Group("")
+	auth.Use(middleware.Auth())
+	{
+		// Notification inbox endpoints
+		auth.GET("/notifications", handlers.GetNotificationList)
+		auth.GET("/notifications/unread-count", handlers.GetUnreadNotificationCount)
+		auth.PUT("/notifications/read-all", handlers.MarkAllNotificationsRead)
+		auth.PUT("/notifications/:id/read", handlers.MarkNotificationRead)
+		auth.DELETE("/notifications/:id", handlers.DeleteNotification)
+
+		// Notification preference endpoints
+		auth.GET("/notification-preferences", handlers.GetNotificationPreferences)
+		auth.PUT("/notification-preferences", handlers.UpdateNotificationPreferences)
+
+		// Push device endpoints
+		auth.POST("/notification-devices", handlers.RegisterNotificationDevice)
+		auth.PUT("/notification-devices/:id", handlers.RefreshNotificationDevice)
+		auth.DELETE("/notification-devices/:id", handlers.DeactivateNotificationDevice)
+
+		// Event endpoints
+		auth.PUT("/events/:id", handlers.UpdateEvent)
+		auth.POST("/events/:id/cancel", handlers.CancelEvent)
+
+		// Game stats endpoints
+		auth.POST("/events/:id/stats", handlers.SaveGameStats)
+		auth.GET("/events/:id/stats", handlers.GetGameBoxScore)
+		auth.GET("/events/:id/stats/history", handlers.GetGameStatHistory)
+		auth.POST("/events/:id/stats/import/preview", handlers.PreviewStatImport)
+		auth.POST("/events/:id/stats/import", handlers.ImportGameStats)
+		auth.GET("/teams/:teamId/stat-import-profiles", handlers.GetStatImportProfiles)
+		auth.POST("/teams/:teamId/stat-import-profiles", handlers.CreateStatImportProfile)
+		auth.PUT("/teams/:teamId/stat-import-profiles/:profileId", handlers.UpdateStatImportProfile)
+		auth.DELETE("/teams/:teamId/stat-import-profiles/:profileId", handlers.DeleteStatImportProfile)
+		auth.POST("/events/:id/plays/sync", handlers.SyncGamePlays)
+		auth.GET("/events/:id/plays", handlers.GetGamePlayList)
+		auth.GET("/players/:playerId/stats", handlers.GetPlayerStats)
+		auth.GET("/teams/:teamId/stats", handlers.GetTeamStats)
+		auth.GET("/stat-definitions", handlers.GetStatDefinitions)
+		auth.GET("/teams/:teamId/stat-definition", handlers.GetTeamStatDefinition)
+		auth.POST("/events/:id/shots", handlers.RecordGameShots)
+		auth.GET("/events/:id/shots", handlers.GetGameShots)
+		auth.DELETE("/events/:id/shots/:shotId", handlers.DeleteGameShot)
+		auth.GET("/teams/:teamId/shot-chart", handlers.GetTeamShotChart)
+		auth.GET("/players/:playerId/shot-chart", handlers.GetPlayerShotChart)
+
+		// Effort and buy-in endpoints
+		auth.POST("/events/:id/effort", handlers.SaveEffortMetrics)
+		auth.POST("/events/:id/buy-in", handlers.SaveBuyInScores)
+		auth.GET("/events/:id/effort", handlers.GetEventEffort)
+		auth.GET("/teams/:teamId/effort/trends", handlers.GetEffortTrends)
+		auth.GET("/teams/:teamId/effort/check-ins", handlers.GetCheckInList)
+
+		// Leadership notes and player timeline endpoints
+		auth.POST("/players/:playerId/leadership-notes", handlers.CreateLeadershipNote)
+		auth.GET("/players/:playerId/leadership-notes", handlers.GetLeadershipNotes)
+		auth.PUT("/leadership-notes/:noteId", handlers.UpdateLeadershipNote)
+		auth.DELETE("/leadership-notes/:noteId", handlers.DeleteLeadershipNote)
+		auth.GET("/players/:playerId/timeline", handlers.GetPlayerTimeline)
+
+		// Academic tracking and eligibility endpoints
+		auth.GET("/players/:playerId/academics", handlers.GetAcademicEntries)
+		auth.POST("/players/:playerId/academics", handlers.CreateAcademicEntry)
+		auth.PUT("/academics/:entryId", handlers.UpdateAcademicEntry)
+		auth.DELETE("/academics/:entryId", handlers.DeleteAcademicEntry)
+		auth.GET("/players/:playerId/eligibility", handlers.GetPlayerEligibility)
+		auth.GET("/teams/:teamId/eligibility", handlers.GetTeamEligibility)
+		auth.GET("/organizations/:orgId/eligibility-rules", handlers.GetEligibilityRule)
+		auth.PUT("/organizations/:orgId/eligibility-rules", handlers.SaveEligibilityRule)
+
+		// Life goal endpoints
+		auth.GET("/players/:playerId/life-goals", handlers.GetLifeGoals)
+		auth.POST("/players/:playerId/life-goals", handlers.CreateLifeGoal)
+		auth.GET("/life-goals/:goalId", handlers.GetLifeGoal)
+		auth.PUT("/life-goals/:goalId", handlers.UpdateLifeGoal)
+		auth.DELETE("/life-goals/:goalId", handlers.DeleteLifeGoal)
+		auth.POST("/life-goals/:goalId/milestones", handlers.AddGoalMilestone)
+		auth.PUT("/life-goals/:goalId/milestones/:milestoneId", handlers.UpdateGoalMilestone)
+		auth.DELETE("/life-goals/:goalId/milestones/:milestoneId", handlers.DeleteGoalMilestone)
+		auth.POST("/life-goals/:goalId/check-ins", handlers.AddGoalCheckIn)
+		auth.GET("/life-goals/:goalId/check-ins", handlers.GetGoalCheckIns)
+		auth.POST("/life-goals/:goalId/comments", handlers.AddGoalComment)
+		auth.DELETE("/life-goals/:goalId/comments/:commentId", handlers.DeleteGoalComment)
+
+		// Report card endpoints
+		auth.GET("/players/:playerId/report-card", handlers.GetPlayerReportCard)
+		auth.GET("/teams/:teamId/report-cards", handlers.GetTeamReportCards)
+
+		// Recruiting profile endpoints
+		auth.GET("/recruiting-profile-schemas", handlers.GetRecruitingProfileSchemas)
+		auth.GET("/players/:playerId/recruiting-profile", handlers.GetRecruitingProfile)
+		auth.PUT("/players/:playerId/recruiting-profile", handlers.SaveRecruitingProfile)
+		auth.PUT("/players/:playerId/recruiting-profile/date-of-birth", handlers.SetRecruitingDateOfBirth)
+		auth.PUT("/players/:playerId/recruiting-profile/highlights", handlers.SetProfileHighlights)
+		auth.POST("/players/:playerId/recruiting-profile/shares", handlers.CreateProfileShare)
+		auth.GET("/players/:playerId/recruiting-profile/shares", handlers.GetProfileShares)
+		auth.PUT("/profile-shares/:shareId", handlers.UpdateProfileShare)
+		auth.DELETE("/profile-shares/:shareId", handlers.DeleteProfileShare)
+		auth.GET("/profile-shares/:shareId/views", handlers.GetProfileShareViews)
+		auth.GET("/players/:playerId/recruiting-profile/permissions", handlers.GetProfilePermissions)
+		auth.POST("/players/:playerId/recruiting-profile/permissions", handlers.GrantProfilePermission)
+		auth.PUT("/profile-permissions/:permissionId", handlers.DecideProfileAccess)
+		auth.DELETE("/profile-permissions/:permissionId", handlers.RevokeProfilePermission)
+		auth.GET("/players/:playerId/recruiting-profile/recruiter-views", handlers.GetRecruiterProfileViews)
+
+		// Video upload endpoints (tus resumable uploads)
+		auth.POST("/teams/:teamId/videos", handlers.CreateVideoUpload)
+		auth.GET("/teams/:teamId/videos", handlers.GetTeamVideoList)
+		auth.HEAD("/videos/:id/upload", handlers.GetVideoUploadOffset)
+		auth.PATCH("/videos/:id/upload", handlers.UploadVideoChunk)
+		auth.DELETE("/videos/:id/upload", handlers.DeleteVideoUpload)
+		auth.GET("/videos/:id", handlers.GetVideo)
+		auth.GET("/videos/:id/processing", handlers.GetVideoProcessing)
+
+		// Video tag endpoints
+		auth.POST("/videos/:id/tags", handlers.CreateVideoTag)
+		auth.GET("/videos/:id/tags", handlers.GetVideoTagList)
+		auth.DELETE("/video-tags/:id", handlers.DeleteVideoTag)
+		auth.GET("/players/:playerId/videos", handlers.GetPlayerVideoList)
+
+		// Video permission endpoints
+		auth.GET("/videos/:id/permissions", handlers.GetVideoPermissionList)
+		auth.POST("/video-permissions/grant", handlers.GrantVideoPermissions)
+		auth.POST("/video-permissions/revoke", handlers.RevokeVideoPermissions)
+	}
+
+	// Admin API Routes
+	admin := auth.Group("/admin")
+	admin.Use(middleware.RequireRole(models.RoleSuperAdmin, models.RoleOrgAdmin))
+	{
+		// Notification template endpoints
+		admin.GET("/notification-templates", handlers.GetNotificationTemplateList)
+		admin.POST("/notification-templates/preview", handlers.PreviewNotificationTemplateByType)
+		admin.POST("/notification-templates/:id/preview", handlers.PreviewNotificationTemplate)
+
+		// Notification dispatch endpoints
+		admin.POST("/notifications/send", handlers.SendNotification)
+		admin.GET("/notifications/:id/deliveries", handlers.GetNotificationDeliveries)
+
+		// Video processing job endpoints
+		admin.GET("/video-jobs", handlers.GetVideoJobList)
+		admin.POST("/video-jobs/:id/retry", handlers.RetryVideoJob)
+
+		// Stats endpoints
+		admin.POST("/teams/:teamId/stats/rebuild", handlers.RebuildTeamStats)
+	}
+
+	// Recruiter API Routes
+	recruiter := auth.Group("/recruiter")
+	recruiter.Use(middleware.RequireRole(models.RoleRecruiter))
+	{
+		recruiter.GET("/account", handlers.GetRecruiterAccount)
+		recruiter.PUT("/account", handlers.SaveRecruiterAccount)
+		recruiter.GET("/profiles", handlers.SearchRecruiterDirectory)
+		recruiter.GET("/profiles/:profileId", handlers.GetRecruiterProfile)
+		recruiter.POST("/profiles/:profileId/access-requests", handlers.RequestProfileAccess)
+		recruiter.GET("/access-requests", handlers.GetRecruiterAccessRequests)
+		recruiter.DELETE("/access-requests/:permissionId", handlers.WithdrawProfileAccess)
 	}
 
 	return r
 }
-
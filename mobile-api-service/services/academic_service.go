@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAcademicEntryNotFound = errors.New("academic entry not found")
+	ErrAcademicTermExists    = errors.New("an entry for this term already exists")
+	ErrEligibilityRuleOrder  = errors.New("watch thresholds must be no stricter than the eligibility thresholds")
+)
+
+// academicDateLayout is the format of term end dates in requests
+const academicDateLayout = "2006-01-02"
+
+// latestTermFirst orders entries by the end of their term. Entries without
+// an end date come after dated ones, latest entered first.
+func latestTermFirst(db *gorm.DB) *gorm.DB {
+	return db.Order("term_end_date IS NULL, term_end_date DESC, created_at DESC, id DESC")
+}
+
+// ListAcademicEntries returns a player's entries, latest term first
+func ListAcademicEntries(ctx context.Context, playerID uint) ([]models.AcademicEntry, error) {
+	entries := []models.AcademicEntry{}
+	err := database.DB.WithContext(ctx).Where("player_id = ?", playerID).
+		Scopes(latestTermFirst).Find(&entries).Error
+	return entries, err
+}
+
+func GetAcademicEntry(ctx context.Context, entryID uint) (*models.AcademicEntry, error) {
+	var entry models.AcademicEntry
+	if err := database.DB.WithContext(ctx).First(&entry, entryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAcademicEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func academicTermTaken(ctx context.Context, playerID uint, term string, exceptID uint) (bool, error) {
+	var count int64
+	err := database.DB.WithContext(ctx).Model(&models.AcademicEntry{}).
+		Where("player_id = ? AND term = ? AND id <> ?", playerID, term, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateAcademicEntry records a term's grades and re-evaluates the player's eligibility
+func CreateAcademicEntry(ctx context.Context, playerID, enteredBy uint, req *models.CreateAcademicEntryRequest) (*models.AcademicEntry, error) {
+	taken, err := academicTermTaken(ctx, playerID, req.Term, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrAcademicTermExists
+	}
+
+	// The binding already checked the date
+	termEnd, _ := time.Parse(academicDateLayout, req.TermEndDate)
+	entry := &models.AcademicEntry{
+		PlayerID:      playerID,
+		Term:          req.Term,
+		TermEndDate:   &termEnd,
+		GPA:           req.GPA,
+		FailingGrades: req.FailingGrades,
+		Notes:         req.Notes,
+		EnteredBy:     enteredBy,
+	}
+	if err := database.DB.WithContext(ctx).Create(entry).Error; err != nil {
+		return nil, err
+	}
+	if err := EvaluateEligibility(ctx, playerID); err != nil {
+		log.Printf("Failed to evaluate eligibility of player %d: %v", playerID, err)
+	}
+	return entry, nil
+}
+
+func UpdateAcademicEntry(ctx context.Context, entry *models.AcademicEntry, req *models.UpdateAcademicEntryRequest) error {
+	updates := map[string]interface{}{}
+	if req.Term != nil {
+		taken, err := academicTermTaken(ctx, entry.PlayerID, *req.Term, entry.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrAcademicTermExists
+		}
+		updates["term"] = *req.Term
+	}
+	if req.TermEndDate != nil {
+		termEnd, _ := time.Parse(academicDateLayout, *req.TermEndDate)
+		updates["term_end_date"] = termEnd
+	}
+	if req.GPA != nil {
+		updates["gpa"] = *req.GPA
+	}
+	if req.FailingGrades != nil {
+		updates["failing_grades"] = *req.FailingGrades
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := database.DB.WithContext(ctx).Model(entry).Updates(updates).Error; err != nil {
+		return err
+	}
+	if err := EvaluateEligibility(ctx, entry.PlayerID); err != nil {
+		log.Printf("Failed to evaluate eligibility of player %d: %v", entry.PlayerID, err)
+	}
+	return nil
+}
+
+func DeleteAcademicEntry(ctx context.Context, entry *models.AcademicEntry) error {
+	if err := database.DB.WithContext(ctx).Delete(entry).Error; err != nil {
+		return err
+	}
+	if err := EvaluateEligibility(ctx, entry.PlayerID); err != nil {
+		log.Printf("Failed to evaluate eligibility of player %d: %v", entry.PlayerID, err)
+	}
+	return nil
+}
+
+// GetEligibilityRule returns the organization's rule set, or nil when none is configured
+func GetEligibilityRule(ctx context.Context, organizationID uint) (*models.EligibilityRule, error) {
+	var rule models.EligibilityRule
+	err := database.DB.WithContext(ctx).Where("organization_id = ?", organizationID).First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveEligibilityRule replaces the organization's rule set and re-evaluates
+// its players in the background
+func SaveEligibilityRule(ctx context.Context, organizationID, userID uint, req *models.EligibilityRuleRequest) (*models.EligibilityRule, error) {
+	if req.MinGPA != nil && req.WatchGPA != nil && *req.WatchGPA < *req.MinGPA {
+		return nil, ErrEligibilityRuleOrder
+	}
+	if req.MaxFailingGrades != nil && req.WatchFailingGrades != nil && *req.WatchFailingGrades > *req.MaxFailingGrades {
+		return nil, ErrEligibilityRuleOrder
+	}
+
+	rule := &models.EligibilityRule{
+		OrganizationID:     organizationID,
+		MinGPA:             req.MinGPA,
+		WatchGPA:           req.WatchGPA,
+		MaxFailingGrades:   req.MaxFailingGrades,
+		WatchFailingGrades: req.WatchFailingGrades,
+		UpdatedBy:          userID,
+	}
+	if err := database.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_gpa", "watch_gpa", "max_failing_grades", "watch_failing_grades", "updated_by", "updated_at"}),
+	}).Create(rule).Error; err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := reevaluateOrganization(context.Background(), organizationID); err != nil {
+			log.Printf("Failed to re-evaluate eligibility in organization %d: %v", organizationID, err)
+		}
+	}()
+	return GetEligibilityRule(ctx, organizationID)
+}
+
+// reevaluateOrganization evaluates every active player of the organization
+// who has academic entries
+func reevaluateOrganization(ctx context.Context, organizationID uint) error {
+	var playerIDs []uint
+	if err := database.DB.WithContext(ctx).Table("team_members").
+		Joins("JOIN teams ON teams.id = team_members.team_id").
+		Where("teams.organization_id = ? AND teams.deleted_at IS NULL", organizationID).
+		Where("team_members.member_type = ? AND team_members.status = ?", models.MemberTypePlayer, models.MemberStatusActive).
+		Where("team_members.user_id IN (?)", database.DB.Model(&models.AcademicEntry{}).Select("player_id")).
+		Distinct().
+		Pluck("team_members.user_id", &playerIDs).Error; err != nil {
+		return err
+	}
+	for _, playerID := range playerIDs {
+		if err := EvaluateEligibility(ctx, playerID); err != nil {
+			log.Printf("Failed to evaluate eligibility of player %d: %v", playerID, err)
+		}
+	}
+	return nil
+}
+
+// evaluateEntry applies a rule set to an academic entry. Without a rule set
+// or an entry the player is eligible.
+func evaluateEntry(rule *models.EligibilityRule, entry *models.AcademicEntry) (uint8, []string) {
+	status := models.EligibilityEligible
+	reasons := []string{}
+	if rule == nil || entry == nil {
+		return status, reasons
+	}
+	flag := func(level uint8, reason string) {
+		status = max(status, level)
+		reasons = append(reasons, reason)
+	}
+
+	if gpa := entry.GPA; gpa != nil {
+		switch {
+		case rule.MinGPA != nil && *gpa < *rule.MinGPA:
+			flag(models.EligibilityIneligible, fmt.Sprintf("GPA %.2f is below the minimum of %.2f", *gpa, *rule.MinGPA))
+		case rule.WatchGPA != nil && *gpa < *rule.WatchGPA:
+			flag(models.EligibilityWatch, fmt.Sprintf("GPA %.2f is below the watch level of %.2f", *gpa, *rule.WatchGPA))
+		}
+	}
+	if failing := entry.FailingGrades; failing != nil {
+		switch {
+		case rule.MaxFailingGrades != nil && *failing > *rule.MaxFailingGrades:
+			flag(models.EligibilityIneligible, fmt.Sprintf("%d failing grades, more than the maximum of %d", *failing, *rule.MaxFailingGrades))
+		case rule.WatchFailingGrades != nil && *failing >= *rule.WatchFailingGrades:
+			flag(models.EligibilityWatch, fmt.Sprintf("%d failing grades, at or above the watch level of %d", *failing, *rule.WatchFailingGrades))
+		}
+	}
+	return status, reasons
+}
+
+// EvaluateEligibility applies each of the player's organizations' rule sets
+// to their latest academic entry and notifies the player's coaches and
+// parents when a status changes
+func EvaluateEligibility(ctx context.Context, playerID uint) error {
+	db := database.DB.WithContext(ctx)
+	orgIDs, err := PlayerOrganizationIDs(ctx, playerID)
+	if err != nil {
+		return err
+	}
+
+	var latest *models.AcademicEntry
+	var entry models.AcademicEntry
+	err = db.Where("player_id = ?", playerID).Scopes(latestTermFirst).Take(&entry).Error
+	switch {
+	case err == nil:
+		latest = &entry
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	now := time.Now()
+	for _, orgID := range orgIDs {
+		rule, err := GetEligibilityRule(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		status, reasons := evaluateEntry(rule, latest)
+		reasonsJSON, _ := json.Marshal(reasons)
+
+		var previous models.PlayerEligibility
+		err = db.Where("player_id = ? AND organization_id = ?", playerID, orgID).First(&previous).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		found := err == nil
+		changed := (found && previous.Status != status) || (!found && status != models.EligibilityEligible)
+
+		current := models.PlayerEligibility{
+			PlayerID:       playerID,
+			OrganizationID: orgID,
+			Status:         status,
+			Reasons:        reasonsJSON,
+			EvaluatedAt:    now,
+			ChangedAt:      now,
+		}
+		if latest != nil {
+			current.Term = latest.Term
+		}
+		if found && !changed {
+			current.ChangedAt = previous.ChangedAt
+		}
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "player_id"}, {Name: "organization_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "term", "reasons", "evaluated_at", "changed_at"}),
+		}).Create(&current).Error; err != nil {
+			return err
+		}
+
+		if changed {
+			notifyEligibilityChange(ctx, &current, reasons)
+		}
+	}
+	return nil
+}
+
+// notifyEligibilityChange tells the player's coaches in the organization and
+// their parents about a new status
+func notifyEligibilityChange(ctx context.Context, eligibility *models.PlayerEligibility, reasons []string) {
+	var coachIDs []uint
+	if err := database.DB.WithContext(ctx).Table("team_members AS coach").
+		Joins("JOIN team_members AS player ON player.team_id = coach.team_id").
+		Joins("JOIN teams ON teams.id = coach.team_id").
+		Where("teams.organization_id = ? AND teams.deleted_at IS NULL", eligibility.OrganizationID).
+		Where("coach.member_type = ? AND coach.status = ?", models.MemberTypeCoach, models.MemberStatusActive).
+		Where("player.user_id = ? AND player.member_type = ? AND player.status = ?",
+			eligibility.PlayerID, models.MemberTypePlayer, models.MemberStatusActive).
+		Distinct().
+		Pluck("coach.user_id", &coachIDs).Error; err != nil {
+		log.Printf("Failed to load coaches of player %d: %v", eligibility.PlayerID, err)
+		return
+	}
+	parentIDs, err := ParentIDsOf(ctx, []uint{eligibility.PlayerID})
+	if err != nil {
+		log.Printf("Failed to load parents of player %d: %v", eligibility.PlayerID, err)
+		return
+	}
+
+	seen := make(map[uint]bool)
+	var audience []uint
+	for _, id := range append(coachIDs, parentIDs...) {
+		if !seen[id] {
+			seen[id] = true
+			audience = append(audience, id)
+		}
+	}
+	if len(audience) == 0 {
+		return
+	}
+
+	var player models.User
+	database.DB.WithContext(ctx).Select("id", "name").First(&player, eligibility.PlayerID)
+	reason := "meets the academic requirements"
+	if len(reasons) > 0 {
+		reason = strings.Join(reasons, "; ")
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"player_id":       eligibility.PlayerID,
+		"organization_id": eligibility.OrganizationID,
+		"status":          eligibility.Status,
+		"term":            eligibility.Term,
+	})
+	NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+		UserIDs: audience,
+		Type:    models.NotificationTypeEligibility,
+		Variables: map[string]string{
+			"player_name": player.Name,
+			"status":      models.EligibilityStatusName(eligibility.Status),
+			"reason":      reason,
+		},
+		Data: data,
+	})
+}
+
+// GetPlayerEligibility returns the player's status in each organization
+func GetPlayerEligibility(ctx context.Context, playerID uint) ([]models.PlayerEligibility, error) {
+	statuses := []models.PlayerEligibility{}
+	err := database.DB.WithContext(ctx).Where("player_id = ?", playerID).
+		Order("organization_id").Find(&statuses).Error
+	return statuses, err
+}
+
+// GetTeamEligibility lists the roster with each player's status in the
+// team's organization, ineligible players first
+func GetTeamEligibility(ctx context.Context, team *models.Team) ([]models.TeamEligibilityLine, error) {
+	roster, err := teamRoster(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(roster))
+	for i, player := range roster {
+		ids[i] = player.UserID
+	}
+
+	var statuses []models.PlayerEligibility
+	if len(ids) > 0 {
+		if err := database.DB.WithContext(ctx).
+			Where("organization_id = ? AND player_id IN ?", team.OrganizationID, ids).
+			Find(&statuses).Error; err != nil {
+			return nil, err
+		}
+	}
+	byPlayer := make(map[uint]*models.PlayerEligibility, len(statuses))
+	for i := range statuses {
+		byPlayer[statuses[i].PlayerID] = &statuses[i]
+	}
+
+	lines := make([]models.TeamEligibilityLine, len(roster))
+	for i, player := range roster {
+		lines[i] = models.TeamEligibilityLine{
+			PlayerID:     player.UserID,
+			PlayerName:   player.Name,
+			JerseyNumber: player.JerseyNumber,
+			Eligibility:  byPlayer[player.UserID],
+		}
+	}
+	statusOf := func(line models.TeamEligibilityLine) uint8 {
+		if line.Eligibility == nil {
+			return models.EligibilityEligible
+		}
+		return line.Eligibility.Status
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		if statusOf(lines[i]) != statusOf(lines[j]) {
+			return statusOf(lines[i]) > statusOf(lines[j])
+		}
+		return lines[i].PlayerName < lines[j].PlayerName
+	})
+	return lines, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+)
+
+// IsTeamCoach reports whether the user is an active coach on the team roster
+func IsTeamCoach(ctx context.Context, userID, teamID uint) bool {
+	var count int64
+	database.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("team_id = ? AND user_id = ? AND member_type = ? AND status = ?",
+			teamID, userID, models.MemberTypeCoach, models.MemberStatusActive).
+		Count(&count)
+	return count > 0
+}
+
+// IsTeamMember reports whether the user is an active coach or player on the team roster
+func IsTeamMember(ctx context.Context, userID, teamID uint) bool {
+	var count int64
+	database.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("team_id = ? AND user_id = ? AND status = ?", teamID, userID, models.MemberStatusActive).
+		Count(&count)
+	return count > 0
+}
+
+// IsParentOf reports whether the user has an approved parent link to the player
+func IsParentOf(ctx context.Context, parentID, playerID uint) bool {
+	var count int64
+	database.DB.WithContext(ctx).Model(&models.ParentPlayer{}).
+		Where("parent_id = ? AND player_id = ? AND status = ?", parentID, playerID, models.ParentLinkApproved).
+		Count(&count)
+	return count > 0
+}
+
+// TeamPlayerIDs returns the active players on the team roster
+func TeamPlayerIDs(ctx context.Context, teamID uint) ([]uint, error) {
+	var ids []uint
+	err := database.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("team_id = ? AND member_type = ? AND status = ?", teamID, models.MemberTypePlayer, models.MemberStatusActive).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// rosterPlayer is an active player of a team with their name and jersey
+type rosterPlayer struct {
+	UserID       uint
+	Name         string
+	JerseyNumber *int
+}
+
+func teamRoster(ctx context.Context, teamID uint) ([]rosterPlayer, error) {
+	var roster []rosterPlayer
+	err := database.DB.WithContext(ctx).Table("team_members").
+		Select("team_members.user_id, users.name, team_members.jersey_number").
+		Joins("JOIN users ON users.id = team_members.user_id").
+		Where("team_members.team_id = ? AND team_members.member_type = ? AND team_members.status = ?",
+			teamID, models.MemberTypePlayer, models.MemberStatusActive).
+		Scan(&roster).Error
+	return roster, err
+}
+
+// ParentIDsOf returns the approved parents linked to any of the players
+func ParentIDsOf(ctx context.Context, playerIDs []uint) ([]uint, error) {
+	if len(playerIDs) == 0 {
+		return nil, nil
+	}
+	var ids []uint
+	err := database.DB.WithContext(ctx).Model(&models.ParentPlayer{}).
+		Where("player_id IN ? AND status = ?", playerIDs, models.ParentLinkApproved).
+		Distinct().
+		Pluck("parent_id", &ids).Error
+	return ids, err
+}
+
+// TeamAudienceIDs returns the active players of the team plus their approved parents, without duplicates
+func TeamAudienceIDs(ctx context.Context, teamID uint) ([]uint, error) {
+	players, err := TeamPlayerIDs(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := ParentIDsOf(ctx, players)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(players)+len(parents))
+	audience := make([]uint, 0, len(players)+len(parents))
+	for _, id := range append(players, parents...) {
+		if !seen[id] {
+			seen[id] = true
+			audience = append(audience, id)
+		}
+	}
+	return audience, nil
+}
+
+// IsTeamPlayer reports whether the user is an active player on the team roster
+func IsTeamPlayer(ctx context.Context, userID, teamID uint) bool {
+	var count int64
+	database.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("team_id = ? AND user_id = ? AND member_type = ? AND status = ?",
+			teamID, userID, models.MemberTypePlayer, models.MemberStatusActive).
+		Count(&count)
+	return count > 0
+}
+
+// IsCoachOfPlayer reports whether the user actively coaches any team the player is rostered on
+func IsCoachOfPlayer(ctx context.Context, coachID, playerID uint) bool {
+	var count int64
+	database.DB.WithContext(ctx).Table("team_members AS coach").
+		Joins("JOIN team_members AS player ON player.team_id = coach.team_id").
+		Where("coach.user_id = ? AND coach.member_type = ? AND coach.status = ?", coachID, models.MemberTypeCoach, models.MemberStatusActive).
+		Where("player.user_id = ? AND player.member_type = ? AND player.status = ?", playerID, models.MemberTypePlayer, models.MemberStatusActive).
+		Count(&count)
+	return count > 0
+}
+
+// PlayerOrganizationIDs returns the organizations of the teams the player is rostered on
+func PlayerOrganizationIDs(ctx context.Context, playerID uint) ([]uint, error) {
+	var ids []uint
+	err := database.DB.WithContext(ctx).Model(&models.Team{}).
+		Joins("JOIN team_members ON team_members.team_id = teams.id").
+		Where("team_members.user_id = ? AND team_members.member_type = ? AND team_members.status = ?",
+			playerID, models.MemberTypePlayer, models.MemberStatusActive).
+		Distinct().
+		Pluck("teams.organization_id", &ids).Error
+	return ids, err
+}
+
+// PlayerTeamIDs returns every team the player is or was rostered on
+func PlayerTeamIDs(ctx context.Context, playerID uint) ([]uint, error) {
+	var ids []uint
+	err := database.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("user_id = ? AND member_type = ?", playerID, models.MemberTypePlayer).
+		Distinct().
+		Pluck("team_id", &ids).Error
+	return ids, err
+}
+
+// IsParentOnTeam reports whether the user is an approved parent of an active player on the team
+func IsParentOnTeam(ctx context.Context, parentID, teamID uint) bool {
+	var count int64
+	database.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Joins("JOIN parent_players ON parent_players.player_id = team_members.user_id").
+		Where("parent_players.parent_id = ? AND parent_players.status = ?", parentID, models.ParentLinkApproved).
+		Where("team_members.team_id = ? AND team_members.member_type = ? AND team_members.status = ?",
+			teamID, models.MemberTypePlayer, models.MemberStatusActive).
+		Count(&count)
+	return count > 0
+}
+
+// OrganizationUserIDs returns which of the users belong to one of the
+// organizations: active coaches and players of its teams, and the approved
+// parents of those players
+func OrganizationUserIDs(ctx context.Context, organizationIDs, userIDs []uint) ([]uint, error) {
+	if len(organizationIDs) == 0 || len(userIDs) == 0 {
+		return nil, nil
+	}
+	db := database.DB.WithContext(ctx)
+	var members []uint
+	if err := db.Model(&models.TeamMember{}).
+		Joins("JOIN teams ON teams.id = team_members.team_id").
+		Where("teams.organization_id IN ? AND team_members.status = ? AND team_members.user_id IN ?",
+			organizationIDs, models.MemberStatusActive, userIDs).
+		Distinct().
+		Pluck("team_members.user_id", &members).Error; err != nil {
+		return nil, err
+	}
+	var parents []uint
+	if err := db.Model(&models.ParentPlayer{}).
+		Joins("JOIN team_members ON team_members.user_id = parent_players.player_id").
+		Joins("JOIN teams ON teams.id = team_members.team_id").
+		Where("teams.organization_id IN ? AND team_members.member_type = ? AND team_members.status = ?",
+			organizationIDs, models.MemberTypePlayer, models.MemberStatusActive).
+		Where("parent_players.parent_id IN ? AND parent_players.status = ?", userIDs, models.ParentLinkApproved).
+		Distinct().
+		Pluck("parent_players.parent_id", &parents).Error; err != nil {
+		return nil, err
+	}
+	return append(members, parents...), nil
+}
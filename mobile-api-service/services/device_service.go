@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrDeviceNotFound = errors.New("device not found")
+
+// RegisterDevice stores a device token for the user. Registering a token or
+// device ID the user already has reactivates and refreshes that row instead of duplicating it.
+func RegisterDevice(ctx context.Context, userID uint, req *models.RegisterDeviceRequest) (*models.NotificationDevice, error) {
+	db := database.DB.WithContext(ctx)
+
+	// A token belongs to one install; drop it from any other account that still holds it
+	db.Model(&models.NotificationDevice{}).
+		Where("device_token = ? AND user_id <> ?", req.DeviceToken, userID).
+		Update("is_active", false)
+
+	var device models.NotificationDevice
+	query := db.Where("user_id = ? AND platform = ?", userID, req.Platform)
+	if req.DeviceID != "" {
+		query = query.Where("device_id = ? OR device_token = ?", req.DeviceID, req.DeviceToken)
+	} else {
+		query = query.Where("device_token = ?", req.DeviceToken)
+	}
+	err := query.First(&device).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	device.UserID = userID
+	device.Platform = req.Platform
+	device.DeviceToken = req.DeviceToken
+	if req.DeviceID != "" {
+		device.DeviceID = req.DeviceID
+	}
+	device.IsActive = true
+	device.LastUsedAt = time.Now()
+
+	if err := db.Save(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// RefreshDeviceToken replaces the token of one of the user's devices after the OS rotated it
+func RefreshDeviceToken(ctx context.Context, userID, deviceID uint, token string) (*models.NotificationDevice, error) {
+	var device models.NotificationDevice
+	err := database.DB.WithContext(ctx).Where("id = ? AND user_id = ?", deviceID, userID).First(&device).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeviceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	device.DeviceToken = token
+	device.IsActive = true
+	device.LastUsedAt = time.Now()
+	if err := database.DB.WithContext(ctx).Save(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// DeactivateDevice stops pushes to one of the user's devices (e.g. on logout)
+func DeactivateDevice(ctx context.Context, userID, deviceID uint) error {
+	result := database.DB.WithContext(ctx).Model(&models.NotificationDevice{}).
+		Where("id = ? AND user_id = ?", deviceID, userID).
+		Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// PruneStaleDevices deactivates devices that have not been used since the cutoff
+func PruneStaleDevices(ctx context.Context, maxAge time.Duration) (int64, error) {
+	result := database.DB.WithContext(ctx).Model(&models.NotificationDevice{}).
+		Where("is_active = ? AND last_used_at < ?", true, time.Now().Add(-maxAge)).
+		Update("is_active", false)
+	return result.RowsAffected, result.Error
+}
+
+// StartDevicePruner runs PruneStaleDevices once a day in the background
+func StartDevicePruner() {
+	days, err := strconv.Atoi(config.AppConfig.DeviceStaleDays)
+	if err != nil || days <= 0 {
+		days = 90
+	}
+	maxAge := time.Duration(days) * 24 * time.Hour
+
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			pruned, err := PruneStaleDevices(context.Background(), maxAge)
+			if err != nil {
+				log.Printf("Failed to prune stale devices: %v", err)
+			} else if pruned > 0 {
+				log.Printf("Deactivated %d stale devices", pruned)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// PushChannelSender delivers the push channel to every active device of the
+// recipient through the sender registered for the device platform
+type PushChannelSender struct {
+	Senders map[uint8]PushSender
+}
+
+func (p *PushChannelSender) Send(ctx context.Context, msg *ChannelMessage) error {
+	var devices []models.NotificationDevice
+	err := database.DB.WithContext(ctx).
+		Where("user_id = ? AND is_active = ?", msg.User.ID, true).
+		Find(&devices).Error
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return Permanent(errors.New("no active devices"))
+	}
+
+	push := &PushMessage{
+		Title: msg.Subject,
+		Body:  msg.Body,
+		Data: map[string]string{
+			"notification_id": strconv.FormatUint(uint64(msg.Notification.ID), 10),
+			"type":            msg.Notification.Type,
+		},
+	}
+	if len(msg.Data) > 0 {
+		push.Data["data"] = string(msg.Data)
+	}
+
+	var lastErr error
+	delivered := 0
+	for i := range devices {
+		device := &devices[i]
+		sender, ok := p.Senders[device.Platform]
+		if !ok {
+			continue
+		}
+
+		err := sender.Push(ctx, device.DeviceToken, push)
+		switch {
+		case err == nil:
+			delivered++
+			database.DB.WithContext(ctx).Model(device).Update("last_used_at", time.Now())
+		case errors.Is(err, ErrInvalidDeviceToken):
+			database.DB.WithContext(ctx).Model(device).Update("is_active", false)
+			log.Printf("Deactivated device %d of user %d: provider rejected token", device.ID, device.UserID)
+		default:
+			lastErr = err
+		}
+	}
+
+	if delivered > 0 {
+		return nil
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return Permanent(fmt.Errorf("no deliverable devices for user %d", msg.User.ID))
+}
+
+// NewPushChannelSender builds the push channel from configuration, falling
+// back to LogPushSender for providers without credentials
+func NewPushChannelSender() *PushChannelSender {
+	var fcm PushSender = LogPushSender{Provider: "fcm"}
+	if config.AppConfig.FCMProjectID != "" {
+		tokens, err := NewServiceAccountTokenSourceFromFile(config.AppConfig.FCMCredentialsFile)
+		if err != nil {
+			log.Printf("Failed to load FCM service account, logging Android and web pushes instead: %v", err)
+		} else {
+			fcm = &FCMSender{
+				BaseURL:     config.AppConfig.FCMBaseURL,
+				ProjectID:   config.AppConfig.FCMProjectID,
+				TokenSource: tokens.Token,
+			}
+		}
+	}
+
+	var apns PushSender = LogPushSender{Provider: "apns"}
+	if config.AppConfig.APNsKeyPath != "" {
+		sender, err := NewAPNsSenderFromFile(
+			config.AppConfig.APNsBaseURL,
+			config.AppConfig.APNsKeyPath,
+			config.AppConfig.APNsKeyID,
+			config.AppConfig.APNsTeamID,
+			config.AppConfig.APNsTopic,
+		)
+		if err != nil {
+			log.Printf("Failed to load APNs key, logging iOS pushes instead: %v", err)
+		} else {
+			apns = sender
+		}
+	}
+
+	return &PushChannelSender{Senders: map[uint8]PushSender{
+		models.PlatformIOS:     apns,
+		models.PlatformAndroid: fcm,
+		models.PlatformWeb:     fcm,
+	}}
+}
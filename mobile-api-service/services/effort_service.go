@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEventNotRateable = errors.New("effort can only be rated for practices and games")
+	ErrEventNotStarted  = errors.New("effort can only be rated once the event has started")
+)
+
+// Score scale of effort_metrics and buy_in_scores
+const (
+	MinEffortScore = 1
+	MaxEffortScore = 10
+)
+
+// Thresholds of the drop alerts: a metric that fell at each of the last
+// declineStreakAlert events, a rolling average averageDropAlert below the
+// player's earlier average, or a score sharpDropAlert below the rolling
+// average before it
+const (
+	declineStreakAlert = 3
+	averageDropAlert   = 1.5
+	sharpDropAlert     = 3.0
+)
+
+var scoreMetricLabels = map[string]string{
+	models.ScoreMetricHustle:     "Hustle",
+	models.ScoreMetricEngagement: "Engagement",
+	models.ScoreMetricBuyIn:      "Buy-in",
+}
+
+// LoadEffortEvent loads a practice or game that can be rated
+func LoadEffortEvent(ctx context.Context, eventID uint) (*models.Event, error) {
+	var event models.Event
+	if err := database.DB.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return nil, ErrEventNotFound
+	}
+	if event.Type != models.EventTypePractice && event.Type != models.EventTypeGame {
+		return &event, ErrEventNotRateable
+	}
+	if event.Status == models.EventStatusCancelled {
+		return &event, ErrEventCancelled
+	}
+	if event.StartTime.After(time.Now()) {
+		return &event, ErrEventNotStarted
+	}
+	return &event, nil
+}
+
+// scoreProblems checks scores against the 1–10 scale; the database CHECK is
+// only the last line of defence
+func scoreProblems(scores map[string]int) []string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var problems []string
+	for _, name := range names {
+		if score := scores[name]; score < MinEffortScore || score > MaxEffortScore {
+			problems = append(problems, fmt.Sprintf("%s must be between %d and %d", name, MinEffortScore, MaxEffortScore))
+		}
+	}
+	return problems
+}
+
+// effortRoster returns who can be rated for an event: the active roster plus
+// players already rated for it
+func effortRoster(ctx context.Context, event *models.Event, rated interface{}) (map[uint]bool, error) {
+	roster, err := TeamPlayerIDs(ctx, event.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	var recorded []uint
+	if err := database.DB.WithContext(ctx).Model(rated).
+		Where("event_id = ?", event.ID).Pluck("player_id", &recorded).Error; err != nil {
+		return nil, err
+	}
+	allowed := make(map[uint]bool, len(roster)+len(recorded))
+	for _, id := range append(roster, recorded...) {
+		allowed[id] = true
+	}
+	return allowed, nil
+}
+
+// scoreRowProblems adds the roster and duplicate checks of a bulk score entry
+func scoreRowProblems(allowed, seen map[uint]bool, playerID uint, problems []string) []string {
+	if !allowed[playerID] {
+		problems = append(problems, "player is not on the team roster")
+	}
+	if seen[playerID] {
+		problems = append(problems, "player appears more than once")
+	}
+	seen[playerID] = true
+	return problems
+}
+
+// SaveEffortMetrics upserts the hustle and engagement scores of an event for
+// the roster. Nothing is saved when any line is invalid.
+func SaveEffortMetrics(ctx context.Context, event *models.Event, enteredBy uint, lines []models.EffortLine) error {
+	allowed, err := effortRoster(ctx, event, &models.EffortMetric{})
+	if err != nil {
+		return err
+	}
+	var rows []GameStatRowError
+	seen := make(map[uint]bool, len(lines))
+	metrics := make([]models.EffortMetric, len(lines))
+	for i, line := range lines {
+		problems := scoreProblems(map[string]int{
+			"hustle_score":     line.HustleScore,
+			"engagement_score": line.EngagementScore,
+		})
+		if problems = scoreRowProblems(allowed, seen, line.PlayerID, problems); len(problems) > 0 {
+			rows = append(rows, GameStatRowError{Index: i, PlayerID: line.PlayerID, Errors: problems})
+			continue
+		}
+		metrics[i] = models.EffortMetric{
+			EventID:         event.ID,
+			PlayerID:        line.PlayerID,
+			HustleScore:     line.HustleScore,
+			EngagementScore: line.EngagementScore,
+			Notes:           line.Notes,
+			EnteredBy:       enteredBy,
+		}
+	}
+	if len(rows) > 0 {
+		return &GameStatValidationError{Rows: rows}
+	}
+
+	return database.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "player_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hustle_score", "engagement_score", "notes", "entered_by", "updated_at"}),
+	}).Create(&metrics).Error
+}
+
+// SaveBuyInScores upserts the buy-in scores of an event for the roster
+func SaveBuyInScores(ctx context.Context, event *models.Event, enteredBy uint, lines []models.BuyInLine) error {
+	allowed, err := effortRoster(ctx, event, &models.BuyInScore{})
+	if err != nil {
+		return err
+	}
+	var rows []GameStatRowError
+	seen := make(map[uint]bool, len(lines))
+	scores := make([]models.BuyInScore, len(lines))
+	for i, line := range lines {
+		problems := scoreProblems(map[string]int{"score": line.Score})
+		if problems = scoreRowProblems(allowed, seen, line.PlayerID, problems); len(problems) > 0 {
+			rows = append(rows, GameStatRowError{Index: i, PlayerID: line.PlayerID, Errors: problems})
+			continue
+		}
+		scores[i] = models.BuyInScore{
+			EventID:   event.ID,
+			PlayerID:  line.PlayerID,
+			Score:     line.Score,
+			Notes:     line.Notes,
+			EnteredBy: enteredBy,
+		}
+	}
+	if len(rows) > 0 {
+		return &GameStatValidationError{Rows: rows}
+	}
+
+	return database.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "player_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "notes", "entered_by", "updated_at"}),
+	}).Create(&scores).Error
+}
+
+// scoredPlayers returns the team's roster followed by the given players that
+// are no longer on it, so past ratings keep a name
+func scoredPlayers(ctx context.Context, teamID uint, playerIDs []uint) ([]rosterPlayer, error) {
+	roster, err := teamRoster(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(roster, func(i, j int) bool {
+		a, b := roster[i].JerseyNumber, roster[j].JerseyNumber
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		if a != nil && *a != *b {
+			return *a < *b
+		}
+		return roster[i].Name < roster[j].Name
+	})
+	onRoster := make(map[uint]bool, len(roster))
+	for _, player := range roster {
+		onRoster[player.UserID] = true
+	}
+	var missing []uint
+	for _, id := range playerIDs {
+		if !onRoster[id] {
+			onRoster[id] = true
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		var former []rosterPlayer
+		if err := database.DB.WithContext(ctx).Table("users").Select("id AS user_id, name").
+			Where("id IN ?", missing).Order("name").Scan(&former).Error; err != nil {
+			return nil, err
+		}
+		roster = append(roster, former...)
+	}
+	return roster, nil
+}
+
+// GetEventEffort returns the effort and buy-in sheet of an event for the roster
+func GetEventEffort(ctx context.Context, event *models.Event) ([]models.EventEffortLine, error) {
+	db := database.DB.WithContext(ctx)
+	var metrics []models.EffortMetric
+	if err := db.Where("event_id = ?", event.ID).Find(&metrics).Error; err != nil {
+		return nil, err
+	}
+	var buyIns []models.BuyInScore
+	if err := db.Where("event_id = ?", event.ID).Find(&buyIns).Error; err != nil {
+		return nil, err
+	}
+
+	var rated []uint
+	effortOf := make(map[uint]*models.EffortMetric, len(metrics))
+	for i := range metrics {
+		effortOf[metrics[i].PlayerID] = &metrics[i]
+		rated = append(rated, metrics[i].PlayerID)
+	}
+	buyInOf := make(map[uint]*models.BuyInScore, len(buyIns))
+	for i := range buyIns {
+		buyInOf[buyIns[i].PlayerID] = &buyIns[i]
+		rated = append(rated, buyIns[i].PlayerID)
+	}
+	players, err := scoredPlayers(ctx, event.TeamID, rated)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]models.EventEffortLine, len(players))
+	for i, player := range players {
+		lines[i] = models.EventEffortLine{
+			PlayerID:     player.UserID,
+			PlayerName:   player.Name,
+			JerseyNumber: player.JerseyNumber,
+			Effort:       effortOf[player.UserID],
+			BuyIn:        buyInOf[player.UserID],
+		}
+	}
+	return lines, nil
+}
+
+// analyzeScores computes the rolling averages, streak and drop alerts of one
+// metric; points are in event order
+func analyzeScores(metric string, points []models.ScorePoint, window int) models.ScoreTrend {
+	trend := models.ScoreTrend{Metric: metric, Points: points, Alerts: []models.ScoreAlert{}}
+	if len(points) == 0 {
+		trend.Points = []models.ScorePoint{}
+		return trend
+	}
+
+	mean := func(from, to int) float64 {
+		total := 0
+		for _, point := range points[from:to] {
+			total += point.Score
+		}
+		return float64(total) / float64(to-from)
+	}
+	for i := range points {
+		points[i].RollingAverage = round1(mean(max(0, i+1-window), i+1))
+	}
+	n := len(points)
+	latest := points[n-1].Score
+	average := round1(mean(0, n))
+	rolling := points[n-1].RollingAverage
+	trend.Latest, trend.Average, trend.RollingAverage = &latest, &average, &rolling
+
+	if n > 1 {
+		direction := func(i int) string {
+			switch {
+			case points[i].Score < points[i-1].Score:
+				return "down"
+			case points[i].Score > points[i-1].Score:
+				return "up"
+			}
+			return "flat"
+		}
+		trend.Streak.Direction = direction(n - 1)
+		for i := n - 1; i > 0 && direction(i) == trend.Streak.Direction; i-- {
+			trend.Streak.Length++
+		}
+	}
+
+	label := scoreMetricLabels[metric]
+	if trend.Streak.Direction == "down" && trend.Streak.Length >= declineStreakAlert {
+		from := points[n-1-trend.Streak.Length].Score
+		trend.Alerts = append(trend.Alerts, models.ScoreAlert{
+			Metric: metric, Kind: models.ScoreAlertDeclineStreak,
+			From: float64(from), To: float64(latest),
+			Message: fmt.Sprintf("%s fell at each of the last %d events (%d → %d)", label, trend.Streak.Length, from, latest),
+		})
+	}
+	if n >= 2*window {
+		baseline := round1(mean(0, n-window))
+		if baseline-rolling >= averageDropAlert {
+			trend.Alerts = append(trend.Alerts, models.ScoreAlert{
+				Metric: metric, Kind: models.ScoreAlertAverageDrop,
+				From: baseline, To: rolling,
+				Message: fmt.Sprintf("%s averaged %.1f over the last %d events, down from %.1f", label, rolling, window, baseline),
+			})
+		}
+	}
+	if n > 1 {
+		previous := points[n-2].RollingAverage
+		if previous-float64(latest) >= sharpDropAlert {
+			trend.Alerts = append(trend.Alerts, models.ScoreAlert{
+				Metric: metric, Kind: models.ScoreAlertSharpDrop,
+				From: previous, To: float64(latest),
+				Message: fmt.Sprintf("%s dropped to %d from an average of %.1f", label, latest, previous),
+			})
+		}
+	}
+	return trend
+}
+
+// EffortTrendFilter narrows trends to one player; Sessions is how many of the
+// team's latest rated events are analyzed and Window the rolling average size
+type EffortTrendFilter struct {
+	PlayerID uint
+	Sessions int
+	Window   int
+}
+
+// GetEffortTrends analyzes the hustle, engagement and buy-in scores of the
+// team's players over its latest rated events
+func GetEffortTrends(ctx context.Context, teamID uint, filter EffortTrendFilter) ([]models.PlayerEffortTrend, error) {
+	db := database.DB.WithContext(ctx)
+	var events []models.Event
+	if err := db.Select("id", "type", "title", "start_time").
+		Where("team_id = ? AND status <> ?", teamID, models.EventStatusCancelled).
+		Where("EXISTS (SELECT 1 FROM effort_metrics WHERE effort_metrics.event_id = events.id) OR EXISTS (SELECT 1 FROM buy_in_scores WHERE buy_in_scores.event_id = events.id)").
+		Order("start_time DESC").Limit(filter.Sessions).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].StartTime.Before(events[j].StartTime) })
+	eventIDs := make([]uint, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	var metrics []models.EffortMetric
+	var buyIns []models.BuyInScore
+	if len(eventIDs) > 0 {
+		scope := func() *gorm.DB {
+			query := db.Where("event_id IN ?", eventIDs)
+			if filter.PlayerID != 0 {
+				query = query.Where("player_id = ?", filter.PlayerID)
+			}
+			return query
+		}
+		if err := scope().Find(&metrics).Error; err != nil {
+			return nil, err
+		}
+		if err := scope().Find(&buyIns).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	type eventScores struct {
+		effort *models.EffortMetric
+		buyIn  *models.BuyInScore
+	}
+	scores := make(map[uint]map[uint]*eventScores)
+	at := func(playerID, eventID uint) *eventScores {
+		if scores[playerID] == nil {
+			scores[playerID] = make(map[uint]*eventScores)
+		}
+		if scores[playerID][eventID] == nil {
+			scores[playerID][eventID] = &eventScores{}
+		}
+		return scores[playerID][eventID]
+	}
+	var rated []uint
+	for i := range metrics {
+		at(metrics[i].PlayerID, metrics[i].EventID).effort = &metrics[i]
+		rated = append(rated, metrics[i].PlayerID)
+	}
+	for i := range buyIns {
+		at(buyIns[i].PlayerID, buyIns[i].EventID).buyIn = &buyIns[i]
+		rated = append(rated, buyIns[i].PlayerID)
+	}
+
+	players, err := scoredPlayers(ctx, teamID, rated)
+	if err != nil {
+		return nil, err
+	}
+	window := filter.Window
+	trends := []models.PlayerEffortTrend{}
+	for _, player := range players {
+		if filter.PlayerID != 0 && player.UserID != filter.PlayerID {
+			continue
+		}
+		var hustle, engagement, buyIn []models.ScorePoint
+		for _, event := range events {
+			s := scores[player.UserID][event.ID]
+			if s == nil {
+				continue
+			}
+			point := models.ScorePoint{EventID: event.ID, EventType: event.Type, Title: event.Title, StartTime: event.StartTime}
+			if s.effort != nil {
+				point.Score = s.effort.HustleScore
+				hustle = append(hustle, point)
+				point.Score = s.effort.EngagementScore
+				engagement = append(engagement, point)
+			}
+			if s.buyIn != nil {
+				point.Score = s.buyIn.Score
+				buyIn = append(buyIn, point)
+			}
+		}
+		trends = append(trends, models.PlayerEffortTrend{
+			PlayerID:     player.UserID,
+			PlayerName:   player.Name,
+			JerseyNumber: player.JerseyNumber,
+			Hustle:       analyzeScores(models.ScoreMetricHustle, hustle, window),
+			Engagement:   analyzeScores(models.ScoreMetricEngagement, engagement, window),
+			BuyIn:        analyzeScores(models.ScoreMetricBuyIn, buyIn, window),
+		})
+	}
+	return trends, nil
+}
+
+// GetCheckInList returns the players with a drop alert on any metric, the
+// most alerts and the steepest drop first
+func GetCheckInList(ctx context.Context, teamID uint, filter EffortTrendFilter) ([]models.CheckInPlayer, error) {
+	trends, err := GetEffortTrends(ctx, teamID, filter)
+	if err != nil {
+		return nil, err
+	}
+	list := []models.CheckInPlayer{}
+	steepest := make(map[uint]float64)
+	for _, trend := range trends {
+		var alerts []models.ScoreAlert
+		for _, metric := range []models.ScoreTrend{trend.Hustle, trend.Engagement, trend.BuyIn} {
+			alerts = append(alerts, metric.Alerts...)
+			for _, alert := range metric.Alerts {
+				steepest[trend.PlayerID] = math.Max(steepest[trend.PlayerID], alert.From-alert.To)
+			}
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+		list = append(list, models.CheckInPlayer{
+			PlayerID:     trend.PlayerID,
+			PlayerName:   trend.PlayerName,
+			JerseyNumber: trend.JerseyNumber,
+			Alerts:       alerts,
+			Latest: models.CheckInScores{
+				Hustle:     trend.Hustle.Latest,
+				Engagement: trend.Engagement.Latest,
+				BuyIn:      trend.BuyIn.Latest,
+			},
+		})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if len(list[i].Alerts) != len(list[j].Alerts) {
+			return len(list[i].Alerts) > len(list[j].Alerts)
+		}
+		return steepest[list[i].PlayerID] > steepest[list[j].PlayerID]
+	})
+	return list, nil
+}
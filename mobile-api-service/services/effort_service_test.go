@@ -0,0 +1,129 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+func TestAnalyzeScores(t *testing.T) {
+	hustle := models.ScoreMetricHustle
+	tests := []struct {
+		name    string
+		scores  []int
+		rolling []float64
+		streak  models.ScoreStreak
+		alerts  []models.ScoreAlert
+	}{
+		{
+			name:   "no scores",
+			alerts: []models.ScoreAlert{},
+		},
+		{
+			name:    "single score",
+			scores:  []int{4},
+			rolling: []float64{4},
+			alerts:  []models.ScoreAlert{},
+		},
+		{
+			name:    "flat",
+			scores:  []int{3, 3, 3},
+			rolling: []float64{3, 3, 3},
+			streak:  models.ScoreStreak{Direction: "flat", Length: 2},
+			alerts:  []models.ScoreAlert{},
+		},
+		{
+			name:    "rising streak",
+			scores:  []int{2, 3, 4, 5},
+			rolling: []float64{2, 2.5, 3, 4},
+			streak:  models.ScoreStreak{Direction: "up", Length: 3},
+			alerts:  []models.ScoreAlert{},
+		},
+		{
+			name:    "declines shorter than the alert streak",
+			scores:  []int{5, 4, 5, 4, 3},
+			rolling: []float64{5, 4.5, 4.7, 4.3, 4},
+			streak:  models.ScoreStreak{Direction: "down", Length: 2},
+			alerts:  []models.ScoreAlert{},
+		},
+		{
+			name:    "decline streak",
+			scores:  []int{5, 5, 4, 3, 2},
+			rolling: []float64{5, 5, 4.7, 4, 3},
+			streak:  models.ScoreStreak{Direction: "down", Length: 3},
+			alerts: []models.ScoreAlert{
+				{Metric: hustle, Kind: models.ScoreAlertDeclineStreak, From: 5, To: 2, Message: "Hustle fell at each of the last 3 events (5 → 2)"},
+			},
+		},
+		{
+			name:    "rolling average below the earlier average",
+			scores:  []int{5, 5, 5, 3, 4, 3},
+			rolling: []float64{5, 5, 5, 4.3, 4, 3.3},
+			streak:  models.ScoreStreak{Direction: "down", Length: 1},
+			alerts: []models.ScoreAlert{
+				{Metric: hustle, Kind: models.ScoreAlertAverageDrop, From: 5, To: 3.3, Message: "Hustle averaged 3.3 over the last 3 events, down from 5.0"},
+			},
+		},
+		{
+			name:    "average drop needs two windows of events",
+			scores:  []int{5, 5, 3, 3, 3},
+			rolling: []float64{5, 5, 4.3, 3.7, 3},
+			streak:  models.ScoreStreak{Direction: "flat", Length: 2},
+			alerts:  []models.ScoreAlert{},
+		},
+		{
+			name:    "sharp drop",
+			scores:  []int{5, 5, 5, 1},
+			rolling: []float64{5, 5, 5, 3.7},
+			streak:  models.ScoreStreak{Direction: "down", Length: 1},
+			alerts: []models.ScoreAlert{
+				{Metric: hustle, Kind: models.ScoreAlertSharpDrop, From: 5, To: 1, Message: "Hustle dropped to 1 from an average of 5.0"},
+			},
+		},
+		{
+			name:    "every alert",
+			scores:  []int{5, 5, 5, 5, 4, 3, 1},
+			rolling: []float64{5, 5, 5, 5, 4.7, 4, 2.7},
+			streak:  models.ScoreStreak{Direction: "down", Length: 3},
+			alerts: []models.ScoreAlert{
+				{Metric: hustle, Kind: models.ScoreAlertDeclineStreak, From: 5, To: 1, Message: "Hustle fell at each of the last 3 events (5 → 1)"},
+				{Metric: hustle, Kind: models.ScoreAlertAverageDrop, From: 5, To: 2.7, Message: "Hustle averaged 2.7 over the last 3 events, down from 5.0"},
+				{Metric: hustle, Kind: models.ScoreAlertSharpDrop, From: 4, To: 1, Message: "Hustle dropped to 1 from an average of 4.0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var points []models.ScorePoint
+			for i, score := range tt.scores {
+				points = append(points, models.ScorePoint{EventID: uint(i + 1), Score: score})
+			}
+			trend := analyzeScores(hustle, points, 3)
+
+			var rolling []float64
+			for _, point := range trend.Points {
+				rolling = append(rolling, point.RollingAverage)
+			}
+			if !reflect.DeepEqual(rolling, tt.rolling) {
+				t.Errorf("rolling averages = %v, want %v", rolling, tt.rolling)
+			}
+			if trend.Streak != tt.streak {
+				t.Errorf("Streak = %+v, want %+v", trend.Streak, tt.streak)
+			}
+			if !reflect.DeepEqual(trend.Alerts, tt.alerts) {
+				t.Errorf("Alerts = %+v, want %+v", trend.Alerts, tt.alerts)
+			}
+			if len(tt.scores) == 0 {
+				if trend.Latest != nil || trend.Average != nil || trend.RollingAverage != nil {
+					t.Errorf("summary of no scores = %v, %v, %v, want nil", trend.Latest, trend.Average, trend.RollingAverage)
+				}
+				return
+			}
+			if *trend.Latest != tt.scores[len(tt.scores)-1] || *trend.RollingAverage != tt.rolling[len(tt.rolling)-1] {
+				t.Errorf("Latest = %d, RollingAverage = %v", *trend.Latest, *trend.RollingAverage)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	scheduleChangeDueKey = "events:schedule-change:due"
+	// scheduleTimeLayout is how new_time is written into ScheduleChange
+	// notifications, in each recipient's time zone
+	scheduleTimeLayout = "Mon Jan 2, 3:04 PM MST"
+)
+
+// claimScheduleChange removes a due event from the queue and takes its
+// snapshot in one step, so an edit landing in between can neither re-arm an
+// entry whose snapshot is then deleted nor be claimed by a second worker.
+// Returns nil when the entry is gone or was re-armed past ARGV[2], and an
+// empty string when the snapshot expired.
+var claimScheduleChange = redis.NewScript(`
+local due = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not due or tonumber(due) > tonumber(ARGV[2]) then
+	return nil
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+local snapshot = redis.call('GET', KEYS[2])
+redis.call('DEL', KEYS[2])
+return snapshot or ''
+`)
+
+func scheduleChangeSnapshotKey(eventID uint) string {
+	return fmt.Sprintf("events:schedule-change:%d", eventID)
+}
+
+// eventSnapshot holds the fields that make up an event's schedule
+type eventSnapshot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Location  string    `json:"location"`
+	Status    uint8     `json:"status"`
+}
+
+func snapshotOf(event *models.Event) eventSnapshot {
+	return eventSnapshot{
+		StartTime: event.StartTime,
+		EndTime:   event.EndTime,
+		Location:  event.Location,
+		Status:    event.Status,
+	}
+}
+
+// ScheduleDiff describes how an event's schedule changed between two rows
+type ScheduleDiff struct {
+	TimeChanged     bool
+	LocationChanged bool
+	Cancelled       bool
+}
+
+func (d ScheduleDiff) Empty() bool {
+	return !d.TimeChanged && !d.LocationChanged && !d.Cancelled
+}
+
+// ChangeType is the {{change_type}} wording of the ScheduleChange template
+func (d ScheduleDiff) ChangeType() string {
+	switch {
+	case d.Cancelled:
+		return "cancelled"
+	case d.TimeChanged && d.LocationChanged:
+		return "rescheduled and moved"
+	case d.TimeChanged:
+		return "rescheduled"
+	default:
+		return "moved"
+	}
+}
+
+func diffSchedule(old, new eventSnapshot) ScheduleDiff {
+	return ScheduleDiff{
+		TimeChanged:     !old.StartTime.Equal(new.StartTime) || !old.EndTime.Equal(new.EndTime),
+		LocationChanged: strings.TrimSpace(old.Location) != strings.TrimSpace(new.Location),
+		Cancelled:       old.Status != models.EventStatusCancelled && new.Status == models.EventStatusCancelled,
+	}
+}
+
+// DiffEventSchedule compares the schedule fields of two versions of an event
+func DiffEventSchedule(old, new *models.Event) ScheduleDiff {
+	return diffSchedule(snapshotOf(old), snapshotOf(new))
+}
+
+func scheduleChangeWindow() time.Duration {
+	seconds, err := strconv.Atoi(config.AppConfig.ScheduleChangeCoalesceSeconds)
+	if err != nil || seconds < 0 {
+		seconds = 120
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// OnEventUpdated is the change-detection hook of the event update path. When
+// the schedule changed it records the pre-edit snapshot and (re)arms the
+// coalescing window, so a burst of edits produces a single notification.
+func OnEventUpdated(ctx context.Context, old, new *models.Event) {
+	if DiffEventSchedule(old, new).Empty() {
+		return
+	}
+
+	snapshot, err := json.Marshal(snapshotOf(old))
+	if err != nil {
+		return
+	}
+
+	// Cancellations are urgent, so they skip the coalescing window
+	due := time.Now().Add(scheduleChangeWindow())
+	if new.Status == models.EventStatusCancelled {
+		due = time.Now()
+	}
+
+	if database.RedisClient == nil {
+		go notifyScheduleChange(context.Background(), new.ID, snapshotOf(old))
+		return
+	}
+
+	// SETNX keeps the snapshot from the first edit of the burst
+	pipe := database.RedisClient.TxPipeline()
+	pipe.SetNX(ctx, scheduleChangeSnapshotKey(new.ID), snapshot, 24*time.Hour)
+	pipe.ZAdd(ctx, scheduleChangeDueKey, &redis.Z{Score: float64(due.Unix()), Member: strconv.FormatUint(uint64(new.ID), 10)})
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to queue schedule change for event %d, notifying now: %v", new.ID, err)
+		go notifyScheduleChange(context.Background(), new.ID, snapshotOf(old))
+	}
+}
+
+// FlushScheduleChanges sends the notifications whose coalescing window has closed
+func FlushScheduleChanges(ctx context.Context, now time.Time) error {
+	due, err := database.RedisClient.ZRangeByScore(ctx, scheduleChangeDueKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, member := range due {
+		eventID, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			database.RedisClient.ZRem(ctx, scheduleChangeDueKey, member)
+			continue
+		}
+
+		// Only the worker whose script removes the entry sends it
+		payload, err := claimScheduleChange.Run(ctx, database.RedisClient,
+			[]string{scheduleChangeDueKey, scheduleChangeSnapshotKey(uint(eventID))},
+			member, now.Unix()).Text()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to claim schedule change for event %d: %v", eventID, err)
+			continue
+		}
+
+		var snapshot eventSnapshot
+		if err := json.Unmarshal([]byte(payload), &snapshot); err != nil {
+			log.Printf("Schedule change for event %d has no snapshot, skipped", eventID)
+			continue
+		}
+		notifyScheduleChange(ctx, uint(eventID), snapshot)
+	}
+	return nil
+}
+
+// notifyScheduleChange diffs the snapshot against the event as it is now and
+// notifies the rostered players and their parents. Edits that cancel each
+// other out within the window send nothing.
+func notifyScheduleChange(ctx context.Context, eventID uint, before eventSnapshot) {
+	var event models.Event
+	if err := database.DB.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		log.Printf("Schedule change for event %d skipped: %v", eventID, err)
+		return
+	}
+
+	diff := diffSchedule(before, snapshotOf(&event))
+	if diff.Empty() {
+		return
+	}
+
+	audience, err := TeamAudienceIDs(ctx, event.TeamID)
+	if err != nil {
+		log.Printf("Failed to load audience for event %d: %v", eventID, err)
+		return
+	}
+	if len(audience) == 0 {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"event_id":    event.ID,
+		"team_id":     event.TeamID,
+		"change_type": diff.ChangeType(),
+	}
+	if !diff.Cancelled {
+		payload["start_time"] = event.StartTime.UTC().Format(time.RFC3339)
+	}
+	data, _ := json.Marshal(payload)
+
+	prefs, err := LoadNotificationPreferences(ctx, audience)
+	if err != nil {
+		log.Printf("Failed to load preferences for event %d: %v", eventID, err)
+		return
+	}
+	// One intent per time zone, so new_time reads as local time for everyone
+	zones := make(map[string][]uint)
+	for _, userID := range audience {
+		zone := prefs[userID].TimeZone
+		zones[zone] = append(zones[zone], userID)
+	}
+	for zone, userIDs := range zones {
+		newTime := "none, the event is cancelled"
+		if !diff.Cancelled {
+			newTime = scheduleTimeIn(event.StartTime, zone)
+		}
+		intent := &models.NotificationIntent{
+			UserIDs: userIDs,
+			Type:    models.NotificationTypeScheduleChange,
+			Variables: map[string]string{
+				"event_title": event.Title,
+				"change_type": diff.ChangeType(),
+				"new_time":    newTime,
+			},
+			Data: data,
+		}
+		if _, err := NotificationDispatcher.Dispatch(ctx, intent); err != nil {
+			log.Printf("Failed to send schedule change for event %d: %v", eventID, err)
+		}
+	}
+}
+
+// scheduleTimeIn formats an event time in the time zone, or in UTC when the
+// zone is unknown
+func scheduleTimeIn(t time.Time, zone string) string {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(scheduleTimeLayout)
+}
+
+// StartScheduleChangeWorker flushes closed coalescing windows in the background
+func StartScheduleChangeWorker() {
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			if err := FlushScheduleChanges(context.Background(), now); err != nil {
+				log.Printf("Schedule change worker failed: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleTimeIn(t *testing.T) {
+	start := time.Date(2026, 3, 14, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		zone string
+		want string
+	}{
+		{"UTC", "Sat Mar 14, 11:30 PM UTC"},
+		{"America/Chicago", "Sat Mar 14, 6:30 PM CDT"},
+		{"Asia/Tokyo", "Sun Mar 15, 8:30 AM JST"},
+		{"Not/AZone", "Sat Mar 14, 11:30 PM UTC"},
+	}
+	for _, tt := range tests {
+		if got := scheduleTimeIn(start, tt.zone); got != tt.want {
+			t.Errorf("scheduleTimeIn(%s) = %q, want %q", tt.zone, got, tt.want)
+		}
+	}
+}
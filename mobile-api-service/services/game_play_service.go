@@ -0,0 +1,531 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPlayByPlayBasketballOnly rejects play-by-play for sports whose plays are not modelled
+var ErrPlayByPlayBasketballOnly = errors.New("play-by-play tracking is only available for basketball")
+
+// playProblem explains why a pushed play cannot be recorded, or returns ""
+func playProblem(in *models.GamePlayInput, roster map[uint]bool) string {
+	if in.PlayType == models.PlayOpponentScore {
+		if in.PlayerID != nil {
+			return "opponent scores have no player"
+		}
+		if in.Points == 0 {
+			return "opponent scores need points (1-3)"
+		}
+		return ""
+	}
+	if in.PlayerID == nil {
+		return "player_id is required"
+	}
+	if !roster[*in.PlayerID] {
+		return "player is not on the team roster"
+	}
+	if in.Points != 0 {
+		return "points are only set for opponent scores"
+	}
+	return ""
+}
+
+func sameUintPtr(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func applyPlayInput(play *models.GamePlay, in *models.GamePlayInput) {
+	play.PlayType = in.PlayType
+	play.PlayerID = in.PlayerID
+	play.Period = in.Period
+	play.ClockSeconds = *in.ClockSeconds
+	play.Points = in.Points
+	play.Voided = in.Voided
+	play.ClientUpdatedAt = in.UpdatedAt
+}
+
+// resolvePlayEdit decides whether a pushed edit of a known play wins. A void
+// always wins and a voided play stays voided. Otherwise an edit made on the
+// latest version applies, and an edit made on an older version only applies
+// when the device changed the play later than whoever changed it last.
+func resolvePlayEdit(play *models.GamePlay, in *models.GamePlayInput) (string, string) {
+	if play.PlayType == in.PlayType && sameUintPtr(play.PlayerID, in.PlayerID) && play.Period == in.Period &&
+		play.ClockSeconds == *in.ClockSeconds && play.Points == in.Points && play.Voided == in.Voided {
+		return models.PlaySyncUnchanged, ""
+	}
+	if play.Voided {
+		return models.PlaySyncConflict, "play was voided on another device"
+	}
+	if in.Voided || in.BaseVersion == play.Version || in.UpdatedAt.After(play.ClientUpdatedAt) {
+		return models.PlaySyncUpdated, ""
+	}
+	return models.PlaySyncConflict, "play was changed on another device after this edit"
+}
+
+// duplicatePlayClockTolerance is how far apart the clocks of two devices may
+// read for one action, since each tracker taps a moment after the play
+const duplicatePlayClockTolerance = 3
+
+// findDuplicatePlay looks for the same action recorded by another device that
+// this device has not already matched, so two devices tracking one game do
+// not count it twice. The play closest on the clock is taken.
+func findDuplicatePlay(tx *gorm.DB, play *models.GamePlay) (uint, error) {
+	matched := tx.Model(&models.GamePlay{}).Select("duplicate_of").
+		Where("event_id = ? AND device_id = ? AND duplicate_of IS NOT NULL", play.EventID, play.DeviceID)
+	query := tx.Model(&models.GamePlay{}).
+		Where("event_id = ? AND device_id <> ? AND voided = ? AND duplicate_of IS NULL", play.EventID, play.DeviceID, false).
+		Where("play_type = ? AND period = ? AND points = ?", play.PlayType, play.Period, play.Points).
+		Where("clock_seconds BETWEEN ? AND ?", play.ClockSeconds-duplicatePlayClockTolerance, play.ClockSeconds+duplicatePlayClockTolerance).
+		Where("id NOT IN (?)", matched)
+	if play.PlayerID == nil {
+		query = query.Where("player_id IS NULL")
+	} else {
+		query = query.Where("player_id = ?", *play.PlayerID)
+	}
+
+	var ids []uint
+	err := query.Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "ABS(clock_seconds - ?), id", Vars: []interface{}{play.ClockSeconds}}}).
+		Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// playRoster returns the players plays may be recorded for: the active
+// roster plus anyone already in the game's log
+func playRoster(ctx context.Context, event *models.Event) (map[uint]bool, error) {
+	roster, err := TeamPlayerIDs(ctx, event.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	var logged []uint
+	if err := database.DB.WithContext(ctx).Model(&models.GamePlay{}).
+		Where("event_id = ? AND player_id IS NOT NULL", event.ID).
+		Distinct().Pluck("player_id", &logged).Error; err != nil {
+		return nil, err
+	}
+	allowed := make(map[uint]bool, len(roster)+len(logged))
+	for _, id := range append(roster, logged...) {
+		allowed[id] = true
+	}
+	return allowed, nil
+}
+
+// SyncGamePlays applies the plays a device queued and returns every play
+// changed since the device's last pull. Pushing the same plays again is a
+// no-op. When anything changed the game's stat lines are derived again from
+// the log in the same transaction.
+func SyncGamePlays(ctx context.Context, event *models.Event, userID uint, req *models.SyncGamePlaysRequest) (*models.GamePlaySyncResponse, error) {
+	definition, err := TeamStatDefinition(ctx, event.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if !usesColumns(definition) {
+		return nil, ErrPlayByPlayBasketballOnly
+	}
+	roster, err := playRoster(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.GamePlaySyncResponse{Results: make([]models.GamePlaySyncResult, 0, len(req.Plays))}
+	var derivedFor []uint
+	err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the event serializes syncs of one game so revisions stay ordered
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Event{}, event.ID).Error; err != nil {
+			return ErrEventNotFound
+		}
+		var revision uint64
+		if err := tx.Model(&models.GamePlay{}).Where("event_id = ?", event.ID).
+			Select("COALESCE(MAX(revision), 0)").Scan(&revision).Error; err != nil {
+			return err
+		}
+
+		changed := false
+		for i := range req.Plays {
+			in := &req.Plays[i]
+			result := models.GamePlaySyncResult{ClientID: in.ClientID}
+			if problem := playProblem(in, roster); problem != "" {
+				result.Status = models.PlaySyncRejected
+				result.Reason = problem
+				resp.Results = append(resp.Results, result)
+				continue
+			}
+
+			var play models.GamePlay
+			err := tx.Where("event_id = ? AND client_id = ?", event.ID, in.ClientID).Take(&play).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				play = models.GamePlay{
+					EventID:    event.ID,
+					ClientID:   in.ClientID,
+					DeviceID:   req.DeviceID,
+					Version:    1,
+					RecordedBy: userID,
+				}
+				applyPlayInput(&play, in)
+				result.Status = models.PlaySyncCreated
+				if !play.Voided {
+					duplicateOf, err := findDuplicatePlay(tx, &play)
+					if err != nil {
+						return err
+					}
+					if duplicateOf != 0 {
+						play.DuplicateOf = &duplicateOf
+						result.Status = models.PlaySyncDuplicate
+					}
+				}
+				revision++
+				play.Revision = revision
+				if err := tx.Create(&play).Error; err != nil {
+					return err
+				}
+				changed = true
+
+			case err != nil:
+				return err
+
+			default:
+				result.Status, result.Reason = resolvePlayEdit(&play, in)
+				if result.Status != models.PlaySyncUpdated {
+					break
+				}
+				applyPlayInput(&play, in)
+				play.Version++
+				revision++
+				play.Revision = revision
+				if err := tx.Save(&play).Error; err != nil {
+					return err
+				}
+				if play.Voided {
+					// Copies other devices recorded of a voided play go with it
+					if err := tx.Model(&models.GamePlay{}).
+						Where("event_id = ? AND duplicate_of = ? AND voided = ?", event.ID, play.ID, false).
+						Updates(map[string]interface{}{
+							"voided":   true,
+							"version":  gorm.Expr("version + 1"),
+							"revision": revision,
+						}).Error; err != nil {
+						return err
+					}
+				}
+				changed = true
+			}
+
+			result.Play = &play
+			resp.Results = append(resp.Results, result)
+		}
+
+		if changed {
+			if derivedFor, err = deriveGameStats(tx, event, userID); err != nil {
+				return err
+			}
+		}
+
+		resp.Revision = revision
+		return tx.Where("event_id = ? AND revision > ?", event.ID, req.Since).
+			Order("revision, id").Find(&resp.Changes).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(derivedFor) > 0 {
+		InvalidateStatsCache(ctx, event.TeamID, derivedFor)
+	}
+
+	jerseys := teamJerseys(ctx, event.TeamID)
+	describePlays(resp.Changes, jerseys)
+	for _, result := range resp.Results {
+		if result.Play != nil {
+			result.Play.Description = describePlay(result.Play, jerseys)
+		}
+	}
+	return resp, nil
+}
+
+// ListGamePlays returns the plays changed after the since revision in
+// revision order, and the game's latest revision
+func ListGamePlays(ctx context.Context, event *models.Event, since uint64) ([]models.GamePlay, uint64, error) {
+	plays := []models.GamePlay{}
+	if err := database.DB.WithContext(ctx).Where("event_id = ? AND revision > ?", event.ID, since).
+		Order("revision, id").Find(&plays).Error; err != nil {
+		return nil, 0, err
+	}
+	var revision uint64
+	if err := database.DB.WithContext(ctx).Model(&models.GamePlay{}).Where("event_id = ?", event.ID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&revision).Error; err != nil {
+		return nil, 0, err
+	}
+	describePlays(plays, teamJerseys(ctx, event.TeamID))
+	return plays, revision, nil
+}
+
+// countedPlays loads the plays that make up the game record in game order
+func countedPlays(tx *gorm.DB, eventID uint) ([]models.GamePlay, error) {
+	var plays []models.GamePlay
+	err := tx.Where("event_id = ? AND voided = ? AND duplicate_of IS NULL", eventID, false).
+		Order("period, clock_seconds DESC, id").Find(&plays).Error
+	return plays, err
+}
+
+// deriveGameStats rewrites the stat lines of a game from its log and
+// refreshes the season totals, returning the players of the game. Players
+// whose plays were all voided are zeroed; notes and additional_stats are kept.
+func deriveGameStats(tx *gorm.DB, event *models.Event, editedBy uint) ([]uint, error) {
+	plays, err := countedPlays(tx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	var existing []models.GameStat
+	if err := tx.Where("event_id = ?", event.ID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	derived := DeriveStatLines(plays, startersOf(existing))
+
+	for i := range existing {
+		kept := statLineOf(&existing[i])
+		line, ok := derived[kept.PlayerID]
+		if !ok {
+			line = &models.GameStatLine{PlayerID: kept.PlayerID}
+			derived[kept.PlayerID] = line
+		}
+		line.AdditionalStats = kept.AdditionalStats
+		line.Notes = kept.Notes
+	}
+
+	lines := make([]models.GameStatLine, 0, len(derived))
+	for _, line := range derived {
+		lines = append(lines, *line)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].PlayerID < lines[j].PlayerID })
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	if _, err := writeStatLines(tx, event.ID, editedBy, lines, true); err != nil {
+		return nil, err
+	}
+	playerIDs := playerIDsOf(lines)
+	return playerIDs, refreshSeasonStats(tx, event.TeamID, playerIDs)
+}
+
+// startersOf returns the players whose stored lines are marked as started
+func startersOf(stats []models.GameStat) []uint {
+	var starters []uint
+	for i := range stats {
+		var additional struct {
+			Started bool `json:"started"`
+		}
+		if json.Unmarshal(stats[i].AdditionalStats, &additional) == nil && additional.Started {
+			starters = append(starters, stats[i].PlayerID)
+		}
+	}
+	return starters
+}
+
+// playPoints is the score change of a play, negative for the opponent
+func playPoints(play *models.GamePlay) int {
+	switch play.PlayType {
+	case models.PlayTwoPointMade:
+		return 2
+	case models.PlayThreeMade:
+		return 3
+	case models.PlayFreeThrowMade:
+		return 1
+	case models.PlayOpponentScore:
+		return -int(play.Points)
+	}
+	return 0
+}
+
+// DeriveStatLines totals counted plays, given in game order, into stat lines
+// per player. Minutes and plus/minus are only set for starters and players
+// with substitutions. Starters are on court from the start of the first
+// period; a stint runs from then or a sub in to the next sub out, carrying
+// over into later periods, and every score during it counts toward the
+// player's plus/minus. Periods are taken to be as long as the highest clock
+// recorded in the game.
+func DeriveStatLines(plays []models.GamePlay, starters []uint) map[uint]*models.GameStatLine {
+	lines := make(map[uint]*models.GameStatLine)
+	if len(plays) == 0 {
+		return lines
+	}
+	seconds := make(map[uint]int)
+	plusMinus := make(map[uint]int)
+	onCourt := make(map[uint]int)
+	period := plays[0].Period
+
+	periodLength := 0
+	for i := range plays {
+		if plays[i].ClockSeconds > periodLength {
+			periodLength = plays[i].ClockSeconds
+		}
+	}
+	if period == 1 {
+		for _, playerID := range starters {
+			onCourt[playerID] = periodLength
+			seconds[playerID] = 0
+			if _, ok := lines[playerID]; !ok {
+				lines[playerID] = &models.GameStatLine{PlayerID: playerID}
+			}
+		}
+	}
+
+	// closePeriod ends the stints at the buzzer; the lineup starts the next
+	// period on court
+	closePeriod := func() {
+		for playerID, since := range onCourt {
+			seconds[playerID] += since
+			onCourt[playerID] = periodLength
+		}
+	}
+
+	for i := range plays {
+		play := &plays[i]
+		if play.Period != period {
+			closePeriod()
+			period = play.Period
+		}
+		if points := playPoints(play); points != 0 {
+			for playerID := range onCourt {
+				plusMinus[playerID] += points
+			}
+		}
+		if play.PlayerID == nil {
+			continue
+		}
+		playerID := *play.PlayerID
+		line, ok := lines[playerID]
+		if !ok {
+			line = &models.GameStatLine{PlayerID: playerID}
+			lines[playerID] = line
+		}
+
+		c := &line.GameStatCounts
+		switch play.PlayType {
+		case models.PlayTwoPointMade:
+			c.FieldGoalsMade++
+			c.FieldGoalsAttempted++
+			c.Points += 2
+		case models.PlayTwoPointMissed:
+			c.FieldGoalsAttempted++
+		case models.PlayThreeMade:
+			c.FieldGoalsMade++
+			c.FieldGoalsAttempted++
+			c.ThreePointersMade++
+			c.ThreePointersAttempted++
+			c.Points += 3
+		case models.PlayThreeMissed:
+			c.FieldGoalsAttempted++
+			c.ThreePointersAttempted++
+		case models.PlayFreeThrowMade:
+			c.FreeThrowsMade++
+			c.FreeThrowsAttempted++
+			c.Points++
+		case models.PlayFreeThrowMissed:
+			c.FreeThrowsAttempted++
+		case models.PlayRebound:
+			c.Rebounds++
+		case models.PlayAssist:
+			c.Assists++
+		case models.PlaySteal:
+			c.Steals++
+		case models.PlayBlock:
+			c.Blocks++
+		case models.PlayTurnover:
+			c.Turnovers++
+		case models.PlayFoul:
+			c.Fouls++
+		case models.PlaySubIn:
+			if _, in := onCourt[playerID]; !in {
+				onCourt[playerID] = play.ClockSeconds
+			}
+			if _, tracked := seconds[playerID]; !tracked {
+				seconds[playerID] = 0
+			}
+		case models.PlaySubOut:
+			if since, in := onCourt[playerID]; in {
+				seconds[playerID] += since - play.ClockSeconds
+				delete(onCourt, playerID)
+			}
+		}
+	}
+	for playerID, since := range onCourt {
+		seconds[playerID] += since
+	}
+
+	for playerID, total := range seconds {
+		minutes := int(math.Round(float64(total) / 60))
+		pm := plusMinus[playerID]
+		lines[playerID].MinutesPlayed = &minutes
+		lines[playerID].PlusMinus = &pm
+	}
+	return lines
+}
+
+// teamJerseys maps the team's players to their jersey numbers
+func teamJerseys(ctx context.Context, teamID uint) map[uint]int {
+	var members []models.TeamMember
+	database.DB.WithContext(ctx).Select("user_id", "jersey_number").
+		Where("team_id = ? AND member_type = ? AND jersey_number IS NOT NULL", teamID, models.MemberTypePlayer).
+		Find(&members)
+	jerseys := make(map[uint]int, len(members))
+	for _, m := range members {
+		jerseys[m.UserID] = *m.JerseyNumber
+	}
+	return jerseys
+}
+
+var playLabels = map[uint8]string{
+	models.PlayTwoPointMade:    "made 2PT",
+	models.PlayTwoPointMissed:  "missed 2PT",
+	models.PlayThreeMade:       "made 3PT",
+	models.PlayThreeMissed:     "missed 3PT",
+	models.PlayFreeThrowMade:   "made FT",
+	models.PlayFreeThrowMissed: "missed FT",
+	models.PlayRebound:         "rebound",
+	models.PlayAssist:          "assist",
+	models.PlaySteal:           "steal",
+	models.PlayBlock:           "block",
+	models.PlayTurnover:        "turnover",
+	models.PlayFoul:            "foul",
+	models.PlaySubIn:           "sub in",
+	models.PlaySubOut:          "sub out",
+}
+
+// describePlay renders a play like "made 3PT by #12 at 4:31 Q2"
+func describePlay(play *models.GamePlay, jerseys map[uint]int) string {
+	at := fmt.Sprintf("at %d:%02d Q%d", play.ClockSeconds/60, play.ClockSeconds%60, play.Period)
+	if play.PlayType == models.PlayOpponentScore {
+		return fmt.Sprintf("opponent scored %d %s", play.Points, at)
+	}
+	by := "unknown player"
+	if play.PlayerID != nil {
+		if jersey, ok := jerseys[*play.PlayerID]; ok {
+			by = fmt.Sprintf("#%d", jersey)
+		} else {
+			by = fmt.Sprintf("player %d", *play.PlayerID)
+		}
+	}
+	return fmt.Sprintf("%s by %s %s", playLabels[play.PlayType], by, at)
+}
+
+func describePlays(plays []models.GamePlay, jerseys map[uint]int) {
+	for i := range plays {
+		plays[i].Description = describePlay(&plays[i], jerseys)
+	}
+}
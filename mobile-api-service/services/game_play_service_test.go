@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+// testPlay is a counted play; player 0 stands for an opponent score
+func testPlay(period uint8, clock int, playType uint8, playerID uint) models.GamePlay {
+	play := models.GamePlay{Period: period, ClockSeconds: clock, PlayType: playType}
+	if playerID != 0 {
+		play.PlayerID = &playerID
+	}
+	return play
+}
+
+func opponentScore(period uint8, clock int, points uint8) models.GamePlay {
+	play := testPlay(period, clock, models.PlayOpponentScore, 0)
+	play.Points = points
+	return play
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestDeriveStatLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		plays    []models.GamePlay
+		starters []uint
+		want     map[uint]models.GameStatLine
+	}{
+		{
+			name: "counts without substitutions",
+			plays: []models.GamePlay{
+				testPlay(1, 590, models.PlayTwoPointMade, 1),
+				testPlay(1, 560, models.PlayThreeMissed, 1),
+				testPlay(1, 555, models.PlayRebound, 2),
+				testPlay(1, 540, models.PlayThreeMade, 1),
+				testPlay(1, 540, models.PlayAssist, 2),
+				testPlay(1, 500, models.PlayFreeThrowMade, 2),
+				testPlay(1, 500, models.PlayFreeThrowMissed, 2),
+				testPlay(1, 450, models.PlaySteal, 2),
+				testPlay(1, 440, models.PlayBlock, 1),
+				testPlay(1, 430, models.PlayTurnover, 1),
+				testPlay(1, 420, models.PlayFoul, 1),
+				opponentScore(1, 400, 3),
+			},
+			want: map[uint]models.GameStatLine{
+				1: {PlayerID: 1, GameStatCounts: models.GameStatCounts{
+					Points: 5, Blocks: 1, Turnovers: 1, Fouls: 1,
+					FieldGoalsMade: 2, FieldGoalsAttempted: 3, ThreePointersMade: 1, ThreePointersAttempted: 2,
+				}},
+				2: {PlayerID: 2, GameStatCounts: models.GameStatCounts{
+					Points: 1, Rebounds: 1, Assists: 1, Steals: 1, FreeThrowsMade: 1, FreeThrowsAttempted: 2,
+				}},
+			},
+		},
+		{
+			name: "substitutions give minutes and plus/minus",
+			plays: []models.GamePlay{
+				testPlay(1, 600, models.PlaySubIn, 1),
+				testPlay(1, 600, models.PlaySubIn, 2),
+				testPlay(1, 550, models.PlayTwoPointMade, 1),
+				opponentScore(1, 500, 3),
+				testPlay(1, 420, models.PlaySubOut, 2),
+				testPlay(1, 400, models.PlayThreeMade, 1),
+				// A player without substitutions scores for the players on court
+				testPlay(1, 390, models.PlayFreeThrowMade, 3),
+				testPlay(1, 0, models.PlaySubOut, 1),
+				opponentScore(1, 0, 2),
+			},
+			want: map[uint]models.GameStatLine{
+				1: {PlayerID: 1, MinutesPlayed: intPtr(10), PlusMinus: intPtr(3), GameStatCounts: models.GameStatCounts{
+					Points: 5, FieldGoalsMade: 2, FieldGoalsAttempted: 2, ThreePointersMade: 1, ThreePointersAttempted: 1,
+				}},
+				2: {PlayerID: 2, MinutesPlayed: intPtr(3), PlusMinus: intPtr(-1)},
+				3: {PlayerID: 3, GameStatCounts: models.GameStatCounts{Points: 1, FreeThrowsMade: 1, FreeThrowsAttempted: 1}},
+			},
+		},
+		{
+			name: "stints carry over into the next period",
+			plays: []models.GamePlay{
+				testPlay(1, 600, models.PlaySubIn, 2),
+				testPlay(1, 120, models.PlaySubIn, 1),
+				opponentScore(1, 60, 2),
+				testPlay(1, 0, models.PlaySubOut, 2),
+				opponentScore(2, 500, 3),
+				// Player 1 never left the court, so this sub in changes nothing
+				testPlay(2, 300, models.PlaySubIn, 1),
+				testPlay(2, 200, models.PlayRebound, 1),
+				testPlay(2, 100, models.PlayTwoPointMade, 1),
+			},
+			want: map[uint]models.GameStatLine{
+				// 120 seconds in the first period and all 600 of the second
+				1: {PlayerID: 1, MinutesPlayed: intPtr(12), PlusMinus: intPtr(-3), GameStatCounts: models.GameStatCounts{
+					Points: 2, Rebounds: 1, FieldGoalsMade: 1, FieldGoalsAttempted: 1,
+				}},
+				2: {PlayerID: 2, MinutesPlayed: intPtr(10), PlusMinus: intPtr(-2)},
+			},
+		},
+		{
+			name: "starter plays the whole game",
+			plays: []models.GamePlay{
+				testPlay(1, 480, models.PlayTwoPointMade, 1),
+				opponentScore(1, 300, 3),
+				opponentScore(2, 400, 2),
+				testPlay(3, 200, models.PlayThreeMade, 1),
+				testPlay(4, 10, models.PlayFreeThrowMade, 1),
+			},
+			starters: []uint{1},
+			want: map[uint]models.GameStatLine{
+				// Four periods as long as the highest clock, 480 seconds
+				1: {PlayerID: 1, MinutesPlayed: intPtr(32), PlusMinus: intPtr(1), GameStatCounts: models.GameStatCounts{
+					Points: 6, FieldGoalsMade: 2, FieldGoalsAttempted: 2, ThreePointersMade: 1, ThreePointersAttempted: 1,
+					FreeThrowsMade: 1, FreeThrowsAttempted: 1,
+				}},
+			},
+		},
+		{
+			name: "starter subbed out and back in",
+			plays: []models.GamePlay{
+				testPlay(1, 600, models.PlaySubIn, 3),
+				testPlay(1, 420, models.PlaySubOut, 1),
+				testPlay(1, 420, models.PlaySubIn, 2),
+				opponentScore(1, 300, 2),
+				testPlay(1, 120, models.PlaySubOut, 2),
+				testPlay(1, 120, models.PlaySubIn, 1),
+				testPlay(1, 60, models.PlayTwoPointMade, 3),
+			},
+			starters: []uint{1},
+			want: map[uint]models.GameStatLine{
+				// 180 seconds before the sub out and 120 after the sub in
+				1: {PlayerID: 1, MinutesPlayed: intPtr(5), PlusMinus: intPtr(2)},
+				2: {PlayerID: 2, MinutesPlayed: intPtr(5), PlusMinus: intPtr(-2)},
+				3: {PlayerID: 3, MinutesPlayed: intPtr(10), PlusMinus: intPtr(0), GameStatCounts: models.GameStatCounts{
+					Points: 2, FieldGoalsMade: 1, FieldGoalsAttempted: 1,
+				}},
+			},
+		},
+		{
+			name: "repeated sub in keeps the first stint start",
+			plays: []models.GamePlay{
+				testPlay(1, 600, models.PlaySubIn, 1),
+				testPlay(1, 550, models.PlaySubIn, 1),
+				testPlay(1, 510, models.PlaySubOut, 1),
+			},
+			want: map[uint]models.GameStatLine{
+				// 90 seconds round to 2 minutes
+				1: {PlayerID: 1, MinutesPlayed: intPtr(2), PlusMinus: intPtr(0)},
+			},
+		},
+		{
+			name: "sub out without sub in",
+			plays: []models.GamePlay{
+				testPlay(1, 300, models.PlaySubOut, 1),
+				opponentScore(1, 200, 2),
+			},
+			want: map[uint]models.GameStatLine{
+				1: {PlayerID: 1},
+			},
+		},
+		{
+			name:     "no plays",
+			plays:    nil,
+			starters: []uint{1},
+			want:     map[uint]models.GameStatLine{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[uint]models.GameStatLine)
+			for playerID, line := range DeriveStatLines(tt.plays, tt.starters) {
+				got[playerID] = *line
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeriveStatLines() =\n%s\nwant\n%s", formatLines(got), formatLines(tt.want))
+			}
+		})
+	}
+}
+
+// formatLines prints the lines of players 1-3 with their pointer fields
+// dereferenced, in player order
+func formatLines(lines map[uint]models.GameStatLine) string {
+	var out string
+	for playerID := uint(1); playerID <= 3; playerID++ {
+		line, ok := lines[playerID]
+		if !ok {
+			continue
+		}
+		minutes, plusMinus := "nil", "nil"
+		if line.MinutesPlayed != nil {
+			minutes = strconv.Itoa(*line.MinutesPlayed)
+		}
+		if line.PlusMinus != nil {
+			plusMinus = strconv.Itoa(*line.PlusMinus)
+		}
+		out += fmt.Sprintf("  %d: %+v minutes=%s plus_minus=%s\n", playerID, line.GameStatCounts, minutes, plusMinus)
+	}
+	return out
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEventNotFound  = errors.New("event not found")
+	ErrEventNotGame   = errors.New("stats can only be entered for games")
+	ErrEventCancelled = errors.New("event is cancelled")
+	// ErrStatsFromPlayLog rejects manual totals for games tracked play by play
+	ErrStatsFromPlayLog = errors.New("stats of this game are derived from its play-by-play log")
+)
+
+// GameStatRowError lists the problems of one line of a bulk stat entry
+type GameStatRowError struct {
+	Index    int      `json:"index"`
+	PlayerID uint     `json:"player_id"`
+	Errors   []string `json:"errors"`
+}
+
+// GameStatValidationError is returned when any line of a bulk entry is
+// invalid; nothing is saved in that case
+type GameStatValidationError struct {
+	Rows []GameStatRowError
+}
+
+func (e *GameStatValidationError) Error() string {
+	return fmt.Sprintf("%d stat lines are invalid", len(e.Rows))
+}
+
+// ValidateGameStatCounts checks that a basketball line is internally
+// consistent. Field goals include three-pointers, so points must equal
+// 2·FGM + 3PM + FTM.
+func ValidateGameStatCounts(c models.GameStatCounts) []string {
+	var problems []string
+	if c.FieldGoalsMade > c.FieldGoalsAttempted {
+		problems = append(problems, "field_goals_made exceeds field_goals_attempted")
+	}
+	if c.ThreePointersMade > c.ThreePointersAttempted {
+		problems = append(problems, "three_pointers_made exceeds three_pointers_attempted")
+	}
+	if c.FreeThrowsMade > c.FreeThrowsAttempted {
+		problems = append(problems, "free_throws_made exceeds free_throws_attempted")
+	}
+	if c.ThreePointersMade > c.FieldGoalsMade {
+		problems = append(problems, "three_pointers_made exceeds field_goals_made")
+	}
+	if c.ThreePointersAttempted > c.FieldGoalsAttempted {
+		problems = append(problems, "three_pointers_attempted exceeds field_goals_attempted")
+	}
+	if expected := 2*c.FieldGoalsMade + c.ThreePointersMade + c.FreeThrowsMade; c.Points != expected {
+		problems = append(problems, fmt.Sprintf("points must be %d (2·FGM + 3PM + FTM)", expected))
+	}
+	return problems
+}
+
+// normalizeJSON re-encodes a JSON document so equal documents compare equal
+// byte for byte; empty and null documents become nil
+func normalizeJSON(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(trimmed, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// LoadGameEvent returns an event stats can be entered for
+func LoadGameEvent(ctx context.Context, eventID uint) (*models.Event, error) {
+	var event models.Event
+	if err := database.DB.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return nil, ErrEventNotFound
+	}
+	if event.Type != models.EventTypeGame {
+		return &event, ErrEventNotGame
+	}
+	if event.Status == models.EventStatusCancelled {
+		return &event, ErrEventCancelled
+	}
+	return &event, nil
+}
+
+// validateGameStatLines checks every line and normalizes its additional_stats.
+// Players must be on the active roster or already have a line for the game.
+// Sports without a stat definition keep the basketball column checks and
+// store additional_stats as given.
+func validateGameStatLines(ctx context.Context, event *models.Event, lines []models.GameStatLine) error {
+	definition, err := TeamStatDefinition(ctx, event.TeamID)
+	if err != nil && !errors.Is(err, ErrSportNotSupported) {
+		return err
+	}
+	roster, err := TeamPlayerIDs(ctx, event.TeamID)
+	if err != nil {
+		return err
+	}
+	var recorded []uint
+	if err := database.DB.WithContext(ctx).Model(&models.GameStat{}).
+		Where("event_id = ?", event.ID).Pluck("player_id", &recorded).Error; err != nil {
+		return err
+	}
+	allowed := make(map[uint]bool, len(roster)+len(recorded))
+	for _, id := range append(roster, recorded...) {
+		allowed[id] = true
+	}
+
+	var rows []GameStatRowError
+	seen := make(map[uint]bool, len(lines))
+	for i := range lines {
+		line := &lines[i]
+		var problems []string
+		if definition == nil || usesColumns(definition) {
+			problems = ValidateGameStatCounts(line.GameStatCounts)
+		}
+		if !allowed[line.PlayerID] {
+			problems = append(problems, "player is not on the team roster")
+		}
+		if line.PlusMinus != nil {
+			problems = append(problems, "plus_minus is only derived from play-by-play")
+		}
+		if seen[line.PlayerID] {
+			problems = append(problems, "player appears more than once")
+		}
+		seen[line.PlayerID] = true
+
+		normalized, err := normalizeJSON(line.AdditionalStats)
+		if err != nil || (normalized != nil && normalized[0] != '{') {
+			problems = append(problems, "additional_stats must be a JSON object")
+		} else {
+			line.AdditionalStats = normalized
+			if definition != nil {
+				problems = append(problems, ValidateSportStats(definition, line.GameStatCounts, normalized)...)
+			}
+		}
+
+		if len(problems) > 0 {
+			rows = append(rows, GameStatRowError{Index: i, PlayerID: line.PlayerID, Errors: problems})
+		}
+	}
+	if len(rows) > 0 {
+		return &GameStatValidationError{Rows: rows}
+	}
+	return nil
+}
+
+// statLineOf is the history snapshot of a stored stat line
+func statLineOf(stat *models.GameStat) models.GameStatLine {
+	additional, _ := normalizeJSON(stat.AdditionalStats)
+	return models.GameStatLine{
+		PlayerID:        stat.PlayerID,
+		GameStatCounts:  stat.GameStatCounts,
+		MinutesPlayed:   stat.MinutesPlayed,
+		PlusMinus:       stat.PlusMinus,
+		AdditionalStats: additional,
+		Notes:           stat.Notes,
+	}
+}
+
+// SaveGameStats upserts the stat lines of a game in one transaction. Lines
+// that do not change anything are skipped; every create and update is
+// recorded in game_stat_edits, and the players' season totals are refreshed.
+// Each line replaces the player's stored line, so callers holding only some
+// fields merge them onto the stored line first, as stat imports do.
+// It returns the number of lines written.
+func SaveGameStats(ctx context.Context, event *models.Event, enteredBy uint, lines []models.GameStatLine) (int, error) {
+	if err := validateGameStatLines(ctx, event, lines); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var plays int64
+		if err := tx.Model(&models.GamePlay{}).Where("event_id = ?", event.ID).Count(&plays).Error; err != nil {
+			return err
+		}
+		if plays > 0 {
+			return ErrStatsFromPlayLog
+		}
+
+		var err error
+		if written, err = writeStatLines(tx, event.ID, enteredBy, lines, false); err != nil || written == 0 {
+			return err
+		}
+		return refreshSeasonStats(tx, event.TeamID, playerIDsOf(lines))
+	})
+	if err == nil && written > 0 {
+		InvalidateStatsCache(ctx, event.TeamID, playerIDsOf(lines))
+	}
+	return written, err
+}
+
+func playerIDsOf(lines []models.GameStatLine) []uint {
+	ids := make([]uint, len(lines))
+	for i, line := range lines {
+		ids[i] = line.PlayerID
+	}
+	return ids
+}
+
+// writeStatLines upserts stat lines inside tx and records each change.
+// Derived lines come from the play-by-play log and are recorded as such.
+func writeStatLines(tx *gorm.DB, eventID, editedBy uint, lines []models.GameStatLine, derived bool) (int, error) {
+	if len(lines) == 0 {
+		return 0, nil
+	}
+	var existing []models.GameStat
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ? AND player_id IN ?", eventID, playerIDsOf(lines)).
+		Find(&existing).Error; err != nil {
+		return 0, err
+	}
+	byPlayer := make(map[uint]*models.GameStat, len(existing))
+	for i := range existing {
+		byPlayer[existing[i].PlayerID] = &existing[i]
+	}
+
+	written := 0
+	for _, line := range lines {
+		after, _ := json.Marshal(line)
+		edit := models.GameStatEdit{
+			EventID:  eventID,
+			PlayerID: line.PlayerID,
+			After:    after,
+			EditedBy: editedBy,
+		}
+
+		stat, found := byPlayer[line.PlayerID]
+		if found {
+			before, _ := json.Marshal(statLineOf(stat))
+			if bytes.Equal(before, after) {
+				continue
+			}
+			edit.Action = models.GameStatEditUpdated
+			edit.Before = before
+		} else {
+			stat = &models.GameStat{EventID: eventID, PlayerID: line.PlayerID}
+			edit.Action = models.GameStatEditCreated
+		}
+		if derived {
+			edit.Action = models.GameStatEditDerived
+		}
+
+		stat.GameStatCounts = line.GameStatCounts
+		stat.MinutesPlayed = line.MinutesPlayed
+		stat.PlusMinus = line.PlusMinus
+		stat.AdditionalStats = line.AdditionalStats
+		stat.Notes = line.Notes
+		stat.EnteredBy = editedBy
+		if err := tx.Save(stat).Error; err != nil {
+			return written, err
+		}
+
+		edit.GameStatID = stat.ID
+		if err := tx.Create(&edit).Error; err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+func percentage(made, attempted int) *float64 {
+	if attempted == 0 {
+		return nil
+	}
+	pct := math.Round(float64(made)/float64(attempted)*1000) / 10
+	return &pct
+}
+
+func shootingSplits(c models.GameStatCounts) models.ShootingSplits {
+	return models.ShootingSplits{
+		FieldGoalPct:  percentage(c.FieldGoalsMade, c.FieldGoalsAttempted),
+		ThreePointPct: percentage(c.ThreePointersMade, c.ThreePointersAttempted),
+		FreeThrowPct:  percentage(c.FreeThrowsMade, c.FreeThrowsAttempted),
+	}
+}
+
+// BuildBoxScore returns the stat lines of a game ordered by jersey number,
+// with shooting percentages and team totals
+func BuildBoxScore(ctx context.Context, event *models.Event) (*models.BoxScore, error) {
+	lines := []models.BoxScoreLine{}
+	err := database.DB.WithContext(ctx).Table("game_stats").
+		Select("game_stats.*, users.name AS player_name, team_members.jersey_number").
+		Joins("LEFT JOIN users ON users.id = game_stats.player_id").
+		Joins("LEFT JOIN team_members ON team_members.user_id = game_stats.player_id AND team_members.team_id = ?", event.TeamID).
+		Where("game_stats.event_id = ?", event.ID).
+		Order("team_members.jersey_number IS NULL, team_members.jersey_number, users.name").
+		Scan(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+
+	box := &models.BoxScore{EventID: event.ID, TeamID: event.TeamID, Players: lines}
+	for i := range lines {
+		lines[i].ShootingSplits = shootingSplits(lines[i].GameStatCounts)
+		box.Totals.Add(lines[i].GameStatCounts)
+		if lines[i].MinutesPlayed != nil {
+			box.Totals.MinutesPlayed += *lines[i].MinutesPlayed
+		}
+	}
+	box.Totals.ShootingSplits = shootingSplits(box.Totals.GameStatCounts)
+	return box, nil
+}
+
+// ListGameStatEdits returns the edit history of a game, newest first
+func ListGameStatEdits(ctx context.Context, eventID uint, page, limit int) ([]models.GameStatEdit, int64, error) {
+	scope := func() *gorm.DB {
+		return database.DB.WithContext(ctx).Model(&models.GameStatEdit{}).Where("event_id = ?", eventID)
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var edits []models.GameStatEdit
+	err := scope().Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&edits).Error
+	return edits, total, err
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+func TestValidateGameStatCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts models.GameStatCounts
+		want   []string
+	}{
+		{
+			name: "empty line",
+		},
+		{
+			name: "consistent line",
+			counts: models.GameStatCounts{
+				Points: 21, Rebounds: 7, Assists: 4,
+				FieldGoalsMade: 8, FieldGoalsAttempted: 15,
+				ThreePointersMade: 2, ThreePointersAttempted: 5,
+				FreeThrowsMade: 3, FreeThrowsAttempted: 4,
+			},
+		},
+		{
+			name:   "free throws only",
+			counts: models.GameStatCounts{Points: 2, FreeThrowsMade: 2, FreeThrowsAttempted: 2},
+		},
+		{
+			name:   "three-pointers count as field goals",
+			counts: models.GameStatCounts{Points: 9, FieldGoalsMade: 3, FieldGoalsAttempted: 3, ThreePointersMade: 3, ThreePointersAttempted: 3},
+		},
+		{
+			name:   "more field goals made than attempted",
+			counts: models.GameStatCounts{Points: 8, FieldGoalsMade: 4, FieldGoalsAttempted: 3},
+			want:   []string{"field_goals_made exceeds field_goals_attempted"},
+		},
+		{
+			name:   "more three-pointers made than attempted",
+			counts: models.GameStatCounts{Points: 6, FieldGoalsMade: 2, FieldGoalsAttempted: 4, ThreePointersMade: 2, ThreePointersAttempted: 1},
+			want:   []string{"three_pointers_made exceeds three_pointers_attempted"},
+		},
+		{
+			name:   "more free throws made than attempted",
+			counts: models.GameStatCounts{Points: 3, FreeThrowsMade: 3, FreeThrowsAttempted: 2},
+			want:   []string{"free_throws_made exceeds free_throws_attempted"},
+		},
+		{
+			name:   "three-pointers outside the field goals",
+			counts: models.GameStatCounts{Points: 5, FieldGoalsMade: 1, FieldGoalsAttempted: 4, ThreePointersMade: 2, ThreePointersAttempted: 5},
+			want: []string{
+				"three_pointers_made exceeds field_goals_made",
+				"three_pointers_attempted exceeds field_goals_attempted",
+				"points must be 4 (2·FGM + 3PM + FTM)",
+			},
+		},
+		{
+			name:   "points scored without a basket",
+			counts: models.GameStatCounts{Points: 4},
+			want:   []string{"points must be 0 (2·FGM + 3PM + FTM)"},
+		},
+		{
+			name:   "three-pointers counted as twos",
+			counts: models.GameStatCounts{Points: 4, FieldGoalsMade: 2, FieldGoalsAttempted: 2, ThreePointersMade: 2, ThreePointersAttempted: 2},
+			want:   []string{"points must be 6 (2·FGM + 3PM + FTM)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateGameStatCounts(tt.counts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateGameStatCounts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
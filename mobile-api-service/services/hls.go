@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/storage"
+
+	"gorm.io/gorm"
+)
+
+// hlsSegmentSeconds is the target segment length; keyframes are forced on
+// this grid so every rendition splits at the same timestamps
+const hlsSegmentSeconds = 6
+
+var ErrNoRenditions = errors.New("video has no HLS renditions")
+
+type hlsLadderStep struct {
+	Name      string
+	Height    int
+	VideoKbps int
+	AudioKbps int
+}
+
+// hlsLadder is tuned for phones on cellular data; steps taller than the
+// source are skipped
+var hlsLadder = []hlsLadderStep{
+	{Name: "240p", Height: 240, VideoKbps: 400, AudioKbps: 64},
+	{Name: "360p", Height: 360, VideoKbps: 800, AudioKbps: 96},
+	{Name: "480p", Height: 480, VideoKbps: 1400, AudioKbps: 128},
+	{Name: "720p", Height: 720, VideoKbps: 2800, AudioKbps: 128},
+}
+
+func ladderFor(sourceHeight int) []hlsLadderStep {
+	var steps []hlsLadderStep
+	for _, step := range hlsLadder {
+		if step.Height <= sourceHeight {
+			steps = append(steps, step)
+		}
+	}
+	if len(steps) == 0 {
+		steps = hlsLadder[:1]
+	}
+	return steps
+}
+
+// ParseMediaPlaylist reads the segments and target duration of a VOD variant playlist
+func ParseMediaPlaylist(r io.Reader) ([]models.HLSSegment, int, error) {
+	var segments []models.HLSSegment
+	targetDuration := 0
+	pending := -1.0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-TARGETDURATION:"):
+			targetDuration, _ = strconv.Atoi(strings.TrimPrefix(line, "#EXT-X-TARGETDURATION:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			value := strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)[0]
+			duration, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid EXTINF %q", line)
+			}
+			pending = duration
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			if pending < 0 {
+				return nil, 0, fmt.Errorf("segment %q has no EXTINF", line)
+			}
+			segments = append(segments, models.HLSSegment{URI: line, Duration: pending})
+			pending = -1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(segments) == 0 {
+		return nil, 0, errors.New("playlist has no segments")
+	}
+	return segments, targetDuration, nil
+}
+
+// BuildMediaPlaylist writes a VOD variant playlist. startOffset, when set,
+// tells the player where to begin within the first segment.
+func BuildMediaPlaylist(segments []models.HLSSegment, targetDuration int, startOffset *float64, uri func(models.HLSSegment) string) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-PLAYLIST-TYPE:VOD\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n#EXT-X-MEDIA-SEQUENCE:0\n", targetDuration)
+	if startOffset != nil {
+		fmt.Fprintf(&b, "#EXT-X-START:TIME-OFFSET=%.3f,PRECISE=YES\n", *startOffset)
+	}
+	for _, segment := range segments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n%s\n", segment.Duration, uri(segment))
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+	return b.String()
+}
+
+// BuildMasterPlaylist lists the renditions, lowest bandwidth first
+func BuildMasterPlaylist(renditions []models.VideoRendition, uri func(models.VideoRendition) string) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, r := range renditions {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n%s\n",
+			r.Bandwidth, r.Width, r.Height, r.Name, uri(r))
+	}
+	return b.String()
+}
+
+// SegmentsInRange returns the segments covering [start, end) and the offset of
+// start within the first of them
+func SegmentsInRange(segments []models.HLSSegment, start, end float64) ([]models.HLSSegment, float64) {
+	var selected []models.HLSSegment
+	offset := 0.0
+	position := 0.0
+	for _, segment := range segments {
+		segStart, segEnd := position, position+segment.Duration
+		position = segEnd
+		if segEnd <= start || segStart >= end {
+			continue
+		}
+		if len(selected) == 0 {
+			offset = start - segStart
+		}
+		selected = append(selected, segment)
+	}
+	return selected, offset
+}
+
+// LoadRenditions returns the HLS renditions of a video, lowest bandwidth first
+func LoadRenditions(ctx context.Context, videoID uint) ([]models.VideoRendition, error) {
+	var renditions []models.VideoRendition
+	err := database.DB.WithContext(ctx).Where("video_id = ?", videoID).Order("bandwidth").Find(&renditions).Error
+	if err == nil && len(renditions) == 0 {
+		err = ErrNoRenditions
+	}
+	return renditions, err
+}
+
+// evenWidth scales the source width to height, rounded to an even number as x264 requires
+func evenWidth(sourceWidth, sourceHeight, height int) int {
+	if sourceWidth == 0 || sourceHeight == 0 {
+		return 0
+	}
+	w := int(math.Round(float64(sourceWidth) * float64(height) / float64(sourceHeight)))
+	return w + w%2
+}
+
+// transcodeRendition encodes one ladder step into an HLS variant in dir
+func transcodeRendition(ctx context.Context, source, dir string, step hlsLadderStep, hasAudio bool) error {
+	args := []string{
+		"-v", "error", "-y", "-i", source,
+		"-vf", fmt.Sprintf("scale=-2:%d", step.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-b:v", fmt.Sprintf("%dk", step.VideoKbps),
+		"-maxrate", fmt.Sprintf("%dk", step.VideoKbps*107/100),
+		"-bufsize", fmt.Sprintf("%dk", step.VideoKbps*3/2),
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", hlsSegmentSeconds),
+		"-sc_threshold", "0",
+	}
+	if hasAudio {
+		args = append(args, "-c:a", "aac", "-b:a", fmt.Sprintf("%dk", step.AudioKbps), "-ac", "2")
+	} else {
+		args = append(args, "-an")
+	}
+	args = append(args,
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(hlsSegmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(dir, "seg_%05d.ts"),
+		filepath.Join(dir, "index.m3u8"),
+	)
+	_, err := runTool(ctx, config.AppConfig.FFmpegPath, args...)
+	return err
+}
+
+func putFile(ctx context.Context, file, objectKey, contentType string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return storage.Default.Put(ctx, objectKey, f, info.Size(), contentType)
+}
+
+// PackageHLS transcodes the source into the bitrate ladder, stores every
+// segment plus the variant and master playlists under prefix, and records the
+// renditions. report is called with the share of the ladder finished (0-1).
+func PackageHLS(ctx context.Context, videoID uint, source, workDir, prefix string, meta *VideoMetadata, report func(done float64)) error {
+	var sourceWidth, sourceHeight int
+	hasAudio := false
+	for _, s := range meta.Streams {
+		switch s.Type {
+		case "video":
+			if sourceHeight == 0 {
+				sourceWidth, sourceHeight = s.Width, s.Height
+			}
+		case "audio":
+			hasAudio = true
+		}
+	}
+
+	steps := ladderFor(sourceHeight)
+	renditions := make([]models.VideoRendition, 0, len(steps))
+	for i, step := range steps {
+		dir := filepath.Join(workDir, "hls", step.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if err := transcodeRendition(ctx, source, dir, step, hasAudio); err != nil {
+			return err
+		}
+
+		playlist, err := os.Open(filepath.Join(dir, "index.m3u8"))
+		if err != nil {
+			return err
+		}
+		segments, targetDuration, err := ParseMediaPlaylist(playlist)
+		playlist.Close()
+		if err != nil {
+			return Permanent(err)
+		}
+
+		keyPrefix := path.Join(prefix, step.Name)
+		for _, segment := range segments {
+			if err := putFile(ctx, filepath.Join(dir, segment.URI), path.Join(keyPrefix, segment.URI), "video/mp2t"); err != nil {
+				return err
+			}
+		}
+		playlistKey := path.Join(keyPrefix, "index.m3u8")
+		if err := putFile(ctx, filepath.Join(dir, "index.m3u8"), playlistKey, "application/vnd.apple.mpegurl"); err != nil {
+			return err
+		}
+
+		segmentsJSON, _ := json.Marshal(segments)
+		renditions = append(renditions, models.VideoRendition{
+			VideoID:        videoID,
+			Name:           step.Name,
+			Width:          evenWidth(sourceWidth, sourceHeight, step.Height),
+			Height:         step.Height,
+			Bandwidth:      (step.VideoKbps + step.AudioKbps) * 1000,
+			PlaylistKey:    playlistKey,
+			TargetDuration: targetDuration,
+			Segments:       segmentsJSON,
+		})
+		report(float64(i+1) / float64(len(steps)))
+	}
+
+	// The stored master uses relative URIs so the tree also plays straight from a bucket
+	master := BuildMasterPlaylist(renditions, func(r models.VideoRendition) string {
+		return r.Name + "/index.m3u8"
+	})
+	if err := storage.Default.Put(ctx, path.Join(prefix, "master.m3u8"), strings.NewReader(master), int64(len(master)), "application/vnd.apple.mpegurl"); err != nil {
+		return err
+	}
+
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("video_id = ?", videoID).Delete(&models.VideoRendition{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&renditions).Error
+	})
+}
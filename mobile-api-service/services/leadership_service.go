@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoteNotFound    = errors.New("leadership note not found")
+	ErrPlayerNotOnTeam = errors.New("player is not on this team")
+	ErrEventNotOnTeam  = errors.New("event does not belong to this team")
+)
+
+// NoteViewer describes who is reading a player's notes and timeline.
+// ManagedTeamIDs are the player's teams the viewer coaches or administers;
+// notes of those teams are visible whatever their visibility.
+type NoteViewer struct {
+	UserID         uint
+	Parent         bool
+	ManagedTeamIDs []uint
+}
+
+func (v NoteViewer) manages(teamID uint) bool {
+	for _, id := range v.ManagedTeamIDs {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleNotes restricts a leadership_notes query to what the viewer may see:
+// everything on the teams they manage, otherwise only the notes shared with
+// them explicitly
+func visibleNotes(query *gorm.DB, playerID uint, viewer NoteViewer) *gorm.DB {
+	conditions := database.DB.Where("1 = 0")
+	if len(viewer.ManagedTeamIDs) > 0 {
+		conditions = conditions.Or("leadership_notes.team_id IN ?", viewer.ManagedTeamIDs)
+	}
+	if viewer.UserID == playerID {
+		conditions = conditions.Or("leadership_notes.visibility = ?", models.NoteVisibilityPlayer)
+	}
+	if viewer.Parent {
+		conditions = conditions.Or("leadership_notes.visibility = ?", models.NoteVisibilityParents)
+	}
+	return query.Where("leadership_notes.player_id = ?", playerID).Where(conditions)
+}
+
+// fillCoachName sets the author name of a single note
+func fillCoachName(ctx context.Context, note *models.LeadershipNote) {
+	notes := []models.LeadershipNote{*note}
+	fillCoachNames(ctx, notes)
+	note.CoachName = notes[0].CoachName
+}
+
+// fillCoachNames sets the author names of notes
+func fillCoachNames(ctx context.Context, notes []models.LeadershipNote) {
+	ids := make([]uint, 0, len(notes))
+	for _, note := range notes {
+		ids = append(ids, note.CoachID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	var users []models.User
+	database.DB.WithContext(ctx).Select("id", "name").Where("id IN ?", ids).Find(&users)
+	names := make(map[uint]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	for i := range notes {
+		notes[i].CoachName = names[notes[i].CoachID]
+	}
+}
+
+// CreateLeadershipNote records a coach's note about a player of the team.
+// Notes are coach-only unless shared explicitly.
+func CreateLeadershipNote(ctx context.Context, playerID, coachID uint, req *models.CreateLeadershipNoteRequest) (*models.LeadershipNote, error) {
+	roster, err := TeamPlayerIDs(ctx, req.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	onTeam := false
+	for _, id := range roster {
+		onTeam = onTeam || id == playerID
+	}
+	if !onTeam {
+		return nil, ErrPlayerNotOnTeam
+	}
+	if req.EventID != nil {
+		var event models.Event
+		if err := database.DB.WithContext(ctx).Select("id", "team_id").First(&event, *req.EventID).Error; err != nil || event.TeamID != req.TeamID {
+			return nil, ErrEventNotOnTeam
+		}
+	}
+
+	note := &models.LeadershipNote{
+		PlayerID:   playerID,
+		TeamID:     req.TeamID,
+		CoachID:    coachID,
+		Score:      req.Score,
+		Comment:    req.Comment,
+		EventID:    req.EventID,
+		Visibility: req.Visibility,
+	}
+	if note.Visibility == 0 {
+		note.Visibility = models.NoteVisibilityCoach
+	}
+	if err := database.DB.WithContext(ctx).Create(note).Error; err != nil {
+		return nil, err
+	}
+	fillCoachName(ctx, note)
+	return note, nil
+}
+
+// ListLeadershipNotes pages the notes about a player the viewer may see, newest first
+func ListLeadershipNotes(ctx context.Context, playerID uint, viewer NoteViewer, teamID uint, page, limit int) ([]models.LeadershipNote, int64, error) {
+	db := database.DB.WithContext(ctx)
+	scope := func() *gorm.DB {
+		query := visibleNotes(db.Model(&models.LeadershipNote{}), playerID, viewer)
+		if teamID != 0 {
+			query = query.Where("leadership_notes.team_id = ?", teamID)
+		}
+		return query
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	notes := []models.LeadershipNote{}
+	if err := scope().Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&notes).Error; err != nil {
+		return nil, 0, err
+	}
+	fillCoachNames(ctx, notes)
+	return notes, total, nil
+}
+
+func GetLeadershipNote(ctx context.Context, noteID uint) (*models.LeadershipNote, error) {
+	var note models.LeadershipNote
+	if err := database.DB.WithContext(ctx).First(&note, noteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoteNotFound
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+func UpdateLeadershipNote(ctx context.Context, note *models.LeadershipNote, req *models.UpdateLeadershipNoteRequest) error {
+	updates := map[string]interface{}{}
+	if req.Score != nil {
+		updates["score"] = *req.Score
+	}
+	if req.Comment != nil {
+		updates["comment"] = *req.Comment
+	}
+	if req.Visibility != nil {
+		updates["visibility"] = *req.Visibility
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := database.DB.WithContext(ctx).Model(note).Updates(updates).Error; err != nil {
+		return err
+	}
+	fillCoachName(ctx, note)
+	return nil
+}
+
+func DeleteLeadershipNote(ctx context.Context, note *models.LeadershipNote) error {
+	return database.DB.WithContext(ctx).Delete(note).Error
+}
+
+// TimelineFilter bounds a player timeline; TeamID 0 covers all their teams
+type TimelineFilter struct {
+	TeamID uint
+	From   time.Time
+	To     time.Time
+	Page   int
+	Limit  int
+}
+
+// timelineEvent is an event of one of the player's teams
+type timelineEvent struct {
+	ID        uint
+	TeamID    uint
+	Type      uint8
+	Title     string
+	StartTime time.Time
+}
+
+// playerTeamEvents returns the past, not cancelled events of the teams within
+// the range that took place while the player was on the team
+func playerTeamEvents(ctx context.Context, playerID uint, teamIDs []uint, from, to time.Time) ([]timelineEvent, error) {
+	var events []timelineEvent
+	err := database.DB.WithContext(ctx).Table("events").
+		Select("events.id, events.team_id, events.type, events.title, events.start_time").
+		Joins("JOIN team_members ON team_members.team_id = events.team_id AND team_members.user_id = ? AND team_members.member_type = ?",
+			playerID, models.MemberTypePlayer).
+		Where("events.team_id IN ? AND events.deleted_at IS NULL AND events.status <> ?", teamIDs, models.EventStatusCancelled).
+		Where("events.start_time BETWEEN ? AND ? AND events.start_time <= ?", from, to, time.Now()).
+		Where("(team_members.joined_at IS NULL OR events.start_time >= team_members.joined_at) AND (team_members.removed_at IS NULL OR events.start_time <= team_members.removed_at)").
+		Order("events.start_time").
+		Scan(&events).Error
+	return events, err
+}
+
+// GetPlayerTimeline merges a player's leadership notes, effort scores and
+// attendance, newest first. Notes follow their visibility; effort scores
+// are only shown on teams the viewer manages; attendance comes from the
+// player's RSVPs to past events while they were on the team.
+func GetPlayerTimeline(ctx context.Context, playerID uint, viewer NoteViewer, filter TimelineFilter) ([]models.TimelineEntry, int, error) {
+	teamIDs, err := PlayerTeamIDs(ctx, playerID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.TeamID != 0 {
+		teamIDs = intersectIDs(teamIDs, []uint{filter.TeamID})
+	}
+	managed := intersectIDs(teamIDs, viewer.ManagedTeamIDs)
+	viewer.ManagedTeamIDs = managed
+	if len(teamIDs) == 0 {
+		return []models.TimelineEntry{}, 0, nil
+	}
+
+	db := database.DB.WithContext(ctx)
+	var entries []models.TimelineEntry
+
+	var notes []models.LeadershipNote
+	if err := visibleNotes(db.Model(&models.LeadershipNote{}), playerID, viewer).
+		Where("team_id IN ? AND created_at BETWEEN ? AND ?", teamIDs, filter.From, filter.To).
+		Find(&notes).Error; err != nil {
+		return nil, 0, err
+	}
+	fillCoachNames(ctx, notes)
+
+	events, err := playerTeamEvents(ctx, playerID, teamIDs, filter.From, filter.To)
+	if err != nil {
+		return nil, 0, err
+	}
+	eventByID := make(map[uint]*timelineEvent, len(events))
+	eventIDs := make([]uint, len(events))
+	for i := range events {
+		eventByID[events[i].ID] = &events[i]
+		eventIDs[i] = events[i].ID
+	}
+	// Notes may be about events outside the range; load their titles too
+	var missing []uint
+	for _, note := range notes {
+		if note.EventID != nil && eventByID[*note.EventID] == nil {
+			missing = append(missing, *note.EventID)
+		}
+	}
+	if len(missing) > 0 {
+		var extra []timelineEvent
+		if err := db.Table("events").Select("id, team_id, type, title, start_time").
+			Where("id IN ?", missing).Scan(&extra).Error; err != nil {
+			return nil, 0, err
+		}
+		for i := range extra {
+			eventByID[extra[i].ID] = &extra[i]
+		}
+	}
+
+	for i := range notes {
+		note := &notes[i]
+		entry := models.TimelineEntry{
+			Type:       models.TimelineTypeNote,
+			OccurredAt: note.CreatedAt,
+			TeamID:     note.TeamID,
+			EventID:    note.EventID,
+			Note:       note,
+		}
+		if note.EventID != nil && eventByID[*note.EventID] != nil {
+			entry.EventType = eventByID[*note.EventID].Type
+			entry.EventTitle = eventByID[*note.EventID].Title
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(eventIDs) > 0 {
+		var rsvps []models.EventRSVP
+		if err := db.Where("user_id = ? AND event_id IN ?", playerID, eventIDs).Find(&rsvps).Error; err != nil {
+			return nil, 0, err
+		}
+		rsvpOf := make(map[uint]*models.EventRSVP, len(rsvps))
+		for i := range rsvps {
+			rsvpOf[rsvps[i].EventID] = &rsvps[i]
+		}
+
+		efforts := make(map[uint]*models.TimelineEffort)
+		if len(managed) > 0 {
+			var metrics []models.EffortMetric
+			if err := db.Where("player_id = ? AND event_id IN ?", playerID, eventIDs).Find(&metrics).Error; err != nil {
+				return nil, 0, err
+			}
+			var buyIns []models.BuyInScore
+			if err := db.Where("player_id = ? AND event_id IN ?", playerID, eventIDs).Find(&buyIns).Error; err != nil {
+				return nil, 0, err
+			}
+			effortAt := func(eventID uint) *models.TimelineEffort {
+				if efforts[eventID] == nil {
+					efforts[eventID] = &models.TimelineEffort{}
+				}
+				return efforts[eventID]
+			}
+			for i := range metrics {
+				effort := effortAt(metrics[i].EventID)
+				effort.HustleScore = &metrics[i].HustleScore
+				effort.EngagementScore = &metrics[i].EngagementScore
+				effort.Notes = metrics[i].Notes
+			}
+			for i := range buyIns {
+				effortAt(buyIns[i].EventID).BuyInScore = &buyIns[i].Score
+			}
+		}
+
+		for i := range events {
+			event := &events[i]
+			eventID := event.ID
+			base := models.TimelineEntry{
+				OccurredAt: event.StartTime,
+				TeamID:     event.TeamID,
+				EventID:    &eventID,
+				EventType:  event.Type,
+				EventTitle: event.Title,
+			}
+
+			attendance := base
+			attendance.Type = models.TimelineTypeAttendance
+			attendance.Attendance = &models.TimelineAttendance{Status: models.AttendanceNoResponse}
+			if rsvp := rsvpOf[event.ID]; rsvp != nil {
+				attendance.Attendance.Status = attendanceStatus(rsvp.Status)
+				attendance.Attendance.Notes = rsvp.Notes
+			}
+			entries = append(entries, attendance)
+
+			if effort := efforts[event.ID]; effort != nil && viewer.manages(event.TeamID) {
+				entry := base
+				entry.Type = models.TimelineTypeEffort
+				entry.Effort = effort
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].OccurredAt.After(entries[j].OccurredAt) })
+	total := len(entries)
+	start := min((filter.Page-1)*filter.Limit, total)
+	end := min(start+filter.Limit, total)
+	return entries[start:end], total, nil
+}
+
+func attendanceStatus(status uint8) string {
+	switch status {
+	case models.RSVPAttending:
+		return models.AttendanceAttending
+	case models.RSVPNotAttending:
+		return models.AttendanceNotAttending
+	case models.RSVPMaybe:
+		return models.AttendanceMaybe
+	}
+	return models.AttendanceNoResponse
+}
+
+func intersectIDs(a, b []uint) []uint {
+	in := make(map[uint]bool, len(b))
+	for _, id := range b {
+		in[id] = true
+	}
+	var both []uint
+	for _, id := range a {
+		if in[id] {
+			both = append(both, id)
+		}
+	}
+	return both
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGoalNotFound        = errors.New("goal not found")
+	ErrMilestoneNotFound   = errors.New("milestone not found")
+	ErrGoalCommentNotFound = errors.New("comment not found")
+)
+
+// goalDateLayout is the format of target dates in requests and notifications
+const goalDateLayout = "2006-01-02"
+
+func parseGoalDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse(goalDateLayout, value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+func goalMilestones(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, id")
+}
+
+// decorateGoals sets the progress of the latest check-in and the completion
+// prompt of each goal
+func decorateGoals(ctx context.Context, goals []models.LifeGoal) error {
+	if len(goals) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(goals))
+	for i, goal := range goals {
+		ids[i] = goal.ID
+	}
+
+	var latest []models.GoalCheckIn
+	if err := database.DB.WithContext(ctx).
+		Where("id IN (?)", database.DB.Model(&models.GoalCheckIn{}).
+			Select("MAX(id)").Where("goal_id IN ?", ids).Group("goal_id")).
+		Find(&latest).Error; err != nil {
+		return err
+	}
+	byGoal := make(map[uint]*models.GoalCheckIn, len(latest))
+	for i := range latest {
+		byGoal[latest[i].GoalID] = &latest[i]
+	}
+
+	for i := range goals {
+		goal := &goals[i]
+		if checkIn := byGoal[goal.ID]; checkIn != nil {
+			goal.Progress = &checkIn.Progress
+			goal.LastCheckInAt = &checkIn.CreatedAt
+		}
+		goal.ReadyToComplete = goal.Status != models.GoalStatusCompleted && allMilestonesDone(goal.Milestones)
+	}
+	return nil
+}
+
+func allMilestonesDone(milestones []models.GoalMilestone) bool {
+	if len(milestones) == 0 {
+		return false
+	}
+	for _, milestone := range milestones {
+		if milestone.CompletedAt == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ListLifeGoals returns a player's goals, optionally of one status, active goals first
+func ListLifeGoals(ctx context.Context, playerID uint, status uint8) ([]models.LifeGoal, error) {
+	query := database.DB.WithContext(ctx).Preload("Milestones", goalMilestones).
+		Where("player_id = ?", playerID)
+	if status != 0 {
+		query = query.Where("status = ?", status)
+	}
+	goals := []models.LifeGoal{}
+	if err := query.Order("status, target_date IS NULL, target_date, id").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, decorateGoals(ctx, goals)
+}
+
+func GetLifeGoal(ctx context.Context, goalID uint) (*models.LifeGoal, error) {
+	var goal models.LifeGoal
+	if err := database.DB.WithContext(ctx).Preload("Milestones", goalMilestones).First(&goal, goalID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	goals := []models.LifeGoal{goal}
+	if err := decorateGoals(ctx, goals); err != nil {
+		return nil, err
+	}
+	return &goals[0], nil
+}
+
+// GetLifeGoalDetail adds the check-in history and coach comments to a goal, newest first
+func GetLifeGoalDetail(ctx context.Context, goal *models.LifeGoal) (*models.LifeGoalDetail, error) {
+	db := database.DB.WithContext(ctx)
+	detail := &models.LifeGoalDetail{
+		LifeGoal: *goal,
+		CheckIns: []models.GoalCheckIn{},
+		Comments: []models.GoalComment{},
+	}
+	if err := db.Where("goal_id = ?", goal.ID).Order("created_at DESC, id DESC").Find(&detail.CheckIns).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("goal_id = ?", goal.ID).Order("created_at DESC, id DESC").Find(&detail.Comments).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(detail.Comments))
+	for i, comment := range detail.Comments {
+		ids[i] = comment.CoachID
+	}
+	if len(ids) > 0 {
+		var users []models.User
+		db.Select("id", "name").Where("id IN ?", ids).Find(&users)
+		names := make(map[uint]string, len(users))
+		for _, user := range users {
+			names[user.ID] = user.Name
+		}
+		for i := range detail.Comments {
+			detail.Comments[i].CoachName = names[detail.Comments[i].CoachID]
+		}
+	}
+	return detail, nil
+}
+
+// CreateLifeGoal creates an active goal with its initial milestones
+func CreateLifeGoal(ctx context.Context, playerID uint, req *models.CreateLifeGoalRequest) (*models.LifeGoal, error) {
+	goal := &models.LifeGoal{
+		PlayerID:    playerID,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      models.GoalStatusActive,
+		TargetDate:  parseGoalDate(req.TargetDate),
+	}
+	for i, title := range req.Milestones {
+		goal.Milestones = append(goal.Milestones, models.GoalMilestone{Title: title, SortOrder: i})
+	}
+	if err := database.DB.WithContext(ctx).Create(goal).Error; err != nil {
+		return nil, err
+	}
+	return GetLifeGoal(ctx, goal.ID)
+}
+
+// UpdateLifeGoal edits a goal. Marking it completed sets completed_at and
+// moving it out of completed clears it; a new target date re-arms the reminder.
+func UpdateLifeGoal(ctx context.Context, goal *models.LifeGoal, req *models.UpdateLifeGoalRequest) (*models.LifeGoal, error) {
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.TargetDate != nil {
+		updates["target_date"] = parseGoalDate(*req.TargetDate)
+		updates["reminder_sent_at"] = nil
+	}
+	if req.Status != nil && *req.Status != goal.Status {
+		updates["status"] = *req.Status
+		if *req.Status == models.GoalStatusCompleted {
+			updates["completed_at"] = time.Now()
+		} else {
+			updates["completed_at"] = nil
+		}
+	}
+	if len(updates) > 0 {
+		if err := database.DB.WithContext(ctx).Model(&models.LifeGoal{}).Where("id = ?", goal.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return GetLifeGoal(ctx, goal.ID)
+}
+
+// DeleteLifeGoal removes a goal with its milestones, check-ins and comments
+func DeleteLifeGoal(ctx context.Context, goal *models.LifeGoal) error {
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.GoalMilestone{}, &models.GoalCheckIn{}, &models.GoalComment{}} {
+			if err := tx.Where("goal_id = ?", goal.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.LifeGoal{}, goal.ID).Error
+	})
+}
+
+// AddGoalMilestone appends a milestone, last unless a sort order is given
+func AddGoalMilestone(ctx context.Context, goal *models.LifeGoal, req *models.CreateMilestoneRequest) (*models.LifeGoal, error) {
+	milestone := &models.GoalMilestone{GoalID: goal.ID, Title: req.Title, SortOrder: len(goal.Milestones)}
+	if req.SortOrder != nil {
+		milestone.SortOrder = *req.SortOrder
+	}
+	if err := database.DB.WithContext(ctx).Create(milestone).Error; err != nil {
+		return nil, err
+	}
+	return GetLifeGoal(ctx, goal.ID)
+}
+
+func findMilestone(goal *models.LifeGoal, milestoneID uint) (*models.GoalMilestone, error) {
+	for i := range goal.Milestones {
+		if goal.Milestones[i].ID == milestoneID {
+			return &goal.Milestones[i], nil
+		}
+	}
+	return nil, ErrMilestoneNotFound
+}
+
+// UpdateGoalMilestone edits or checks off a milestone. Checking off the last
+// open milestone prompts the player to mark the goal complete.
+func UpdateGoalMilestone(ctx context.Context, goal *models.LifeGoal, milestoneID uint, req *models.UpdateMilestoneRequest) (*models.LifeGoal, error) {
+	milestone, err := findMilestone(goal, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.SortOrder != nil {
+		updates["sort_order"] = *req.SortOrder
+	}
+	if req.Completed != nil && *req.Completed != (milestone.CompletedAt != nil) {
+		if *req.Completed {
+			updates["completed_at"] = time.Now()
+		} else {
+			updates["completed_at"] = nil
+		}
+	}
+	if len(updates) > 0 {
+		if err := database.DB.WithContext(ctx).Model(milestone).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	wasReady := goal.ReadyToComplete
+	updated, err := GetLifeGoal(ctx, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	if updated.ReadyToComplete && !wasReady {
+		notifyGoalReady(updated)
+	}
+	return updated, nil
+}
+
+func DeleteGoalMilestone(ctx context.Context, goal *models.LifeGoal, milestoneID uint) (*models.LifeGoal, error) {
+	milestone, err := findMilestone(goal, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.DB.WithContext(ctx).Delete(milestone).Error; err != nil {
+		return nil, err
+	}
+	return GetLifeGoal(ctx, goal.ID)
+}
+
+// AddGoalCheckIn records the player's progress on a goal
+func AddGoalCheckIn(ctx context.Context, goal *models.LifeGoal, req *models.CreateGoalCheckInRequest) (*models.GoalCheckIn, error) {
+	checkIn := &models.GoalCheckIn{GoalID: goal.ID, Progress: *req.Progress, Comment: req.Comment}
+	if err := database.DB.WithContext(ctx).Create(checkIn).Error; err != nil {
+		return nil, err
+	}
+	return checkIn, nil
+}
+
+// ListGoalCheckIns pages the check-ins of a goal, newest first
+func ListGoalCheckIns(ctx context.Context, goalID uint, page, limit int) ([]models.GoalCheckIn, int64, error) {
+	db := database.DB.WithContext(ctx)
+	var total int64
+	if err := db.Model(&models.GoalCheckIn{}).Where("goal_id = ?", goalID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	checkIns := []models.GoalCheckIn{}
+	err := db.Where("goal_id = ?", goalID).Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).Limit(limit).Find(&checkIns).Error
+	return checkIns, total, err
+}
+
+// AddGoalComment records a coach's comment and lets the player know
+func AddGoalComment(ctx context.Context, goal *models.LifeGoal, coachID uint, req *models.CreateGoalCommentRequest) (*models.GoalComment, error) {
+	comment := &models.GoalComment{GoalID: goal.ID, CoachID: coachID, Comment: req.Comment}
+	if err := database.DB.WithContext(ctx).Create(comment).Error; err != nil {
+		return nil, err
+	}
+
+	var coach models.User
+	database.DB.WithContext(ctx).Select("id", "name").First(&coach, coachID)
+	comment.CoachName = coach.Name
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"goal_id":    goal.ID,
+		"comment_id": comment.ID,
+	})
+	NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+		UserIDs: []uint{goal.PlayerID},
+		Type:    models.NotificationTypeGoalComment,
+		Variables: map[string]string{
+			"coach_name": coach.Name,
+			"goal_title": goal.Title,
+			"comment":    comment.Comment,
+		},
+		Data: data,
+	})
+	return comment, nil
+}
+
+// DeleteGoalComment removes a comment; only its author may delete it
+func DeleteGoalComment(ctx context.Context, goal *models.LifeGoal, commentID, coachID uint) error {
+	result := database.DB.WithContext(ctx).
+		Where("id = ? AND goal_id = ? AND coach_id = ?", commentID, goal.ID, coachID).
+		Delete(&models.GoalComment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrGoalCommentNotFound
+	}
+	return nil
+}
+
+func notifyGoalReady(goal *models.LifeGoal) {
+	data, _ := json.Marshal(map[string]interface{}{"goal_id": goal.ID})
+	NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+		UserIDs:   []uint{goal.PlayerID},
+		Type:      models.NotificationTypeGoalReady,
+		Variables: map[string]string{"goal_title": goal.Title},
+		Data:      data,
+	})
+}
+
+func goalReminderDays() int {
+	days, err := strconv.Atoi(config.AppConfig.GoalReminderDays)
+	if err != nil || days <= 0 {
+		days = 7
+	}
+	return days
+}
+
+// SendGoalReminders reminds players of active goals whose target date is
+// within the reminder window and that had no check-in within it. A goal is
+// reminded at most once per window; claiming the reminder with a conditional
+// update keeps concurrent workers from sending it twice.
+func SendGoalReminders(ctx context.Context, now time.Time, days int) (int, error) {
+	db := database.DB.WithContext(ctx)
+	window := time.Duration(days) * 24 * time.Hour
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := now.Add(-window)
+
+	var goals []models.LifeGoal
+	if err := db.Where("status = ? AND target_date BETWEEN ? AND ?",
+		models.GoalStatusActive, today, today.AddDate(0, 0, days)).
+		Where("reminder_sent_at IS NULL OR reminder_sent_at < ?", since).
+		Where("NOT EXISTS (?)", database.DB.Model(&models.GoalCheckIn{}).
+			Select("1").Where("goal_check_ins.goal_id = life_goals.id AND goal_check_ins.created_at >= ?", since)).
+		Find(&goals).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range goals {
+		goal := &goals[i]
+		claim := db.Model(&models.LifeGoal{}).
+			Where("id = ? AND (reminder_sent_at IS NULL OR reminder_sent_at < ?)", goal.ID, since).
+			Update("reminder_sent_at", now)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			continue
+		}
+
+		data, _ := json.Marshal(map[string]interface{}{"goal_id": goal.ID})
+		NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+			UserIDs: []uint{goal.PlayerID},
+			Type:    models.NotificationTypeGoalReminder,
+			Variables: map[string]string{
+				"goal_title":  goal.Title,
+				"target_date": goal.TargetDate.Format(goalDateLayout),
+			},
+			Data: data,
+		})
+		sent++
+	}
+	return sent, nil
+}
+
+// StartGoalReminderWorker checks for goals to remind about every hour
+func StartGoalReminderWorker() {
+	days := goalReminderDays()
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			sent, err := SendGoalReminders(context.Background(), time.Now(), days)
+			if err != nil {
+				log.Printf("Goal reminder worker failed: %v", err)
+			} else if sent > 0 {
+				log.Printf("Sent %d goal reminders", sent)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+
+	"mobile-api-service/models"
+)
+
+// ChannelMessage is what a channel sender receives for one recipient
+type ChannelMessage struct {
+	Notification *models.Notification
+	User         *models.User
+	Subject      string
+	Body         string
+	Data         json.RawMessage
+}
+
+// ChannelSender delivers a rendered notification over a single channel
+type ChannelSender interface {
+	Send(ctx context.Context, msg *ChannelMessage) error
+}
+
+// PermanentDeliveryError marks a failure that retrying cannot fix
+// (e.g. an unknown recipient), so the dispatcher stops after one attempt
+type PermanentDeliveryError struct {
+	Err error
+}
+
+func (e *PermanentDeliveryError) Error() string { return e.Err.Error() }
+func (e *PermanentDeliveryError) Unwrap() error { return e.Err }
+
+// Permanent wraps err so it is not retried
+func Permanent(err error) error {
+	return &PermanentDeliveryError{Err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent *PermanentDeliveryError
+	return errors.As(err, &permanent)
+}
+
+// InboxSender delivers in-app notifications by storing them in the user's inbox
+type InboxSender struct{}
+
+func (InboxSender) Send(ctx context.Context, msg *ChannelMessage) error {
+	if msg.Notification.ID != 0 {
+		return nil
+	}
+	return CreateNotification(ctx, msg.Notification)
+}
+
+// FakeSender is a local stand-in for an external provider. It logs and keeps
+// every message it receives and can be told to fail.
+type FakeSender struct {
+	Channel models.NotificationChannel
+
+	mu       sync.Mutex
+	sent     []ChannelMessage
+	failWith error
+}
+
+func NewFakeSender(channel models.NotificationChannel) *FakeSender {
+	return &FakeSender{Channel: channel}
+}
+
+func (f *FakeSender) Send(ctx context.Context, msg *ChannelMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failWith != nil {
+		return f.failWith
+	}
+	f.sent = append(f.sent, *msg)
+	log.Printf("[%s] notification %d to user %d: %s", f.Channel, msg.Notification.ID, msg.User.ID, msg.Subject)
+	return nil
+}
+
+// FailWith makes every following Send return err (nil to succeed again)
+func (f *FakeSender) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failWith = err
+}
+
+// Sent returns a copy of the messages delivered so far
+func (f *FakeSender) Sent() []ChannelMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ChannelMessage(nil), f.sent...)
+}
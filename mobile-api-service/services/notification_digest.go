@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+)
+
+// digestItemLimit caps how many titles are listed in one summary message
+const digestItemLimit = 10
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateQuietHours checks the time zone and clock fields of a preference
+func ValidateQuietHours(pref *models.NotificationPreference) error {
+	if _, err := time.LoadLocation(pref.TimeZone); err != nil {
+		return fmt.Errorf("invalid time zone %q", pref.TimeZone)
+	}
+	for _, value := range []string{pref.QuietHoursStart, pref.QuietHoursEnd, pref.DigestTime} {
+		if _, err := parseClock(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextClock returns the first instant at or after now (in loc) whose wall clock is the given minute of day
+func nextClock(now time.Time, loc *time.Location, minute int) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), minute/60, minute%60, 0, 0, loc)
+	if next.Before(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// inQuietHours reports whether now falls inside the window and when the window ends.
+// Windows may wrap midnight (e.g. 22:00-07:00).
+func inQuietHours(pref *models.NotificationPreference, now time.Time, loc *time.Location) (bool, time.Time) {
+	if !pref.QuietHoursEnabled {
+		return false, time.Time{}
+	}
+	start, err1 := parseClock(pref.QuietHoursStart)
+	end, err2 := parseClock(pref.QuietHoursEnd)
+	if err1 != nil || err2 != nil || start == end {
+		return false, time.Time{}
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	inside := (start < end && minute >= start && minute < end) ||
+		(start > end && (minute >= start || minute < end))
+	if !inside {
+		return false, time.Time{}
+	}
+	return true, nextClock(now, loc, end)
+}
+
+// HoldUntil returns when a non-urgent notification arriving at now may ping the
+// user, or false if it can be delivered right away
+func HoldUntil(pref *models.NotificationPreference, now time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(pref.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	if pref.DigestEnabled {
+		minute, err := parseClock(pref.DigestTime)
+		if err == nil {
+			release := nextClock(now, loc, minute)
+			// Never release a digest into the middle of quiet hours
+			if quiet, end := inQuietHours(pref, release, loc); quiet {
+				release = end
+			}
+			return release, true
+		}
+	}
+
+	if quiet, end := inQuietHours(pref, now, loc); quiet {
+		return end, true
+	}
+	return time.Time{}, false
+}
+
+// holdNotification queues a stored notification for the user's next summary
+func holdNotification(ctx context.Context, notification *models.Notification, releaseAt time.Time) error {
+	return database.DB.WithContext(ctx).Create(&models.NotificationDigestItem{
+		UserID:         notification.UserID,
+		NotificationID: notification.ID,
+		ReleaseAt:      releaseAt,
+	}).Error
+}
+
+// SendDueDigests groups released items per user and sends each user a single
+// summary over their external channels
+func (d *Dispatcher) SendDueDigests(ctx context.Context, now time.Time) error {
+	var items []models.NotificationDigestItem
+	err := database.DB.WithContext(ctx).
+		Where("delivered_at IS NULL AND release_at <= ?", now).
+		Order("user_id, id").
+		Find(&items).Error
+	if err != nil {
+		return err
+	}
+
+	byUser := map[uint][]models.NotificationDigestItem{}
+	var userIDs []uint
+	for _, item := range items {
+		if _, ok := byUser[item.UserID]; !ok {
+			userIDs = append(userIDs, item.UserID)
+		}
+		byUser[item.UserID] = append(byUser[item.UserID], item)
+	}
+
+	for _, userID := range userIDs {
+		if err := d.sendDigest(ctx, userID, byUser[userID]); err != nil {
+			log.Printf("Failed to send digest to user %d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// sendDigest claims the user's released items and sends the summary. When
+// anything fails before delivery starts the claim is released, so the items
+// are retried by the next run instead of being lost.
+func (d *Dispatcher) sendDigest(ctx context.Context, userID uint, items []models.NotificationDigestItem) (err error) {
+	itemIDs := make([]uint, 0, len(items))
+	notificationIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+		notificationIDs = append(notificationIDs, item.NotificationID)
+	}
+
+	// Claim the items first so a second worker cannot send the same digest.
+	// The claim time is whole seconds so it matches the stored column exactly.
+	claimedAt := time.Now().Truncate(time.Second)
+	claim := database.DB.WithContext(ctx).Model(&models.NotificationDigestItem{}).
+		Where("id IN ? AND delivered_at IS NULL", itemIDs).
+		Update("delivered_at", claimedAt)
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		release := database.DB.WithContext(context.Background()).Model(&models.NotificationDigestItem{}).
+			Where("id IN ? AND delivered_at = ?", itemIDs, claimedAt).
+			Update("delivered_at", nil)
+		if release.Error != nil {
+			log.Printf("Failed to release digest items of user %d: %v", userID, release.Error)
+		}
+	}()
+
+	var notifications []models.Notification
+	err = database.DB.WithContext(ctx).
+		Where("id IN ?", notificationIDs).
+		Order("id").
+		Find(&notifications).Error
+	if err != nil {
+		return err
+	}
+	if len(notifications) == 0 {
+		// Every held notification was deleted from the inbox in the meantime
+		return nil
+	}
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return err
+	}
+	prefs, err := LoadNotificationPreferences(ctx, []uint{userID})
+	if err != nil {
+		return err
+	}
+	pref := prefs[userID]
+
+	lines := make([]string, 0, digestItemLimit+1)
+	for i, n := range notifications {
+		if i == digestItemLimit {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(notifications)-digestItemLimit))
+			break
+		}
+		lines = append(lines, "• "+n.Title)
+	}
+	rendered, err := RenderNotification(ctx, models.NotificationTypeDigest, "", map[string]string{
+		"count": strconv.Itoa(len(notifications)),
+		"items": strings.Join(lines, "\n"),
+	})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uint, 0, len(notifications))
+	for _, n := range notifications {
+		ids = append(ids, n.ID)
+	}
+	data, _ := json.Marshal(map[string]interface{}{"notification_ids": ids})
+
+	// The summary is kept in the inbox for reference but starts read so the
+	// badge keeps counting only the individual notifications
+	readAt := time.Now()
+	msg := &ChannelMessage{
+		Notification: &models.Notification{
+			UserID: userID,
+			Type:   models.NotificationTypeDigest,
+			Title:  rendered.Subject,
+			Body:   rendered.Body,
+			Data:   data,
+			ReadAt: &readAt,
+		},
+		User:    &user,
+		Subject: rendered.Subject,
+		Body:    rendered.Body,
+		Data:    data,
+	}
+
+	for _, channel := range enabledChannels(pref, &user) {
+		d.deliver(ctx, channel, msg)
+	}
+
+	database.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("id IN ?", ids).
+		Update("sent_at", time.Now())
+	return nil
+}
+
+// StartDigestWorker checks for released digests every minute in the background
+func StartDigestWorker() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			if err := NotificationDispatcher.SendDueDigests(context.Background(), now); err != nil {
+				log.Printf("Digest worker failed: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+)
+
+// RetryPolicy controls how often and how fast failed deliveries are retried
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// Backoff returns the wait before the given attempt (attempt 2 waits BaseDelay, then doubling)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 2; i < attempt; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// ChannelSummary counts deliveries for one channel
+type ChannelSummary struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
+
+// DispatchResult summarizes the fan-out of one intent
+type DispatchResult struct {
+	Recipients int                        `json:"recipients"`
+	OptedOut   int                        `json:"opted_out"`
+	Held       int                        `json:"held"`
+	Channels   map[string]*ChannelSummary `json:"channels"`
+}
+
+// Dispatcher fans notification intents out to every enabled channel
+type Dispatcher struct {
+	senders map[models.NotificationChannel]ChannelSender
+	retry   RetryPolicy
+}
+
+func NewDispatcher(senders map[models.NotificationChannel]ChannelSender, retry RetryPolicy) *Dispatcher {
+	return &Dispatcher{senders: senders, retry: retry}
+}
+
+var NotificationDispatcher *Dispatcher
+
+// InitNotificationDispatcher wires the default channel senders
+func InitNotificationDispatcher() {
+	NotificationDispatcher = NewDispatcher(map[models.NotificationChannel]ChannelSender{
+		models.ChannelInApp: InboxSender{},
+		models.ChannelPush:  NewPushChannelSender(),
+		models.ChannelEmail: NewFakeSender(models.ChannelEmail),
+		models.ChannelSMS:   NewFakeSender(models.ChannelSMS),
+	}, DefaultRetryPolicy)
+}
+
+// LoadNotificationPreferences returns preferences for the users, using column defaults for users without a row
+func LoadNotificationPreferences(ctx context.Context, userIDs []uint) (map[uint]models.NotificationPreference, error) {
+	var rows []models.NotificationPreference
+	if err := database.DB.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	prefs := make(map[uint]models.NotificationPreference, len(userIDs))
+	for _, id := range userIDs {
+		prefs[id] = models.DefaultNotificationPreference(id)
+	}
+	for _, row := range rows {
+		prefs[row.UserID] = row
+	}
+	return prefs, nil
+}
+
+// CategoryEnabled reports whether the user accepts notifications of the category
+func CategoryEnabled(pref models.NotificationPreference, category models.NotificationCategory) bool {
+	switch category {
+	case models.CategoryGameDay:
+		return pref.GameDayNotifications
+	case models.CategoryScheduleChange:
+		return pref.ScheduleChangeNotifications
+	case models.CategoryAnnouncement:
+		return pref.AnnouncementNotifications
+	case models.CategoryMedia:
+		return pref.MediaNotifications
+	}
+	return true
+}
+
+// enabledChannels lists the channels to use for a user, in-app first so the
+// inbox row exists before external channels reference it
+func enabledChannels(pref models.NotificationPreference, user *models.User) []models.NotificationChannel {
+	channels := []models.NotificationChannel{models.ChannelInApp}
+	if pref.PushEnabled {
+		channels = append(channels, models.ChannelPush)
+	}
+	if pref.EmailEnabled && user.Email != "" {
+		channels = append(channels, models.ChannelEmail)
+	}
+	if pref.SMSEnabled && user.Phone != "" {
+		channels = append(channels, models.ChannelSMS)
+	}
+	return channels
+}
+
+// dispatchConcurrency bounds how many recipients are delivered to at once, so
+// retries during a provider outage do not hold up a team-wide fan-out
+const dispatchConcurrency = 16
+
+// Dispatch renders the intent's template and delivers it to every recipient
+// over the channels their preferences allow. Rendering errors abort before
+// anything is sent. Recipients are delivered to concurrently; each
+// recipient's channels run in order.
+func (d *Dispatcher) Dispatch(ctx context.Context, intent *models.NotificationIntent) (*DispatchResult, error) {
+	rendered, err := RenderNotification(ctx, intent.Type, intent.Locale, intent.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := database.DB.WithContext(ctx).Where("id IN ?", intent.UserIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) < len(intent.UserIDs) {
+		found := make(map[uint]bool, len(users))
+		for _, user := range users {
+			found[user.ID] = true
+		}
+		for _, id := range intent.UserIDs {
+			if !found[id] {
+				log.Printf("Notification %s skipped unknown user %d", intent.Type, id)
+			}
+		}
+	}
+	prefs, err := LoadNotificationPreferences(ctx, intent.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DispatchResult{Channels: map[string]*ChannelSummary{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, dispatchConcurrency)
+	now := time.Now()
+
+	for i := range users {
+		user := &users[i]
+		pref := prefs[user.ID]
+		if !CategoryEnabled(pref, models.CategoryForType(intent.Type)) {
+			result.OptedOut++
+			continue
+		}
+		result.Recipients++
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			d.dispatchTo(ctx, intent, rendered, user, &pref, now, result, &mu)
+		}()
+	}
+	wg.Wait()
+
+	return result, nil
+}
+
+// dispatchTo delivers the rendered intent to one recipient, adding the
+// outcome to result under mu
+func (d *Dispatcher) dispatchTo(ctx context.Context, intent *models.NotificationIntent, rendered *RenderedTemplate, user *models.User, pref *models.NotificationPreference, now time.Time, result *DispatchResult, mu *sync.Mutex) {
+	msg := &ChannelMessage{
+		Notification: &models.Notification{
+			UserID: user.ID,
+			Type:   intent.Type,
+			Title:  rendered.Subject,
+			Body:   rendered.Body,
+			Data:   intent.Data,
+		},
+		User:    user,
+		Subject: rendered.Subject,
+		Body:    rendered.Body,
+		Data:    intent.Data,
+	}
+
+	// Non-urgent notifications still land in the inbox but external pings wait for the user's summary
+	var releaseAt time.Time
+	held := false
+	if !intent.IsUrgent() {
+		releaseAt, held = HoldUntil(pref, now)
+	}
+
+	delivered := false
+	for _, channel := range enabledChannels(*pref, user) {
+		if held && channel != models.ChannelInApp {
+			continue
+		}
+		sent := d.deliver(ctx, channel, msg)
+		delivered = delivered || sent
+
+		mu.Lock()
+		summary := result.Channels[channel.String()]
+		if summary == nil {
+			summary = &ChannelSummary{}
+			result.Channels[channel.String()] = summary
+		}
+		if sent {
+			summary.Sent++
+		} else {
+			summary.Failed++
+		}
+		mu.Unlock()
+	}
+
+	if held && msg.Notification.ID != 0 {
+		if err := holdNotification(ctx, msg.Notification, releaseAt); err != nil {
+			log.Printf("Failed to hold notification %d for user %d: %v", msg.Notification.ID, user.ID, err)
+			return
+		}
+		mu.Lock()
+		result.Held++
+		mu.Unlock()
+		return
+	}
+	if delivered && msg.Notification.ID != 0 {
+		database.DB.WithContext(ctx).Model(msg.Notification).Update("sent_at", time.Now())
+	}
+}
+
+// DispatchAsync runs Dispatch in the background and logs the outcome
+func (d *Dispatcher) DispatchAsync(intent *models.NotificationIntent) {
+	go func() {
+		result, err := d.Dispatch(context.Background(), intent)
+		if err != nil {
+			log.Printf("Notification dispatch of %s failed: %v", intent.Type, err)
+			return
+		}
+		log.Printf("Notification dispatch of %s: %d recipients, %d opted out", intent.Type, result.Recipients, result.OptedOut)
+	}()
+}
+
+// deliver sends msg over one channel, retrying with exponential backoff and
+// recording every attempt in notification_deliveries
+func (d *Dispatcher) deliver(ctx context.Context, channel models.NotificationChannel, msg *ChannelMessage) bool {
+	sender, ok := d.senders[channel]
+	if !ok {
+		return false
+	}
+
+	for attempt := 1; attempt <= d.retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-time.After(d.retry.Backoff(attempt)):
+			}
+		}
+
+		err := sender.Send(ctx, msg)
+
+		delivery := models.NotificationDelivery{
+			NotificationID: msg.Notification.ID,
+			UserID:         msg.User.ID,
+			Channel:        channel,
+			Attempt:        attempt,
+			Status:         models.DeliveryStatusSent,
+		}
+		if err != nil {
+			delivery.Status = models.DeliveryStatusFailed
+			delivery.Error = err.Error()
+		}
+		if dbErr := database.DB.WithContext(ctx).Create(&delivery).Error; dbErr != nil {
+			log.Printf("Failed to record %s delivery for user %d: %v", channel, msg.User.ID, dbErr)
+		}
+
+		if err == nil {
+			return true
+		}
+		if isPermanent(err) {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// unreadCountTTL bounds how long a badge count can drift if a write ever bypasses this service
+const unreadCountTTL = 24 * time.Hour
+
+// adjustIfCached only touches counters that are already cached so a miss is always
+// rebuilt from the database rather than from a partial delta
+var adjustIfCached = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return nil
+end
+local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if v < 0 then
+	redis.call('SET', KEYS[1], 0)
+	v = 0
+end
+redis.call('EXPIRE', KEYS[1], ARGV[2])
+return v
+`)
+
+func unreadCountKey(userID uint) string {
+	return fmt.Sprintf("notifications:unread:%d", userID)
+}
+
+func adjustUnreadCount(ctx context.Context, userID uint, delta int64) {
+	if database.RedisClient == nil || delta == 0 {
+		return
+	}
+	err := adjustIfCached.Run(ctx, database.RedisClient, []string{unreadCountKey(userID)}, delta, int(unreadCountTTL.Seconds())).Err()
+	if err != nil && err != redis.Nil {
+		// Drop the key so the next read recounts instead of serving a stale badge
+		database.RedisClient.Del(ctx, unreadCountKey(userID))
+	}
+}
+
+// CreateNotification stores an inbox entry and bumps the cached unread count
+func CreateNotification(ctx context.Context, notification *models.Notification) error {
+	if err := database.DB.WithContext(ctx).Create(notification).Error; err != nil {
+		return err
+	}
+	if notification.ReadAt == nil {
+		adjustUnreadCount(ctx, notification.UserID, 1)
+	}
+	return nil
+}
+
+// ListNotifications returns a page of a user's notifications, newest first.
+// cursor is the ID of the last item of the previous page (0 for the first page).
+func ListNotifications(ctx context.Context, userID uint, cursor uint, limit int, unreadOnly bool) ([]models.Notification, uint, error) {
+	var notifications []models.Notification
+
+	query := database.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	// Fetch one extra row to know whether another page exists
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var nextCursor uint
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		nextCursor = notifications[limit-1].ID
+	}
+	return notifications, nextCursor, nil
+}
+
+// UnreadCount returns the cached unread count, rebuilding it from the database on a miss
+func UnreadCount(ctx context.Context, userID uint) (int64, error) {
+	if database.RedisClient != nil {
+		count, err := database.RedisClient.Get(ctx, unreadCountKey(userID)).Int64()
+		if err == nil {
+			return count, nil
+		}
+	}
+
+	var count int64
+	err := database.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if database.RedisClient != nil {
+		database.RedisClient.Set(ctx, unreadCountKey(userID), count, unreadCountTTL)
+	}
+	return count, nil
+}
+
+// MarkNotificationRead marks one of the user's notifications as read
+func MarkNotificationRead(ctx context.Context, userID, notificationID uint) error {
+	result := database.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		// Either already read (a no-op) or not the user's notification
+		var count int64
+		database.DB.WithContext(ctx).Model(&models.Notification{}).
+			Where("id = ? AND user_id = ?", notificationID, userID).
+			Count(&count)
+		if count == 0 {
+			return ErrNotificationNotFound
+		}
+		return nil
+	}
+
+	adjustUnreadCount(ctx, userID, -1)
+	return nil
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user as read
+func MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	result := database.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// Drop rather than zero the counter so a notification created mid-update is not lost
+	if database.RedisClient != nil {
+		database.RedisClient.Del(ctx, unreadCountKey(userID))
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteNotification removes one of the user's notifications
+func DeleteNotification(ctx context.Context, userID, notificationID uint) error {
+	var notification models.Notification
+	err := database.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		First(&notification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotificationNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	result := database.DB.WithContext(ctx).Delete(&notification)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 && notification.ReadAt == nil {
+		adjustUnreadCount(ctx, userID, -1)
+	}
+	return nil
+}
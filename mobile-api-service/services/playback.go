@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/storage"
+)
+
+var ErrRenditionNotFound = errors.New("rendition not found")
+
+// Playlists are fetched by video players that cannot send the bearer token,
+// so their URLs carry an expiry and an HMAC of path and expiry instead.
+
+func playbackSigningKey() []byte {
+	if config.AppConfig.MediaSigningSecret != "" {
+		return []byte(config.AppConfig.MediaSigningSecret)
+	}
+	return []byte(config.AppConfig.JWTSecret)
+}
+
+func apiPathSignature(p string, expires int64) string {
+	mac := hmac.New(sha256.New, playbackSigningKey())
+	fmt.Fprintf(mac, "%s\n%d", p, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignAPIPath returns the absolute, signed URL of an API path
+func SignAPIPath(p string, expires time.Time) string {
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("signature", apiPathSignature(p, expires.Unix()))
+	return strings.TrimRight(config.AppConfig.PublicAPIBaseURL, "/") + p + "?" + q.Encode()
+}
+
+// VerifyAPIPath checks the expires and signature query values of a signed API
+// URL and returns the expiry so nested playlist URLs can reuse it
+func VerifyAPIPath(p, expires, signature string) (time.Time, bool) {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return time.Time{}, false
+	}
+	if !hmac.Equal([]byte(signature), []byte(apiPathSignature(p, exp))) {
+		return time.Time{}, false
+	}
+	return time.Unix(exp, 0), true
+}
+
+func videoMasterPath(videoID uint) string {
+	return fmt.Sprintf("/api/videos/%d/hls/master.m3u8", videoID)
+}
+
+func videoVariantPath(videoID uint, rendition string) string {
+	return fmt.Sprintf("/api/videos/%d/hls/%s/index.m3u8", videoID, rendition)
+}
+
+func clipMasterPath(tagID uint) string {
+	return fmt.Sprintf("/api/video-tags/%d/hls/master.m3u8", tagID)
+}
+
+func clipVariantPath(tagID uint, rendition string) string {
+	return fmt.Sprintf("/api/video-tags/%d/hls/%s/index.m3u8", tagID, rendition)
+}
+
+// VideoHLSURL is the signed master playlist URL of a video
+func VideoHLSURL(videoID uint, expires time.Time) string {
+	return SignAPIPath(videoMasterPath(videoID), expires)
+}
+
+// ClipHLSURL is the signed master playlist URL of a tag's clip
+func ClipHLSURL(tagID uint, expires time.Time) string {
+	return SignAPIPath(clipMasterPath(tagID), expires)
+}
+
+func loadRendition(ctx context.Context, videoID uint, name string) (*models.VideoRendition, []models.HLSSegment, error) {
+	var rendition models.VideoRendition
+	if err := database.DB.WithContext(ctx).Where("video_id = ? AND name = ?", videoID, name).First(&rendition).Error; err != nil {
+		return nil, nil, ErrRenditionNotFound
+	}
+	var segments []models.HLSSegment
+	if err := json.Unmarshal(rendition.Segments, &segments); err != nil {
+		return nil, nil, err
+	}
+	return &rendition, segments, nil
+}
+
+// signedSegmentURI signs a segment for direct download from storage
+func signedSegmentURI(ctx context.Context, rendition *models.VideoRendition) func(models.HLSSegment) string {
+	prefix := path.Dir(rendition.PlaylistKey)
+	ttl := PlaybackURLTTL()
+	return func(segment models.HLSSegment) string {
+		signed, _ := storage.Default.SignedURL(ctx, path.Join(prefix, segment.URI), ttl)
+		return signed
+	}
+}
+
+// RenderVideoMaster builds the master playlist of a video whose variant URLs
+// share the master's expiry
+func RenderVideoMaster(ctx context.Context, videoID uint, expires time.Time) (string, error) {
+	renditions, err := LoadRenditions(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	return BuildMasterPlaylist(renditions, func(r models.VideoRendition) string {
+		return SignAPIPath(videoVariantPath(videoID, r.Name), expires)
+	}), nil
+}
+
+// RenderVideoVariant builds a variant playlist with signed segment URLs
+func RenderVideoVariant(ctx context.Context, videoID uint, name string) (string, error) {
+	rendition, segments, err := loadRendition(ctx, videoID, name)
+	if err != nil {
+		return "", err
+	}
+	return BuildMediaPlaylist(segments, rendition.TargetDuration, nil, signedSegmentURI(ctx, rendition)), nil
+}
+
+// RenderClipMaster builds the master playlist of a tag's clip
+func RenderClipMaster(ctx context.Context, tag *models.VideoTag, expires time.Time) (string, error) {
+	renditions, err := LoadRenditions(ctx, tag.VideoID)
+	if err != nil {
+		return "", err
+	}
+	return BuildMasterPlaylist(renditions, func(r models.VideoRendition) string {
+		return SignAPIPath(clipVariantPath(tag.ID, r.Name), expires)
+	}), nil
+}
+
+// RenderClipVariant builds a variant playlist holding only the segments that
+// cover the tag's time range, starting playback at the tag's first second
+func RenderClipVariant(ctx context.Context, tag *models.VideoTag, name string) (string, error) {
+	rendition, segments, err := loadRendition(ctx, tag.VideoID, name)
+	if err != nil {
+		return "", err
+	}
+	selected, offset := SegmentsInRange(segments, tag.StartTime, tag.EndTime)
+	if len(selected) == 0 {
+		return "", ErrRenditionNotFound
+	}
+	return BuildMediaPlaylist(selected, rendition.TargetDuration, &offset, signedSegmentURI(ctx, rendition)), nil
+}
@@ -0,0 +1,498 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRecruiterAccountRequired   = errors.New("set up your recruiter account first")
+	ErrProfilePermissionNotFound  = errors.New("profile permission not found")
+	ErrProfileAccessExists        = errors.New("access to this profile was already requested or granted")
+	ErrProfileAccessNotPending    = errors.New("only pending requests can be approved or denied")
+	ErrInvalidProfileGrant        = errors.New("user_id must be a recruiter account of the granted type; public grants take no user_id")
+	ErrProfilePermissionNotActive = errors.New("only pending or approved permissions can be revoked")
+	ErrNoProfileApprover          = errors.New("this player is a minor without a linked parent, so nobody can approve access yet")
+)
+
+// GetRecruiterAccount returns the recruiter account of the user
+func GetRecruiterAccount(ctx context.Context, userID uint) (*models.RecruiterAccount, error) {
+	var account models.RecruiterAccount
+	err := database.DB.WithContext(ctx).Where("user_id = ?", userID).First(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecruiterAccountRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// SaveRecruiterAccount creates or replaces the user's recruiter account details
+func SaveRecruiterAccount(ctx context.Context, userID uint, req *models.SaveRecruiterAccountRequest) (*models.RecruiterAccount, error) {
+	account, err := GetRecruiterAccount(ctx, userID)
+	switch {
+	case errors.Is(err, ErrRecruiterAccountRequired):
+		account = &models.RecruiterAccount{UserID: userID}
+	case err != nil:
+		return nil, err
+	}
+
+	account.AccountType = req.AccountType
+	account.OrganizationName = req.OrganizationName
+	account.Title = req.Title
+	account.Division = req.Division
+	account.Website = req.Website
+	if err := database.DB.WithContext(ctx).Save(account).Error; err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// visibleToRecruiter restricts a query on recruiting_profiles to the active
+// profiles the recruiter may see: those with an approved public grant or an
+// approved grant to the recruiter
+func visibleToRecruiter(db *gorm.DB, recruiterID uint) *gorm.DB {
+	granted := database.DB.Model(&models.ProfilePermission{}).Select("1").
+		Where("profile_permissions.profile_id = recruiting_profiles.id AND profile_permissions.status = ?", models.ProfilePermissionApproved).
+		Where(database.DB.Where("profile_permissions.permission_type = ?", models.ProfilePermissionPublic).
+			Or("profile_permissions.granted_to = ?", recruiterID))
+	return db.Where("recruiting_profiles.is_active = ?", true).Where("EXISTS (?)", granted)
+}
+
+// SearchRecruiterDirectory lists the profiles the recruiter may see, by
+// graduation year then name
+func SearchRecruiterDirectory(ctx context.Context, recruiterID uint, filter models.DirectoryFilter, page, limit int) ([]models.DirectoryProfile, int64, error) {
+	scope := func() *gorm.DB {
+		query := visibleToRecruiter(database.DB.WithContext(ctx).Table("recruiting_profiles"), recruiterID).
+			Joins("JOIN users ON users.id = recruiting_profiles.player_id AND users.deleted_at IS NULL")
+		if filter.Sport != "" {
+			query = query.Where("recruiting_profiles.sport = ?", NormalizeSport(filter.Sport))
+		}
+		if filter.GraduationYear > 0 {
+			query = query.Where("recruiting_profiles.graduation_year = ?", filter.GraduationYear)
+		}
+		if filter.Position != "" {
+			query = query.Where("recruiting_profiles.position = ?", filter.Position)
+		}
+		if filter.MinHeight > 0 {
+			query = query.Where("recruiting_profiles.height_inches >= ?", filter.MinHeight)
+		}
+		if filter.MaxHeight > 0 {
+			query = query.Where("recruiting_profiles.height_inches <= ?", filter.MaxHeight)
+		}
+		return query
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	profiles := []models.DirectoryProfile{}
+	err := scope().
+		Select("recruiting_profiles.id AS profile_id, recruiting_profiles.player_id, users.name AS player_name, " +
+			"recruiting_profiles.sport, recruiting_profiles.position, recruiting_profiles.graduation_year, " +
+			"recruiting_profiles.height_inches, recruiting_profiles.weight, recruiting_profiles.profile_photo_url").
+		Order("recruiting_profiles.graduation_year IS NULL, recruiting_profiles.graduation_year, users.name").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&profiles).Error
+	return profiles, total, err
+}
+
+// GetVisibleRecruitingProfile returns a profile the recruiter may see;
+// profiles they may not see are reported as not found
+func GetVisibleRecruitingProfile(ctx context.Context, recruiterID, profileID uint) (*models.RecruitingProfile, error) {
+	var profile models.RecruitingProfile
+	err := visibleToRecruiter(database.DB.WithContext(ctx).Model(&models.RecruitingProfile{}), recruiterID).
+		Where("recruiting_profiles.id = ?", profileID).
+		First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecruitingProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// RecordRecruiterView logs that the recruiter opened the profile
+func RecordRecruiterView(ctx context.Context, profileID, recruiterID uint) error {
+	return database.DB.WithContext(ctx).Create(&models.RecruiterProfileView{
+		ProfileID:   profileID,
+		RecruiterID: recruiterID,
+		ViewedAt:    time.Now(),
+	}).Error
+}
+
+// ProfileAccessApproverIDs returns who decides on access to a profile: the
+// player, or their approved parents while the player is a minor. A minor
+// without approved parents has no approvers.
+func ProfileAccessApproverIDs(ctx context.Context, profile *models.RecruitingProfile) ([]uint, error) {
+	if IsMinor(profile) {
+		return ParentIDsOf(ctx, []uint{profile.PlayerID})
+	}
+	return []uint{profile.PlayerID}, nil
+}
+
+// RequestProfileAccess asks for access to an active profile for the
+// recruiter account's type and notifies the approvers. A denied or revoked
+// request can be asked again. Without approvers nothing is requested, since
+// the request could never be decided.
+func RequestProfileAccess(ctx context.Context, profile *models.RecruitingProfile, account *models.RecruiterAccount, req *models.RequestProfileAccessRequest) (*models.ProfilePermission, error) {
+	if !profile.IsActive {
+		return nil, ErrRecruitingProfileNotFound
+	}
+
+	approvers, err := ProfileAccessApproverIDs(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, ErrNoProfileApprover
+	}
+
+	db := database.DB.WithContext(ctx)
+	var permission models.ProfilePermission
+	err = db.Where("profile_id = ? AND granted_to = ?", profile.ID, account.UserID).First(&permission).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		permission = models.ProfilePermission{ProfileID: profile.ID, GrantedTo: &account.UserID}
+	case err != nil:
+		return nil, err
+	case permission.Status == models.ProfilePermissionPending || permission.Status == models.ProfilePermissionApproved:
+		return nil, ErrProfileAccessExists
+	}
+
+	permission.PermissionType = account.AccountType
+	permission.Status = models.ProfilePermissionPending
+	permission.Message = req.Message
+	permission.GrantedBy = nil
+	permission.DecidedAt = nil
+	if err := db.Save(&permission).Error; err != nil {
+		return nil, err
+	}
+
+	var recruiter, player models.User
+	db.Select("id", "name").First(&recruiter, account.UserID)
+	db.Select("id", "name").First(&player, profile.PlayerID)
+	data, _ := json.Marshal(map[string]interface{}{
+		"profile_id":    profile.ID,
+		"permission_id": permission.ID,
+	})
+	NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+		UserIDs: approvers,
+		Type:    models.NotificationTypeProfileAccess,
+		Variables: map[string]string{
+			"recruiter_name":    recruiter.Name,
+			"organization_name": account.OrganizationName,
+			"player_name":       player.Name,
+			"message":           permission.Message,
+		},
+		Data: data,
+	})
+	return &permission, nil
+}
+
+// ListRecruiterAccessRequests returns the recruiter's requests and grants,
+// newest first, with the players' names
+func ListRecruiterAccessRequests(ctx context.Context, recruiterID uint) ([]models.ProfilePermission, error) {
+	db := database.DB.WithContext(ctx)
+	permissions := []models.ProfilePermission{}
+	if err := db.Where("granted_to = ?", recruiterID).Order("updated_at DESC, id DESC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	if len(permissions) == 0 {
+		return permissions, nil
+	}
+
+	var rows []struct {
+		ProfileID  uint
+		PlayerName string
+	}
+	profileIDs := make([]uint, len(permissions))
+	for i, permission := range permissions {
+		profileIDs[i] = permission.ProfileID
+	}
+	if err := db.Table("recruiting_profiles").
+		Joins("JOIN users ON users.id = recruiting_profiles.player_id").
+		Where("recruiting_profiles.id IN ?", profileIDs).
+		Select("recruiting_profiles.id AS profile_id, users.name AS player_name").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		names[row.ProfileID] = row.PlayerName
+	}
+	for i := range permissions {
+		permissions[i].PlayerName = names[permissions[i].ProfileID]
+	}
+	return permissions, nil
+}
+
+// WithdrawProfileAccess removes one of the recruiter's own requests or grants
+func WithdrawProfileAccess(ctx context.Context, recruiterID, permissionID uint) error {
+	result := database.DB.WithContext(ctx).
+		Where("id = ? AND granted_to = ?", permissionID, recruiterID).
+		Delete(&models.ProfilePermission{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProfilePermissionNotFound
+	}
+	return nil
+}
+
+func GetProfilePermission(ctx context.Context, permissionID uint) (*models.ProfilePermission, error) {
+	var permission models.ProfilePermission
+	err := database.DB.WithContext(ctx).First(&permission, permissionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProfilePermissionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+// recruiterDetail is the name and account of a recruiter
+type recruiterDetail struct {
+	UserID           uint
+	Name             string
+	AccountType      uint8
+	OrganizationName string
+	Title            string
+}
+
+func recruiterDetails(ctx context.Context, userIDs []uint) (map[uint]recruiterDetail, error) {
+	details := make(map[uint]recruiterDetail, len(userIDs))
+	if len(userIDs) == 0 {
+		return details, nil
+	}
+	var rows []recruiterDetail
+	err := database.DB.WithContext(ctx).Table("users").
+		Joins("LEFT JOIN recruiter_accounts ON recruiter_accounts.user_id = users.id").
+		Where("users.id IN ?", userIDs).
+		Select("users.id AS user_id, users.name, recruiter_accounts.account_type, recruiter_accounts.organization_name, recruiter_accounts.title").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		details[row.UserID] = row
+	}
+	return details, nil
+}
+
+// fillGrantees sets the names and accounts of the recruiters of permissions
+func fillGrantees(ctx context.Context, permissions []models.ProfilePermission) error {
+	var ids []uint
+	for _, permission := range permissions {
+		if permission.GrantedTo != nil {
+			ids = append(ids, *permission.GrantedTo)
+		}
+	}
+	details, err := recruiterDetails(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range permissions {
+		if permissions[i].GrantedTo == nil {
+			continue
+		}
+		detail := details[*permissions[i].GrantedTo]
+		permissions[i].GranteeName = detail.Name
+		permissions[i].OrganizationName = detail.OrganizationName
+		permissions[i].Title = detail.Title
+	}
+	return nil
+}
+
+// ListProfilePermissions returns the requests and grants of a profile,
+// pending requests first
+func ListProfilePermissions(ctx context.Context, profileID uint) ([]models.ProfilePermission, error) {
+	permissions := []models.ProfilePermission{}
+	if err := database.DB.WithContext(ctx).Where("profile_id = ?", profileID).
+		Order("status, updated_at DESC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	if err := fillGrantees(ctx, permissions); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GrantProfilePermission grants access without a request. Public grants
+// need no user; the others go to a recruiter account of the granted type.
+// An existing request or grant of the user is approved with the new type.
+func GrantProfilePermission(ctx context.Context, profile *models.RecruitingProfile, grantorID uint, req *models.GrantProfilePermissionRequest) (*models.ProfilePermission, error) {
+	db := database.DB.WithContext(ctx)
+	var permission models.ProfilePermission
+	var err error
+	if req.PermissionType == models.ProfilePermissionPublic {
+		if req.UserID != nil {
+			return nil, ErrInvalidProfileGrant
+		}
+		err = db.Where("profile_id = ? AND granted_to IS NULL", profile.ID).First(&permission).Error
+	} else {
+		if req.UserID == nil {
+			return nil, ErrInvalidProfileGrant
+		}
+		account, accountErr := GetRecruiterAccount(ctx, *req.UserID)
+		if errors.Is(accountErr, ErrRecruiterAccountRequired) || (accountErr == nil && account.AccountType != req.PermissionType) {
+			return nil, ErrInvalidProfileGrant
+		}
+		if accountErr != nil {
+			return nil, accountErr
+		}
+		err = db.Where("profile_id = ? AND granted_to = ?", profile.ID, *req.UserID).First(&permission).Error
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		permission = models.ProfilePermission{ProfileID: profile.ID, GrantedTo: req.UserID}
+	case err != nil:
+		return nil, err
+	}
+
+	now := time.Now()
+	permission.PermissionType = req.PermissionType
+	permission.Status = models.ProfilePermissionApproved
+	permission.GrantedBy = &grantorID
+	permission.DecidedAt = &now
+	if err := db.Save(&permission).Error; err != nil {
+		return nil, err
+	}
+	permissions := []models.ProfilePermission{permission}
+	if err := fillGrantees(ctx, permissions); err != nil {
+		return nil, err
+	}
+	return &permissions[0], nil
+}
+
+// DecideProfileAccess approves or denies a pending request and lets the
+// recruiter know
+func DecideProfileAccess(ctx context.Context, permission *models.ProfilePermission, profile *models.RecruitingProfile, deciderID uint, approve bool) (*models.ProfilePermission, error) {
+	if permission.Status != models.ProfilePermissionPending {
+		return nil, ErrProfileAccessNotPending
+	}
+
+	db := database.DB.WithContext(ctx)
+	status, decision := models.ProfilePermissionDenied, "denied"
+	if approve {
+		status, decision = models.ProfilePermissionApproved, "approved"
+	}
+	// Claiming the request with a conditional update keeps two approvers
+	// from deciding it twice
+	result := db.Model(&models.ProfilePermission{}).
+		Where("id = ? AND status = ?", permission.ID, models.ProfilePermissionPending).
+		Updates(map[string]interface{}{"status": status, "granted_by": deciderID, "decided_at": time.Now()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrProfileAccessNotPending
+	}
+
+	updated, err := GetProfilePermission(ctx, permission.ID)
+	if err != nil {
+		return nil, err
+	}
+	if updated.GrantedTo != nil {
+		var player models.User
+		db.Select("id", "name").First(&player, profile.PlayerID)
+		data, _ := json.Marshal(map[string]interface{}{
+			"profile_id":    profile.ID,
+			"permission_id": updated.ID,
+		})
+		NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+			UserIDs: []uint{*updated.GrantedTo},
+			Type:    models.NotificationTypeProfileDecision,
+			Variables: map[string]string{
+				"player_name": player.Name,
+				"decision":    decision,
+			},
+			Data: data,
+		})
+	}
+	permissions := []models.ProfilePermission{*updated}
+	if err := fillGrantees(ctx, permissions); err != nil {
+		return nil, err
+	}
+	return &permissions[0], nil
+}
+
+// RevokeProfilePermission ends a grant or pending request. Recruiter access
+// is checked on every request, so the profile disappears from the
+// recruiter's directory right away.
+func RevokeProfilePermission(ctx context.Context, permission *models.ProfilePermission, revokerID uint) error {
+	if permission.Status != models.ProfilePermissionPending && permission.Status != models.ProfilePermissionApproved {
+		return ErrProfilePermissionNotActive
+	}
+	return database.DB.WithContext(ctx).Model(permission).Updates(map[string]interface{}{
+		"status":     models.ProfilePermissionRevoked,
+		"granted_by": revokerID,
+		"decided_at": time.Now(),
+	}).Error
+}
+
+// ListRecruiterViews returns the logged recruiter views of a profile, newest
+// first, with who viewed it
+func ListRecruiterViews(ctx context.Context, profileID uint, page, limit int) ([]models.RecruiterProfileView, int64, error) {
+	scope := func() *gorm.DB {
+		return database.DB.WithContext(ctx).Model(&models.RecruiterProfileView{}).Where("profile_id = ?", profileID)
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	views := []models.RecruiterProfileView{}
+	if err := scope().Order("viewed_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&views).Error; err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, len(views))
+	for i, view := range views {
+		ids[i] = view.RecruiterID
+	}
+	details, err := recruiterDetails(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range views {
+		detail := details[views[i].RecruiterID]
+		views[i].RecruiterName = detail.Name
+		views[i].AccountType = detail.AccountType
+		views[i].OrganizationName = detail.OrganizationName
+		views[i].Title = detail.Title
+	}
+	return views, total, nil
+}
+
+// RecruiterViewSummaries counts the views of each recruiter on a profile,
+// most recent first
+func RecruiterViewSummaries(ctx context.Context, profileID uint) ([]models.RecruiterViewSummary, error) {
+	summaries := []models.RecruiterViewSummary{}
+	err := database.DB.WithContext(ctx).Table("recruiter_profile_views").
+		Joins("JOIN users ON users.id = recruiter_profile_views.recruiter_id").
+		Joins("LEFT JOIN recruiter_accounts ON recruiter_accounts.user_id = recruiter_profile_views.recruiter_id").
+		Where("recruiter_profile_views.profile_id = ?", profileID).
+		Select("recruiter_profile_views.recruiter_id, users.name AS recruiter_name, recruiter_accounts.organization_name, " +
+			"COUNT(*) AS views, MAX(recruiter_profile_views.viewed_at) AS last_viewed_at").
+		Group("recruiter_profile_views.recruiter_id, users.name, recruiter_accounts.organization_name").
+		Order("last_viewed_at DESC").
+		Scan(&summaries).Error
+	return summaries, err
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+
+	"mobile-api-service/models"
+)
+
+type pageStat struct {
+	Label string
+	Value string
+}
+
+type pageSeason struct {
+	TeamName string
+	Games    int
+	Stats    []pageStat
+}
+
+type sharedProfilePage struct {
+	*models.PublicRecruitingProfile
+	SportLabel  string
+	Height      string
+	Measurables []pageStat
+	Seasons     []pageSeason
+}
+
+var sharedProfileTemplate = template.Must(template.New("profile").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>{{.PlayerName}} - Recruiting Profile</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;color:#212529;background:#f5f6f8;margin:0}
+main{max-width:860px;margin:0 auto;padding:24px}
+section{background:#fff;border-radius:8px;padding:20px;margin-bottom:16px}
+header{display:flex;gap:20px;align-items:center}
+header img{width:120px;height:120px;object-fit:cover;border-radius:50%}
+h1{margin:0 0 4px}h2{margin:0 0 12px;font-size:18px}
+.muted{color:#6c757d}
+dl{display:grid;grid-template-columns:max-content 1fr;gap:6px 16px;margin:0}dt{color:#6c757d}dd{margin:0}
+table{width:100%;border-collapse:collapse}th,td{text-align:left;padding:6px 8px;border-bottom:1px solid #dee2e6;font-size:14px}
+ul.clips{list-style:none;padding:0;display:grid;grid-template-columns:repeat(auto-fill,minmax(240px,1fr));gap:12px}
+ul.clips img{width:100%;border-radius:6px}
+</style>
+</head>
+<body>
+<main>
+<section>
+<header>
+{{if .ProfilePhotoURL}}<img src="{{.ProfilePhotoURL}}" alt="{{.PlayerName}}">{{end}}
+<div>
+<h1>{{.PlayerName}}</h1>
+<div class="muted">{{.SportLabel}}{{if .Position}} &middot; {{.Position}}{{end}}{{if .GraduationYear}} &middot; Class of {{.GraduationYear}}{{end}}</div>
+<div class="muted">{{if .Height}}{{.Height}}{{end}}{{if and .Height .Weight}} &middot; {{end}}{{if .Weight}}{{.Weight}} lb{{end}}</div>
+</div>
+</header>
+</section>
+{{if .Bio}}<section><h2>About</h2><p>{{.Bio}}</p></section>{{end}}
+{{if .Measurables}}<section><h2>Measurables</h2><dl>{{range .Measurables}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>{{end}}</dl></section>{{end}}
+{{if .Seasons}}<section><h2>Season Stats</h2>
+{{range .Seasons}}<h3>{{.TeamName}} <span class="muted">({{.Games}} games)</span></h3>
+<table><tr>{{range .Stats}}<th>{{.Label}}</th>{{end}}</tr><tr>{{range .Stats}}<td>{{.Value}}</td>{{end}}</tr></table>
+{{end}}</section>{{end}}
+{{if .Highlights}}<section><h2>Highlights</h2><ul class="clips">
+{{range .Highlights}}<li><a href="{{if .HLSURL}}{{.HLSURL}}{{else}}{{.StorageURL}}{{end}}">{{if .ThumbnailURL}}<img src="{{.ThumbnailURL}}" alt="">{{end}}{{if .Label}}{{.Label}}{{else}}{{.VideoTitle}}{{end}}</a></li>
+{{end}}</ul></section>{{end}}
+<p class="muted">Updated {{.UpdatedAt.Format "Jan 2, 2006"}}</p>
+</main>
+</body>
+</html>
+`))
+
+// formatHeight writes inches as feet and inches, e.g. 6'2"
+func formatHeight(inches *int) string {
+	if inches == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d'%d\"", *inches/12, *inches%12)
+}
+
+// profileMeasurables lists additional_info in the order of the sport's schema
+func profileMeasurables(sport string, info json.RawMessage) []pageStat {
+	schema, err := ProfileSchemaFor(sport)
+	if err != nil || len(info) == 0 {
+		return nil
+	}
+	var values map[string]interface{}
+	if json.Unmarshal(info, &values) != nil {
+		return nil
+	}
+	var out []pageStat
+	for _, field := range schema.Fields {
+		value, ok := values[field.Key]
+		if !ok {
+			continue
+		}
+		text := fmt.Sprint(value)
+		switch v := value.(type) {
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			text = "No"
+			if v {
+				text = "Yes"
+			}
+		}
+		if field.Unit != "" {
+			text += " " + field.Unit
+		}
+		out = append(out, pageStat{Label: field.Label, Value: text})
+	}
+	return out
+}
+
+// seasonPageStats picks the per-game averages and derived metrics of a season
+// line for the public page
+func seasonPageStats(line models.SeasonStatLine) []pageStat {
+	stats := []pageStat{}
+	definition, err := StatDefinitionFor(line.Sport)
+	if err != nil {
+		return stats
+	}
+	if usesColumns(definition) {
+		a := line.Averages
+		stats = append(stats,
+			pageStat{"PPG", formatOne(a.Points)},
+			pageStat{"RPG", formatOne(a.Rebounds)},
+			pageStat{"APG", formatOne(a.Assists)},
+			pageStat{"SPG", formatOne(a.Steals)},
+			pageStat{"BPG", formatOne(a.Blocks)},
+			pageStat{"FG%", formatPct(line.Advanced.FieldGoalPct)},
+		)
+		return stats
+	}
+	if line.SportStats == nil {
+		return stats
+	}
+	for _, field := range definition.Fields {
+		if field.Type == models.StatFieldBoolean || len(stats) >= 6 {
+			continue
+		}
+		stats = append(stats, pageStat{field.Label + " / game", strconv.FormatFloat(line.SportStats.Averages[field.Key], 'f', -1, 64)})
+	}
+	for i, formula := range definition.Derived {
+		if i >= 3 {
+			break
+		}
+		value := "-"
+		if v := line.SportStats.Derived[formula.Key]; v != nil {
+			value = strconv.FormatFloat(*v, 'f', formula.Decimals, 64)
+		}
+		stats = append(stats, pageStat{formula.Label, value})
+	}
+	return stats
+}
+
+// RenderSharedProfilePage writes the read-only HTML page of a shared profile
+func RenderSharedProfilePage(w io.Writer, profile *models.PublicRecruitingProfile) error {
+	page := sharedProfilePage{
+		PublicRecruitingProfile: profile,
+		SportLabel:              profile.Sport,
+		Height:                  formatHeight(profile.HeightInches),
+		Measurables:             profileMeasurables(profile.Sport, profile.AdditionalInfo),
+	}
+	if schema, err := ProfileSchemaFor(profile.Sport); err == nil {
+		page.SportLabel = schema.Label
+	}
+	for _, line := range profile.SeasonStats {
+		page.Seasons = append(page.Seasons, pageSeason{
+			TeamName: line.TeamName,
+			Games:    line.GamesPlayed,
+			Stats:    seasonPageStats(line),
+		})
+	}
+	return sharedProfileTemplate.Execute(w, page)
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProfileShareNotFound = errors.New("profile share not found")
+	ErrShareUnavailable     = errors.New("this profile link is no longer available")
+	ErrShareExpiryPast      = errors.New("expires_at must be in the future")
+)
+
+// publicProfileCacheTTL bounds how stale embedded season stats can get on
+// shared profiles; profile edits invalidate the cache right away
+const publicProfileCacheTTL = 5 * time.Minute
+
+// profileViewDedupeWindow is how long repeat visits of one visitor count as
+// a single view, from PROFILE_VIEW_DEDUPE_MINUTES
+func profileViewDedupeWindow() time.Duration {
+	minutes, err := strconv.Atoi(config.AppConfig.ProfileViewDedupeMinutes)
+	if err != nil || minutes < 1 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// setShareURLs fills the public page and JSON links of a share
+func setShareURLs(share *models.ProfileShare) {
+	base := strings.TrimRight(config.AppConfig.PublicAPIBaseURL, "/")
+	share.PageURL = base + "/share/" + share.ShareToken
+	share.JSONURL = base + "/api/shared-profiles/" + share.ShareToken
+}
+
+// CreateProfileShare generates a share link for the profile
+func CreateProfileShare(ctx context.Context, profile *models.RecruitingProfile, userID uint, req *models.CreateProfileShareRequest) (*models.ProfileShare, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrShareExpiryPast
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	share := &models.ProfileShare{
+		ProfileID:  profile.ID,
+		ShareToken: token,
+		Label:      req.Label,
+		ExpiresAt:  req.ExpiresAt,
+		IsActive:   true,
+		CreatedBy:  userID,
+	}
+	if err := database.DB.WithContext(ctx).Create(share).Error; err != nil {
+		return nil, err
+	}
+	setShareURLs(share)
+	return share, nil
+}
+
+// ListProfileShares returns the profile's shares, newest first
+func ListProfileShares(ctx context.Context, profileID uint) ([]models.ProfileShare, error) {
+	shares := []models.ProfileShare{}
+	if err := database.DB.WithContext(ctx).Where("profile_id = ?", profileID).
+		Order("created_at DESC").Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		setShareURLs(&shares[i])
+	}
+	return shares, nil
+}
+
+func GetProfileShare(ctx context.Context, shareID uint) (*models.ProfileShare, error) {
+	var share models.ProfileShare
+	err := database.DB.WithContext(ctx).First(&share, shareID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProfileShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	setShareURLs(&share)
+	return &share, nil
+}
+
+// UpdateProfileShare toggles a share or changes its label and expiry. Public
+// requests read the share from the database every time, so deactivating it
+// takes effect on the next request.
+func UpdateProfileShare(ctx context.Context, share *models.ProfileShare, req *models.UpdateProfileShareRequest) (*models.ProfileShare, error) {
+	updates := map[string]interface{}{}
+	if req.Label != nil {
+		updates["label"] = *req.Label
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.NeverExpires {
+		updates["expires_at"] = nil
+	} else if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, ErrShareExpiryPast
+		}
+		updates["expires_at"] = *req.ExpiresAt
+	}
+	if len(updates) > 0 {
+		if err := database.DB.WithContext(ctx).Model(share).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return GetProfileShare(ctx, share.ID)
+}
+
+// DeleteProfileShare removes a share and its view log
+func DeleteProfileShare(ctx context.Context, shareID uint) error {
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("share_id = ?", shareID).Delete(&models.ProfileView{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProfileShare{}, shareID).Error
+	})
+}
+
+// ResolveShare returns the share of a token with its profile when the link
+// is active, unexpired and the profile is published. It never reads a cache.
+func ResolveShare(ctx context.Context, token string) (*models.ProfileShare, *models.RecruitingProfile, error) {
+	db := database.DB.WithContext(ctx)
+	var share models.ProfileShare
+	if err := db.Where("share_token = ? AND is_active = ?", token, true).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrShareUnavailable
+		}
+		return nil, nil, err
+	}
+	if share.ExpiresAt != nil && !share.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrShareUnavailable
+	}
+
+	var profile models.RecruitingProfile
+	if err := db.Where("id = ? AND is_active = ?", share.ProfileID, true).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrShareUnavailable
+		}
+		return nil, nil, err
+	}
+	return &share, &profile, nil
+}
+
+func recruitingProfileVersionKey(profileID uint) string {
+	return fmt.Sprintf("recruiting:profile:%d:version", profileID)
+}
+
+// InvalidateRecruitingProfileCache drops the cached public view of a profile
+func InvalidateRecruitingProfileCache(ctx context.Context, profileID uint) {
+	if database.RedisClient == nil {
+		return
+	}
+	database.RedisClient.Incr(ctx, recruitingProfileVersionKey(profileID))
+}
+
+// GetPublicRecruitingProfile returns the shared view of a profile. Clip URLs
+// are not signed yet; the cache keeps them unsigned.
+func GetPublicRecruitingProfile(ctx context.Context, profile *models.RecruitingProfile) (*models.PublicRecruitingProfile, error) {
+	var cacheKey string
+	if database.RedisClient != nil {
+		version, _ := database.RedisClient.Get(ctx, recruitingProfileVersionKey(profile.ID)).Int64()
+		cacheKey = fmt.Sprintf("recruiting:profile:%d:v%d:public", profile.ID, version)
+		if data, err := database.RedisClient.Get(ctx, cacheKey).Bytes(); err == nil {
+			var cached models.PublicRecruitingProfile
+			if json.Unmarshal(data, &cached) == nil {
+				return &cached, nil
+			}
+		}
+	}
+
+	view, err := BuildRecruitingProfileView(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	public := &models.PublicRecruitingProfile{
+		ProfileID:       view.ID,
+		PlayerName:      view.PlayerName,
+		Sport:           view.Sport,
+		Bio:             view.Bio,
+		HeightInches:    view.HeightInches,
+		Weight:          view.Weight,
+		GraduationYear:  view.GraduationYear,
+		Position:        view.Position,
+		AdditionalInfo:  view.AdditionalInfo,
+		ProfilePhotoURL: view.ProfilePhotoURL,
+		SeasonStats:     view.SeasonStats,
+		Highlights:      view.Highlights,
+		UpdatedAt:       view.UpdatedAt,
+	}
+
+	if cacheKey != "" {
+		if data, err := json.Marshal(public); err == nil {
+			database.RedisClient.Set(ctx, cacheKey, data, publicProfileCacheTTL)
+		}
+	}
+	return public, nil
+}
+
+// ProfileVisitorHash identifies a visitor of a share without storing their address
+func ProfileVisitorHash(shareID uint, clientIP, userAgent string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%s|%s", shareID, clientIP, userAgent)))
+	return hex.EncodeToString(sum[:])
+}
+
+// firstVisitInWindow reports whether the visitor has not been counted on the
+// share within the dedupe window, claiming the window when Redis is available
+func firstVisitInWindow(ctx context.Context, shareID uint, visitorHash string) (bool, error) {
+	window := profileViewDedupeWindow()
+	if database.RedisClient != nil {
+		key := fmt.Sprintf("recruiting:share:%d:visitor:%s", shareID, visitorHash)
+		first, err := database.RedisClient.SetNX(ctx, key, 1, window).Result()
+		if err == nil {
+			return first, nil
+		}
+		log.Printf("Profile view dedupe fell back to the database: %v", err)
+	}
+
+	var count int64
+	err := database.DB.WithContext(ctx).Model(&models.ProfileView{}).
+		Where("share_id = ? AND visitor_hash = ? AND viewed_at > ?", shareID, visitorHash, time.Now().Add(-window)).
+		Count(&count).Error
+	return count == 0, err
+}
+
+// RecordProfileView logs a view of the share with its referrer and counts it,
+// unless the same visitor was already counted within the dedupe window
+func RecordProfileView(ctx context.Context, share *models.ProfileShare, visitorHash, referrer string) error {
+	first, err := firstVisitInWindow(ctx, share.ID, visitorHash)
+	if err != nil || !first {
+		return err
+	}
+
+	if len(referrer) > 500 {
+		referrer = referrer[:500]
+	}
+	view := &models.ProfileView{
+		ShareID:     share.ID,
+		VisitorHash: visitorHash,
+		Referrer:    referrer,
+		ViewedAt:    time.Now(),
+	}
+	if parsed, err := url.Parse(referrer); err == nil && parsed.Hostname() != "" {
+		view.ReferrerHost = strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	}
+
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(view).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ProfileShare{}).Where("id = ?", share.ID).
+			UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+	})
+}
+
+// ListProfileViews returns the counted views of a share, newest first
+func ListProfileViews(ctx context.Context, shareID uint, page, limit int) ([]models.ProfileView, int64, error) {
+	scope := func() *gorm.DB {
+		return database.DB.WithContext(ctx).Model(&models.ProfileView{}).Where("share_id = ?", shareID)
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	views := []models.ProfileView{}
+	err := scope().Order("viewed_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&views).Error
+	return views, total, err
+}
+
+// ProfileReferrers groups a share's views by referring site, most views
+// first; direct visits have an empty host
+func ProfileReferrers(ctx context.Context, shareID uint) ([]models.ProfileReferrer, error) {
+	referrers := []models.ProfileReferrer{}
+	err := database.DB.WithContext(ctx).Model(&models.ProfileView{}).
+		Select("referrer_host, COUNT(*) AS views, MAX(viewed_at) AS last_viewed_at").
+		Where("share_id = ?", shareID).
+		Group("referrer_host").
+		Order("views DESC, last_viewed_at DESC").
+		Scan(&referrers).Error
+	return referrers, err
+}
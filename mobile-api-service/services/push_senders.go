@@ -0,0 +1,369 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidDeviceToken is returned by a PushSender when the provider rejects
+// the token as unknown or expired; the device should be deactivated
+var ErrInvalidDeviceToken = errors.New("invalid device token")
+
+// PushMessage is the provider-neutral payload of a push notification
+type PushMessage struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// PushSender delivers a push notification to one device token
+type PushSender interface {
+	Push(ctx context.Context, token string, msg *PushMessage) error
+}
+
+var pushHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// FCMSender sends through the Firebase Cloud Messaging HTTP v1 API
+type FCMSender struct {
+	BaseURL   string
+	ProjectID string
+	// TokenSource returns the OAuth2 access token for the request
+	TokenSource func(ctx context.Context) (string, error)
+	Client      *http.Client
+}
+
+func (s *FCMSender) Push(ctx context.Context, token string, msg *PushMessage) error {
+	payload := map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", strings.TrimRight(s.BaseURL, "/"), s.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.TokenSource != nil {
+		accessToken, err := s.TokenSource(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var fcmErr struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &fcmErr)
+
+	// Only UNREGISTERED identifies the token itself; INVALID_ARGUMENT and a
+	// bare 404 also come from a malformed payload or a wrong project ID and
+	// must not deactivate every device
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ErrInvalidDeviceToken
+		}
+	}
+
+	err = fmt.Errorf("fcm: %s: %s", resp.Status, fcmErr.Error.Message)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
+
+func (s *FCMSender) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return pushHTTPClient
+}
+
+// fcmScope is the OAuth2 scope of the FCM HTTP v1 API
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// ServiceAccountTokenSource exchanges a signed service account assertion
+// for OAuth2 access tokens, refreshing them a minute before they expire
+type ServiceAccountTokenSource struct {
+	ClientEmail string
+	PrivateKey  *rsa.PrivateKey
+	KeyID       string
+	TokenURL    string
+	Scope       string
+	Client      *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewServiceAccountTokenSourceFromFile loads the JSON key of a Google service account
+func NewServiceAccountTokenSourceFromFile(path string) (*ServiceAccountTokenSource, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var key struct {
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, err
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return &ServiceAccountTokenSource{
+		ClientEmail: key.ClientEmail,
+		PrivateKey:  privateKey,
+		KeyID:       key.PrivateKeyID,
+		TokenURL:    key.TokenURI,
+		Scope:       fcmScope,
+	}, nil
+}
+
+// Token returns a valid access token, fetching a new one when the cached
+// token is missing or about to expire
+func (s *ServiceAccountTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiresAt.Add(-time.Minute)) {
+		return s.token, nil
+	}
+
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.ClientEmail,
+		"scope": s.Scope,
+		"aud":   s.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if s.KeyID != "" {
+		assertion.Header["kid"] = s.KeyID
+	}
+	signed, err := assertion.SignedString(s.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := s.Client
+	if client == nil {
+		client = pushHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &body)
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return "", fmt.Errorf("fcm token: %s: %s", resp.Status, body.Error)
+	}
+
+	s.token = body.AccessToken
+	s.expiresAt = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// APNsSender sends through the Apple Push Notification service HTTP/2 API
+type APNsSender struct {
+	BaseURL string
+	Topic   string
+	KeyID   string
+	TeamID  string
+	Key     *ecdsa.PrivateKey
+	Client  *http.Client
+
+	mu          sync.Mutex
+	bearer      string
+	bearerIssue time.Time
+}
+
+// NewAPNsSenderFromFile loads the .p8 signing key downloaded from the Apple developer portal
+func NewAPNsSenderFromFile(baseURL, keyPath, keyID, teamID, topic string) (*APNsSender, error) {
+	pem, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, err
+	}
+	return &APNsSender{BaseURL: baseURL, Topic: topic, KeyID: keyID, TeamID: teamID, Key: key}, nil
+}
+
+// providerToken returns the signed provider JWT, reusing it for up to 50 minutes as Apple requires
+func (s *APNsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bearer != "" && time.Since(s.bearerIssue) < 50*time.Minute {
+		return s.bearer, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.KeyID
+	signed, err := token.SignedString(s.Key)
+	if err != nil {
+		return "", err
+	}
+	s.bearer, s.bearerIssue = signed, now
+	return signed, nil
+}
+
+func (s *APNsSender) Push(ctx context.Context, token string, msg *PushMessage) error {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/3/device/%s", strings.TrimRight(s.BaseURL, "/"), token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apns-topic", s.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	if s.Key != nil {
+		bearer, err := s.providerToken()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "bearer "+bearer)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = pushHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &apnsErr)
+
+	// DeviceTokenNotForTopic and the like point at our configuration, not
+	// at the device
+	switch apnsErr.Reason {
+	case "BadDeviceToken", "Unregistered":
+		return ErrInvalidDeviceToken
+	}
+
+	err = fmt.Errorf("apns: %s: %s", resp.Status, apnsErr.Reason)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
+
+// LogPushSender is the local stand-in used when a provider is not configured
+type LogPushSender struct {
+	Provider string
+}
+
+func (s LogPushSender) Push(ctx context.Context, token string, msg *PushMessage) error {
+	log.Printf("[%s] push to %s: %s", s.Provider, truncateToken(token), msg.Title)
+	return nil
+}
+
+func truncateToken(token string) string {
+	if len(token) > 12 {
+		return token[:12] + "..."
+	}
+	return token
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testPush = &PushMessage{Title: "Game day", Body: "Report at 5pm", Data: map[string]string{"event_id": "7"}}
+
+// pushOutcome classifies a Push error the way PushChannelSender and the
+// dispatcher do
+func pushOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "sent"
+	case errors.Is(err, ErrInvalidDeviceToken):
+		return "invalid token"
+	case isPermanent(err):
+		return "permanent"
+	}
+	return "retry"
+}
+
+func TestFCMSender(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"sent", http.StatusOK, `{"name":"projects/p/messages/1"}`, "sent"},
+		{"unregistered token", http.StatusNotFound,
+			`{"error":{"status":"NOT_FOUND","message":"Requested entity was not found.","details":[{"errorCode":"UNREGISTERED"}]}}`, "invalid token"},
+		{"malformed payload", http.StatusBadRequest,
+			`{"error":{"status":"INVALID_ARGUMENT","message":"Invalid value","details":[{"errorCode":"INVALID_ARGUMENT"}]}}`, "permanent"},
+		{"wrong project", http.StatusNotFound, `{"error":{"status":"NOT_FOUND","message":"Project not found"}}`, "permanent"},
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"status":"RESOURCE_EXHAUSTED"}}`, "retry"},
+		{"unavailable", http.StatusServiceUnavailable, `{"error":{"status":"UNAVAILABLE"}}`, "retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/projects/demo/messages:send" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer access-1" {
+					t.Errorf("Authorization = %q", got)
+				}
+				var payload struct {
+					Message struct {
+						Token        string            `json:"token"`
+						Notification map[string]string `json:"notification"`
+						Data         map[string]string `json:"data"`
+					} `json:"message"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Errorf("decode payload: %v", err)
+				}
+				if payload.Message.Token != "device-1" || payload.Message.Notification["title"] != "Game day" || payload.Message.Data["event_id"] != "7" {
+					t.Errorf("payload = %+v", payload.Message)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			sender := &FCMSender{
+				BaseURL:     server.URL,
+				ProjectID:   "demo",
+				TokenSource: func(ctx context.Context) (string, error) { return "access-1", nil },
+				Client:      server.Client(),
+			}
+			if got := pushOutcome(sender.Push(context.Background(), "device-1", testPush)); got != tt.want {
+				t.Errorf("Push() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPNsSender(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		reason string
+		want   string
+	}{
+		{"sent", http.StatusOK, "", "sent"},
+		{"bad device token", http.StatusBadRequest, "BadDeviceToken", "invalid token"},
+		{"unregistered", http.StatusGone, "Unregistered", "invalid token"},
+		{"wrong topic", http.StatusBadRequest, "DeviceTokenNotForTopic", "permanent"},
+		{"bad provider token", http.StatusForbidden, "InvalidProviderToken", "permanent"},
+		{"server error", http.StatusInternalServerError, "InternalServerError", "retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/3/device/device-1" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if got := r.Header.Get("apns-topic"); got != "com.example.app" {
+					t.Errorf("apns-topic = %q", got)
+				}
+				bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+				token, err := jwt.Parse(bearer, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+				if err != nil || token.Header["kid"] != "KEY123" {
+					t.Errorf("provider token = %v, %v", token, err)
+				}
+				var payload map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&payload)
+				if payload["event_id"] != "7" || payload["aps"] == nil {
+					t.Errorf("payload = %v", payload)
+				}
+				w.WriteHeader(tt.status)
+				if tt.reason != "" {
+					w.Write([]byte(`{"reason":"` + tt.reason + `"}`))
+				}
+			}))
+			defer server.Close()
+
+			sender := &APNsSender{
+				BaseURL: server.URL,
+				Topic:   "com.example.app",
+				KeyID:   "KEY123",
+				TeamID:  "TEAM123",
+				Key:     key,
+				Client:  server.Client(),
+			}
+			if got := pushOutcome(sender.Push(context.Background(), "device-1", testPush)); got != tt.want {
+				t.Errorf("Push() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceAccountTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+	expiresIn := 3600
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("grant_type = %q", r.PostForm.Get("grant_type"))
+		}
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		}); err != nil {
+			t.Errorf("assertion: %v", err)
+		}
+		if claims["iss"] != "push@demo.iam.gserviceaccount.com" || claims["scope"] != fcmScope {
+			t.Errorf("claims = %v", claims)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-" + string(rune('0'+n)),
+			"expires_in":   expiresIn,
+		})
+	}))
+	defer server.Close()
+
+	source := &ServiceAccountTokenSource{
+		ClientEmail: "push@demo.iam.gserviceaccount.com",
+		PrivateKey:  key,
+		TokenURL:    server.URL,
+		Scope:       fcmScope,
+		Client:      server.Client(),
+	}
+	ctx := context.Background()
+
+	first, err := source.Token(ctx)
+	if err != nil || first != "access-1" {
+		t.Fatalf("Token() = %q, %v", first, err)
+	}
+	if again, _ := source.Token(ctx); again != "access-1" || atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("cached Token() = %q after %d requests, want access-1 after 1", again, requests)
+	}
+
+	// A token within a minute of expiring is refreshed
+	source.expiresAt = time.Now().Add(30 * time.Second)
+	if refreshed, _ := source.Token(ctx); refreshed != "access-2" {
+		t.Errorf("refreshed Token() = %q, want access-2", refreshed)
+	}
+}
+
+func TestServiceAccountTokenSourceError(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer server.Close()
+
+	source := &ServiceAccountTokenSource{PrivateKey: key, TokenURL: server.URL, Client: server.Client()}
+	if _, err := source.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Token() error = %v, want invalid_grant", err)
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"mobile-api-service/models"
+)
+
+var ErrRecruitingSportNotSupported = errors.New("no recruiting profile schema for this sport")
+
+func measureField(key, label, unit string, min, max float64) models.ProfileField {
+	return models.ProfileField{Key: key, Label: label, Type: models.StatFieldDecimal, Unit: unit, Min: statBound(min), Max: statBound(max)}
+}
+
+func countMeasureField(key, label string, max float64) models.ProfileField {
+	return models.ProfileField{Key: key, Label: label, Type: models.StatFieldInteger, Min: statBound(0), Max: statBound(max)}
+}
+
+func choiceField(key, label string, options ...string) models.ProfileField {
+	return models.ProfileField{Key: key, Label: label, Type: models.ProfileFieldChoice, Options: options}
+}
+
+func textField(key, label string, maxLength int) models.ProfileField {
+	return models.ProfileField{Key: key, Label: label, Type: models.ProfileFieldText, MaxLength: maxLength}
+}
+
+// profileSchemas is the registry of additional_info fields, keyed by
+// normalized sport like the stat definitions, so recruiters see the same
+// measurables on every profile of a sport
+var profileSchemas = map[string]*models.SportProfileSchema{
+	SportBasketball: {
+		Sport: SportBasketball,
+		Label: "Basketball",
+		Fields: []models.ProfileField{
+			measureField("wingspan_inches", "Wingspan", "in", 48, 108),
+			measureField("standing_reach_inches", "Standing reach", "in", 60, 120),
+			measureField("vertical_leap_inches", "Vertical leap", "in", 0, 60),
+			measureField("lane_agility_seconds", "Lane agility", "s", 8, 20),
+			choiceField("dominant_hand", "Dominant hand", "left", "right", "both"),
+			textField("club_team", "Club team", 255),
+		},
+	},
+	SportVolleyball: {
+		Sport: SportVolleyball,
+		Label: "Volleyball",
+		Fields: []models.ProfileField{
+			measureField("standing_reach_inches", "Standing reach", "in", 60, 120),
+			measureField("approach_touch_inches", "Approach touch", "in", 80, 150),
+			measureField("block_touch_inches", "Block touch", "in", 80, 150),
+			measureField("vertical_leap_inches", "Vertical leap", "in", 0, 60),
+			choiceField("dominant_hand", "Dominant hand", "left", "right", "both"),
+			textField("club_team", "Club team", 255),
+		},
+	},
+	SportSoccer: {
+		Sport: SportSoccer,
+		Label: "Soccer",
+		Fields: []models.ProfileField{
+			choiceField("dominant_foot", "Dominant foot", "left", "right", "both"),
+			measureField("sprint_40_yard_seconds", "40-yard sprint", "s", 3.5, 10),
+			measureField("vertical_leap_inches", "Vertical leap", "in", 0, 60),
+			measureField("beep_test_level", "Beep test level", "", 0, 21),
+			textField("club_team", "Club team", 255),
+		},
+	},
+	SportFootball: {
+		Sport: SportFootball,
+		Label: "Football",
+		Fields: []models.ProfileField{
+			measureField("forty_yard_dash_seconds", "40-yard dash", "s", 3.8, 8),
+			measureField("shuttle_seconds", "5-10-5 shuttle", "s", 3, 8),
+			measureField("vertical_leap_inches", "Vertical leap", "in", 0, 60),
+			countMeasureField("broad_jump_inches", "Broad jump", 150),
+			countMeasureField("bench_press_reps", "Bench press reps (225 lb)", 60),
+			measureField("wingspan_inches", "Wingspan", "in", 48, 108),
+			textField("club_team", "7-on-7 team", 255),
+		},
+	},
+}
+
+// ProfileSchemaFor returns the additional_info fields of a sport
+func ProfileSchemaFor(sport string) (*models.SportProfileSchema, error) {
+	schema, ok := profileSchemas[NormalizeSport(sport)]
+	if !ok {
+		return nil, ErrRecruitingSportNotSupported
+	}
+	return schema, nil
+}
+
+// ProfileSchemas lists the schema of every sport
+func ProfileSchemas() []*models.SportProfileSchema {
+	list := make([]*models.SportProfileSchema, 0, len(profileSchemas))
+	for _, schema := range profileSchemas {
+		list = append(list, schema)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Sport < list[j].Sport })
+	return list
+}
+
+// ValidateProfileInfo checks additional_info against the sport's schema: only
+// the schema's fields, each with the right type and within its bounds
+func ValidateProfileInfo(schema *models.SportProfileSchema, info json.RawMessage) []string {
+	var raw map[string]interface{}
+	if len(info) > 0 {
+		if err := json.Unmarshal(info, &raw); err != nil {
+			return []string{"additional_info must be a JSON object"}
+		}
+	}
+	fields := make(map[string]models.ProfileField, len(schema.Fields))
+	for _, field := range schema.Fields {
+		fields[field.Key] = field
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var problems []string
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not a %s profile field", key, schema.Label))
+			continue
+		}
+		if problem := profileFieldProblem(field, raw[key]); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+func profileFieldProblem(field models.ProfileField, raw interface{}) string {
+	switch field.Type {
+	case models.ProfileFieldText:
+		text, ok := raw.(string)
+		if !ok {
+			return fmt.Sprintf("%s must be text", field.Key)
+		}
+		if field.MaxLength > 0 && len([]rune(text)) > field.MaxLength {
+			return fmt.Sprintf("%s must be at most %d characters", field.Key, field.MaxLength)
+		}
+		return ""
+	case models.ProfileFieldChoice:
+		text, ok := raw.(string)
+		for _, option := range field.Options {
+			if ok && text == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s must be one of %s", field.Key, strings.Join(field.Options, ", "))
+	case models.StatFieldBoolean:
+		if _, ok := raw.(bool); !ok {
+			return fmt.Sprintf("%s must be true or false", field.Key)
+		}
+		return ""
+	}
+
+	value, ok := raw.(float64)
+	if !ok {
+		return fmt.Sprintf("%s must be a number", field.Key)
+	}
+	if field.Type == models.StatFieldInteger && value != math.Trunc(value) {
+		return fmt.Sprintf("%s must be a whole number", field.Key)
+	}
+	if field.Min != nil && value < *field.Min {
+		return fmt.Sprintf("%s must be at least %g", field.Key, *field.Min)
+	}
+	if field.Max != nil && value > *field.Max {
+		return fmt.Sprintf("%s must be at most %g", field.Key, *field.Max)
+	}
+	return ""
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRecruitingProfileNotFound = errors.New("recruiting profile not found")
+	ErrRecruitingSportRequired   = errors.New("sport is required to create a recruiting profile")
+	ErrHighlightNotAllowed       = errors.New("highlights must be clips of this player on videos you can watch")
+	ErrDateOfBirthLocked         = errors.New("only a parent, coach or OrgAdmin can change the player's date of birth")
+)
+
+// ProfileInfoValidationError lists why additional_info does not match the
+// sport's schema; nothing is saved in that case
+type ProfileInfoValidationError struct {
+	Problems []string
+}
+
+func (e *ProfileInfoValidationError) Error() string {
+	return fmt.Sprintf("additional_info has %d invalid fields", len(e.Problems))
+}
+
+// IsMinor reports whether the player of a profile is under 18. Without a
+// date of birth the player is treated as a minor, so their parents keep
+// editing the profile until an adult date of birth is recorded. Only parents,
+// coaches and OrgAdmins record it; the player cannot make themselves an adult.
+func IsMinor(profile *models.RecruitingProfile) bool {
+	if profile == nil || profile.DateOfBirth == nil {
+		return true
+	}
+	return profile.DateOfBirth.AddDate(18, 0, 0).After(time.Now())
+}
+
+// GetRecruitingProfile returns the player's profile
+func GetRecruitingProfile(ctx context.Context, playerID uint) (*models.RecruitingProfile, error) {
+	var profile models.RecruitingProfile
+	err := database.DB.WithContext(ctx).Where("player_id = ?", playerID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecruitingProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetRecruitingProfileByID returns a profile by its own ID
+func GetRecruitingProfileByID(ctx context.Context, profileID uint) (*models.RecruitingProfile, error) {
+	var profile models.RecruitingProfile
+	err := database.DB.WithContext(ctx).First(&profile, profileID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecruitingProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveRecruitingProfile creates the player's profile or updates the given
+// fields. additional_info is replaced as a whole and always validated against
+// the schema of the profile's sport, also when only the sport changes. The
+// player may resend the stored date of birth but not change it.
+func SaveRecruitingProfile(ctx context.Context, playerID, userID uint, req *models.SaveRecruitingProfileRequest) (*models.RecruitingProfile, error) {
+	profile, err := GetRecruitingProfile(ctx, playerID)
+	switch {
+	case errors.Is(err, ErrRecruitingProfileNotFound):
+		if req.Sport == nil {
+			return nil, ErrRecruitingSportRequired
+		}
+		profile = &models.RecruitingProfile{PlayerID: playerID, EmbedSeasonStats: true, IsActive: true}
+	case err != nil:
+		return nil, err
+	}
+
+	var dateOfBirth *time.Time
+	if req.DateOfBirth != nil {
+		dateOfBirth = parseGoalDate(*req.DateOfBirth)
+		if userID == playerID && !sameDate(profile.DateOfBirth, dateOfBirth) {
+			return nil, ErrDateOfBirthLocked
+		}
+	}
+
+	if req.Sport != nil {
+		profile.Sport = NormalizeSport(*req.Sport)
+	}
+	schema, err := ProfileSchemaFor(profile.Sport)
+	if err != nil {
+		return nil, err
+	}
+	if req.AdditionalInfo != nil {
+		info, err := normalizeJSON(req.AdditionalInfo)
+		if err != nil || (info != nil && info[0] != '{') {
+			return nil, &ProfileInfoValidationError{Problems: []string{"additional_info must be a JSON object"}}
+		}
+		profile.AdditionalInfo = info
+	}
+	if problems := ValidateProfileInfo(schema, profile.AdditionalInfo); len(problems) > 0 {
+		return nil, &ProfileInfoValidationError{Problems: problems}
+	}
+
+	if req.Bio != nil {
+		profile.Bio = *req.Bio
+	}
+	if req.HeightInches != nil {
+		profile.HeightInches = req.HeightInches
+	}
+	if req.Weight != nil {
+		profile.Weight = req.Weight
+	}
+	if req.GraduationYear != nil {
+		profile.GraduationYear = req.GraduationYear
+	}
+	if req.Position != nil {
+		profile.Position = *req.Position
+	}
+	if req.DateOfBirth != nil {
+		profile.DateOfBirth = dateOfBirth
+	}
+	if req.ProfilePhotoURL != nil {
+		profile.ProfilePhotoURL = *req.ProfilePhotoURL
+	}
+	if req.EmbedSeasonStats != nil {
+		profile.EmbedSeasonStats = *req.EmbedSeasonStats
+	}
+	if req.IsActive != nil {
+		profile.IsActive = *req.IsActive
+	}
+	profile.UpdatedBy = userID
+
+	if err := database.DB.WithContext(ctx).Save(profile).Error; err != nil {
+		return nil, err
+	}
+	InvalidateRecruitingProfileCache(ctx, profile.ID)
+	return profile, nil
+}
+
+// SetProfileDateOfBirth records the player's date of birth, which decides
+// whether their parents still control the profile
+func SetProfileDateOfBirth(ctx context.Context, profile *models.RecruitingProfile, userID uint, value string) error {
+	profile.DateOfBirth = parseGoalDate(value)
+	profile.UpdatedBy = userID
+	if err := database.DB.WithContext(ctx).Model(profile).Select("date_of_birth", "updated_by").
+		Updates(profile).Error; err != nil {
+		return err
+	}
+	InvalidateRecruitingProfileCache(ctx, profile.ID)
+	return nil
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format(goalDateLayout) == b.Format(goalDateLayout)
+}
+
+// BuildRecruitingProfileView adds the player's name, their season lines in
+// the profile's sport when embedded, and the highlight clips. Clip URLs are
+// not signed yet.
+func BuildRecruitingProfileView(ctx context.Context, profile *models.RecruitingProfile) (*models.RecruitingProfileView, error) {
+	db := database.DB.WithContext(ctx)
+	view := &models.RecruitingProfileView{
+		RecruitingProfile: *profile,
+		SeasonStats:       []models.SeasonStatLine{},
+	}
+
+	var player models.User
+	if err := db.Select("id", "name").First(&player, profile.PlayerID).Error; err != nil {
+		return nil, err
+	}
+	view.PlayerName = player.Name
+
+	if profile.EmbedSeasonStats {
+		var rows []models.PlayerSeasonStat
+		if err := db.Where("player_id = ?", profile.PlayerID).Order("season_id, team_id").Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		var sportRows []models.PlayerSeasonStat
+		for _, row := range rows {
+			if NormalizeSport(row.Sport) == profile.Sport {
+				sportRows = append(sportRows, row)
+			}
+		}
+		lines, err := seasonStatLines(db, sportRows)
+		if err != nil {
+			return nil, err
+		}
+		view.SeasonStats = lines
+	}
+
+	highlights, err := ListProfileHighlights(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	view.Highlights = highlights
+	return view, nil
+}
+
+// ListProfileHighlights returns the profile's clips in display order. They
+// are shown to everyone who can see the profile: choosing a clip shares it.
+func ListProfileHighlights(ctx context.Context, profileID uint) ([]models.PlayerClip, error) {
+	clips := []models.PlayerClip{}
+	err := database.DB.WithContext(ctx).Table("profile_highlights").
+		Joins("JOIN video_tags ON video_tags.id = profile_highlights.video_tag_id").
+		Joins("JOIN videos ON videos.id = video_tags.video_id AND videos.deleted_at IS NULL").
+		Joins("JOIN teams ON teams.id = videos.team_id").
+		Where("profile_highlights.profile_id = ?", profileID).
+		Select("video_tags.*, videos.title AS video_title, videos.team_id, teams.season_id, videos.event_id, videos.storage_url, videos.thumbnail_url, videos.uploaded_at").
+		Order("profile_highlights.sort_order, profile_highlights.id").
+		Scan(&clips).Error
+	return clips, err
+}
+
+// SetProfileHighlights replaces the profile's highlights with the given tags,
+// in order. Each tag must be of the profile's player on a video the editor
+// can watch.
+func SetProfileHighlights(ctx context.Context, profile *models.RecruitingProfile, viewer VideoViewer, tagIDs []uint) ([]models.PlayerClip, error) {
+	db := database.DB.WithContext(ctx)
+	var ordered []uint
+	seen := make(map[uint]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			ordered = append(ordered, id)
+		}
+	}
+
+	if len(ordered) > 0 {
+		var tags []models.VideoTag
+		if err := db.Where("id IN ?", ordered).Find(&tags).Error; err != nil {
+			return nil, err
+		}
+		if len(tags) != len(ordered) {
+			return nil, ErrHighlightNotAllowed
+		}
+		checked := map[uint]bool{}
+		for _, tag := range tags {
+			if tag.PlayerID != profile.PlayerID {
+				return nil, ErrHighlightNotAllowed
+			}
+			if _, done := checked[tag.VideoID]; !done {
+				checked[tag.VideoID] = CanViewVideo(ctx, viewer, tag.VideoID)
+			}
+			if !checked[tag.VideoID] {
+				return nil, ErrHighlightNotAllowed
+			}
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("profile_id = ?", profile.ID).Delete(&models.ProfileHighlight{}).Error; err != nil {
+			return err
+		}
+		if len(ordered) == 0 {
+			return nil
+		}
+		highlights := make([]models.ProfileHighlight, len(ordered))
+		for i, id := range ordered {
+			highlights[i] = models.ProfileHighlight{ProfileID: profile.ID, VideoTagID: id, SortOrder: i}
+		}
+		return tx.Create(&highlights).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	InvalidateRecruitingProfileCache(ctx, profile.ID)
+	return ListProfileHighlights(ctx, profile.ID)
+}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"mobile-api-service/models"
+	"mobile-api-service/pdf"
+)
+
+// Layout of report card pages, in points
+const (
+	reportMargin     = 48.0
+	reportFooter     = 36.0
+	reportLineHeight = 14.0
+)
+
+var (
+	reportTextColor  = [3]uint8{33, 37, 41}
+	reportMutedColor = [3]uint8{108, 117, 125}
+	reportRuleColor  = [3]uint8{222, 226, 230}
+	reportAlertColor = [3]uint8{176, 42, 55}
+)
+
+// reportWriter lays report card content out top to bottom, starting a new
+// page whenever the next block does not fit
+type reportWriter struct {
+	doc      *pdf.Document
+	page     *pdf.Page
+	branding *ReportBranding
+	card     *models.ReportCard
+	y        float64
+	width    float64
+}
+
+// RenderReportCard writes the report card as a PDF
+func RenderReportCard(card *models.ReportCard, branding *ReportBranding, out io.Writer) error {
+	doc := pdf.New(pdf.Letter)
+	doc.SetInfo("Title", fmt.Sprintf("Report card - %s", card.PlayerName))
+	doc.SetInfo("Author", branding.Organization.Name)
+
+	w := &reportWriter{doc: doc, branding: branding, card: card, width: doc.Size().Width - 2*reportMargin}
+	w.newPage()
+	w.header()
+	w.statsSection()
+	w.effortSection()
+	w.attendanceSection()
+	w.notesSection()
+	w.academicSection()
+	w.goalsSection()
+	w.footers()
+
+	_, err := doc.WriteTo(out)
+	return err
+}
+
+func (w *reportWriter) newPage() {
+	w.page = w.doc.AddPage()
+	w.y = reportMargin
+	accent := w.branding.Accent
+	w.page.SetFillColor(accent[0], accent[1], accent[2])
+	w.page.Rect(0, 0, w.doc.Size().Width, 6, true)
+}
+
+// ensure starts a new page unless height fits above the footer
+func (w *reportWriter) ensure(height float64) {
+	if w.y+height > w.doc.Size().Height-reportMargin-reportFooter {
+		w.newPage()
+	}
+}
+
+func (w *reportWriter) color(c [3]uint8) {
+	w.page.SetFillColor(c[0], c[1], c[2])
+}
+
+func (w *reportWriter) header() {
+	left := reportMargin
+	if logo := w.branding.Logo; logo != nil {
+		height := 56.0
+		width := height * float64(logo.Width) / float64(logo.Height)
+		if width > 140 {
+			width, height = 140, 140*float64(logo.Height)/float64(logo.Width)
+		}
+		w.page.Image(logo, reportMargin, w.y, width, height)
+		left += width + 16
+	}
+
+	w.color(w.branding.Accent)
+	w.page.SetFont(pdf.HelveticaBold, 11)
+	w.page.Text(left, w.y+14, w.branding.Organization.Name)
+	w.color(reportTextColor)
+	w.page.SetFont(pdf.HelveticaBold, 20)
+	w.page.Text(left, w.y+38, "Player Report Card")
+	w.color(reportMutedColor)
+	w.page.SetFont(pdf.Helvetica, 10)
+	w.page.Text(left, w.y+54, fmt.Sprintf("%s - %s", w.card.From.Format("Jan 2, 2006"), w.card.To.Format("Jan 2, 2006")))
+	w.y += 72
+
+	w.rule()
+	name := w.card.PlayerName
+	if w.card.JerseyNumber != nil {
+		name = fmt.Sprintf("#%d %s", *w.card.JerseyNumber, name)
+	}
+	w.color(reportTextColor)
+	w.page.SetFont(pdf.HelveticaBold, 16)
+	w.page.Text(reportMargin, w.y+18, name)
+	team := w.card.Team.Name
+	if w.card.Team.Division != "" {
+		team += " - " + w.card.Team.Division
+	}
+	w.color(reportMutedColor)
+	w.page.SetFont(pdf.Helvetica, 10)
+	w.page.TextRight(reportMargin+w.width, w.y+18, team)
+	w.y += 30
+}
+
+func (w *reportWriter) rule() {
+	w.page.SetStrokeColor(reportRuleColor[0], reportRuleColor[1], reportRuleColor[2])
+	w.page.SetLineWidth(0.75)
+	w.page.Line(reportMargin, w.y, reportMargin+w.width, w.y)
+	w.y += 8
+}
+
+// section writes a heading; it keeps the heading with at least one line of content
+func (w *reportWriter) section(title string) {
+	w.ensure(30 + 2*reportLineHeight)
+	w.y += 10
+	accent := w.branding.Accent
+	w.page.SetFillColor(accent[0], accent[1], accent[2])
+	w.page.Rect(reportMargin, w.y, w.width, 20, true)
+	w.page.SetFillColor(255, 255, 255)
+	w.page.SetFont(pdf.HelveticaBold, 11)
+	w.page.Text(reportMargin+8, w.y+14, title)
+	w.y += 28
+}
+
+// paragraph writes wrapped text indented by indent
+func (w *reportWriter) paragraph(font pdf.Font, size float64, c [3]uint8, indent float64, text string) {
+	for _, line := range pdf.WrapText(font, size, w.width-indent, text) {
+		w.ensure(reportLineHeight)
+		w.color(c)
+		w.page.SetFont(font, size)
+		w.page.Text(reportMargin+indent, w.y+size, line)
+		w.y += reportLineHeight
+	}
+}
+
+func (w *reportWriter) empty(text string) {
+	w.paragraph(pdf.Helvetica, 10, reportMutedColor, 0, text)
+}
+
+// table writes rows of cells in equal columns; the first row is the header
+func (w *reportWriter) table(rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	columns := len(rows[0])
+	cellWidth := w.width / float64(columns)
+	for i, row := range rows {
+		w.ensure(reportLineHeight + 4)
+		font := pdf.Helvetica
+		if i == 0 {
+			font = pdf.HelveticaBold
+			w.page.SetFillColor(245, 246, 248)
+			w.page.Rect(reportMargin, w.y-2, w.width, reportLineHeight+2, true)
+		}
+		w.page.SetFont(font, 9)
+		w.color(reportTextColor)
+		for j, cell := range row {
+			if cell == "" {
+				continue
+			}
+			x := reportMargin + float64(j)*cellWidth + 4
+			lines := pdf.WrapText(font, 9, cellWidth-8, cell)
+			if len(lines) > 0 {
+				w.page.Text(x, w.y+9, lines[0])
+			}
+		}
+		w.y += reportLineHeight + 2
+	}
+	w.y += 4
+}
+
+func formatPct(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*v, 'f', 1, 64) + "%"
+}
+
+func formatOne(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+func (w *reportWriter) statsSection() {
+	w.section("Statistics")
+	stats := w.card.Stats
+	if stats == nil {
+		w.empty("No games recorded in this period.")
+		return
+	}
+	w.paragraph(pdf.Helvetica, 10, reportTextColor, 0, fmt.Sprintf("%d games played, %d minutes", stats.GamesPlayed, stats.MinutesPlayed))
+	w.y += 4
+	totals, avg := stats.Totals, stats.Averages
+	w.table([][]string{
+		{"", "PTS", "REB", "AST", "STL", "BLK", "TO", "PF"},
+		{"Total", strconv.Itoa(totals.Points), strconv.Itoa(totals.Rebounds), strconv.Itoa(totals.Assists),
+			strconv.Itoa(totals.Steals), strconv.Itoa(totals.Blocks), strconv.Itoa(totals.Turnovers), strconv.Itoa(totals.Fouls)},
+		{"Per game", formatOne(avg.Points), formatOne(avg.Rebounds), formatOne(avg.Assists),
+			formatOne(avg.Steals), formatOne(avg.Blocks), formatOne(avg.Turnovers), formatOne(avg.Fouls)},
+	})
+	advanced := stats.Advanced
+	w.table([][]string{
+		{"FG%", "3P%", "FT%", "eFG%", "TS%"},
+		{formatPct(advanced.FieldGoalPct), formatPct(advanced.ThreePointPct), formatPct(advanced.FreeThrowPct),
+			formatPct(advanced.EffectiveFieldGoalPct), formatPct(advanced.TrueShootingPct)},
+	})
+}
+
+func (w *reportWriter) effortSection() {
+	w.section("Effort & Buy-in")
+	trends := []models.ScoreTrend{w.card.Hustle, w.card.Engagement, w.card.BuyIn}
+	rated := false
+	rows := [][]string{{"Metric", "Rated events", "Average", "Recent average", "Latest", "Trend"}}
+	for _, trend := range trends {
+		if len(trend.Points) == 0 {
+			continue
+		}
+		rated = true
+		average, rolling, latest := "-", "-", "-"
+		if trend.Average != nil {
+			average = formatOne(*trend.Average)
+		}
+		if trend.RollingAverage != nil {
+			rolling = formatOne(*trend.RollingAverage)
+		}
+		if trend.Latest != nil {
+			latest = strconv.Itoa(*trend.Latest)
+		}
+		streak := "Steady"
+		switch trend.Streak.Direction {
+		case "up":
+			streak = fmt.Sprintf("Up %d in a row", trend.Streak.Length)
+		case "down":
+			streak = fmt.Sprintf("Down %d in a row", trend.Streak.Length)
+		}
+		rows = append(rows, []string{scoreMetricLabels[trend.Metric], strconv.Itoa(len(trend.Points)), average, rolling, latest, streak})
+	}
+	if !rated {
+		w.empty("No effort or buy-in scores in this period.")
+		return
+	}
+	w.table(rows)
+	for _, trend := range trends {
+		for _, alert := range trend.Alerts {
+			w.paragraph(pdf.Helvetica, 9, reportAlertColor, 0, "! "+alert.Message)
+		}
+	}
+}
+
+func (w *reportWriter) attendanceSection() {
+	w.section("Attendance")
+	attendance := w.card.Attendance
+	if attendance.Events == 0 {
+		w.empty("No team events in this period.")
+		return
+	}
+	w.table([][]string{
+		{"Events", "Attending", "Not attending", "Maybe", "No response", "Rate"},
+		{strconv.Itoa(attendance.Events), strconv.Itoa(attendance.Attending), strconv.Itoa(attendance.NotAttending),
+			strconv.Itoa(attendance.Maybe), strconv.Itoa(attendance.NoResponse), formatPct(attendance.Rate)},
+	})
+}
+
+func (w *reportWriter) notesSection() {
+	w.section("Leadership Notes")
+	if len(w.card.Notes) == 0 {
+		w.empty("No shared leadership notes in this period.")
+		return
+	}
+	for _, note := range w.card.Notes {
+		heading := fmt.Sprintf("%s - score %d/10", note.CreatedAt.Format("Jan 2, 2006"), note.Score)
+		if note.CoachName != "" {
+			heading += " - " + note.CoachName
+		}
+		w.ensure(2 * reportLineHeight)
+		w.paragraph(pdf.HelveticaBold, 9, reportTextColor, 0, heading)
+		w.paragraph(pdf.Helvetica, 10, reportTextColor, 10, note.Comment)
+		w.y += 4
+	}
+}
+
+func (w *reportWriter) academicSection() {
+	w.section("Academics")
+	if eligibility := w.card.Eligibility; eligibility != nil {
+		c := reportTextColor
+		if eligibility.Status == models.EligibilityIneligible {
+			c = reportAlertColor
+		}
+		status := "Currently " + models.EligibilityStatusName(eligibility.Status)
+		var reasons []string
+		if json.Unmarshal(eligibility.Reasons, &reasons) == nil && len(reasons) > 0 {
+			status += ": " + strings.Join(reasons, "; ")
+		}
+		w.paragraph(pdf.HelveticaBold, 10, c, 0, status)
+		w.y += 4
+	}
+	if len(w.card.Academics) == 0 {
+		w.empty("No academic entries in this period.")
+		return
+	}
+	rows := [][]string{{"Term", "GPA", "Failing grades", "Entered"}}
+	for _, entry := range w.card.Academics {
+		gpa, failing := "-", "-"
+		if entry.GPA != nil {
+			gpa = strconv.FormatFloat(*entry.GPA, 'f', 2, 64)
+		}
+		if entry.FailingGrades != nil {
+			failing = strconv.Itoa(*entry.FailingGrades)
+		}
+		rows = append(rows, []string{entry.Term, gpa, failing, entry.CreatedAt.Format("Jan 2, 2006")})
+	}
+	w.table(rows)
+}
+
+func goalStatusName(goal models.LifeGoal) string {
+	switch goal.Status {
+	case models.GoalStatusCompleted:
+		return "Completed"
+	case models.GoalStatusPaused:
+		return "Paused"
+	}
+	return "Active"
+}
+
+func (w *reportWriter) goalsSection() {
+	w.section("Goals")
+	if len(w.card.Goals) == 0 {
+		w.empty("No goals in this period.")
+		return
+	}
+	for _, goal := range w.card.Goals {
+		details := []string{goalStatusName(goal)}
+		if goal.Progress != nil {
+			details = append(details, fmt.Sprintf("%d%% progress", *goal.Progress))
+		}
+		if len(goal.Milestones) > 0 {
+			done := 0
+			for _, milestone := range goal.Milestones {
+				if milestone.CompletedAt != nil {
+					done++
+				}
+			}
+			details = append(details, fmt.Sprintf("%d/%d milestones", done, len(goal.Milestones)))
+		}
+		if goal.TargetDate != nil && goal.Status != models.GoalStatusCompleted {
+			details = append(details, "target "+goal.TargetDate.Format("Jan 2, 2006"))
+		}
+		w.ensure(2 * reportLineHeight)
+		w.paragraph(pdf.HelveticaBold, 10, reportTextColor, 0, goal.Title)
+		w.paragraph(pdf.Helvetica, 9, reportMutedColor, 10, strings.Join(details, " - "))
+		w.y += 4
+	}
+}
+
+// footers numbers the pages once the page count is known
+func (w *reportWriter) footers() {
+	total := w.doc.PageCount()
+	pageHeight := w.doc.Size().Height
+	for i := 1; i <= total; i++ {
+		page := w.doc.Page(i)
+		page.SetFillColor(reportMutedColor[0], reportMutedColor[1], reportMutedColor[2])
+		page.SetFont(pdf.Helvetica, 8)
+		page.Text(reportMargin, pageHeight-reportMargin+12, fmt.Sprintf("Generated %s", w.card.GeneratedAt.Format("Jan 2, 2006")))
+		page.TextRight(reportMargin+w.width, pageHeight-reportMargin+12, fmt.Sprintf("Page %d of %d", i, total))
+	}
+}
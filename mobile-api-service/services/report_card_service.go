@@ -0,0 +1,380 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/pdf"
+	"mobile-api-service/storage"
+)
+
+var ErrReportPlayerNotOnTeam = errors.New("player has never been on this team")
+
+// maxLogoBytes bounds the organization logo downloaded for report branding
+const maxLogoBytes = 4 << 20
+
+var errLogoAddress = errors.New("logo URL must be a public http(s) address")
+
+// logoHTTPClient only connects to public addresses, so an organization's
+// logo URL cannot reach the services on our own network. The check runs on
+// the resolved address of every connection, redirects included, and no proxy
+// is used since it would connect on our behalf.
+var logoHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+					return errLogoAddress
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 3 {
+			return errors.New("too many logo redirects")
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return errLogoAddress
+		}
+		return nil
+	},
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private like RFC 1918
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP rejects loopback, private, link-local (cloud metadata),
+// unspecified and multicast addresses
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified() && !sharedAddressSpace.Contains(ip)
+}
+
+// ReportBranding is an organization's name, logo and the accent color taken from the logo
+type ReportBranding struct {
+	Organization models.Organization
+	Logo         *pdf.Image
+	Accent       [3]uint8
+}
+
+// defaultAccent is used when the organization has no usable logo
+var defaultAccent = [3]uint8{30, 58, 95}
+
+// LoadReportBranding loads the organization of a team with its logo. A logo
+// that cannot be fetched or decoded is skipped rather than failing the report.
+func LoadReportBranding(ctx context.Context, organizationID uint) (*ReportBranding, error) {
+	branding := &ReportBranding{Accent: defaultAccent}
+	if err := database.DB.WithContext(ctx).Unscoped().First(&branding.Organization, organizationID).Error; err != nil {
+		return nil, err
+	}
+	if branding.Organization.LogoURL == "" {
+		return branding, nil
+	}
+
+	data, err := fetchLogo(ctx, branding.Organization.LogoURL)
+	if err != nil {
+		log.Printf("Report logo of organization %d skipped: %v", organizationID, err)
+		return branding, nil
+	}
+	logo, err := pdf.LoadImage(data)
+	if err != nil {
+		log.Printf("Report logo of organization %d skipped: %v", organizationID, err)
+		return branding, nil
+	}
+	branding.Logo = logo
+	if decoded, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+		branding.Accent = accentColor(decoded)
+	}
+	return branding, nil
+}
+
+// fetchLogo reads a logo from media storage when it is one of ours, over HTTP otherwise
+func fetchLogo(ctx context.Context, url string) ([]byte, error) {
+	if key, ok := storage.Default.ObjectKey(url); ok {
+		reader, err := storage.Default.Open(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return readLogo(reader)
+	}
+	return downloadLogo(ctx, url)
+}
+
+// downloadLogo fetches a logo from a public http(s) URL
+func downloadLogo(ctx context.Context, rawURL string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return nil, errLogoAddress
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := logoHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("logo download returned %s", resp.Status)
+	}
+	return readLogo(resp.Body)
+}
+
+// readLogo reads and closes body, up to maxLogoBytes
+func readLogo(body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, maxLogoBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxLogoBytes {
+		return nil, errors.New("logo is too large")
+	}
+	return data, nil
+}
+
+// accentColor averages the saturated, visible pixels of a logo and darkens
+// the result until white text is readable on it
+func accentColor(img image.Image) [3]uint8 {
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/64)
+	var r, g, b, n float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			cr, cg, cb, ca := img.At(x, y).RGBA()
+			if ca < 0x8000 {
+				continue
+			}
+			fr, fg, fb := float64(cr)/0xFFFF, float64(cg)/0xFFFF, float64(cb)/0xFFFF
+			if max(fr, fg, fb)-min(fr, fg, fb) < 0.2 {
+				continue
+			}
+			r, g, b, n = r+fr, g+fg, b+fb, n+1
+		}
+	}
+	if n == 0 {
+		return defaultAccent
+	}
+	r, g, b = r/n, g/n, b/n
+	for 0.2126*r+0.7152*g+0.0722*b > 0.45 {
+		r, g, b = r*0.85, g*0.85, b*0.85
+	}
+	return [3]uint8{uint8(r * 255), uint8(g * 255), uint8(b * 255)}
+}
+
+// BuildReportCard gathers a player's development on a team within a date range
+func BuildReportCard(ctx context.Context, playerID uint, team *models.Team, organization models.Organization, from, to time.Time) (*models.ReportCard, error) {
+	db := database.DB.WithContext(ctx)
+
+	var member models.TeamMember
+	if err := db.Where("team_id = ? AND user_id = ? AND member_type = ?", team.ID, playerID, models.MemberTypePlayer).
+		First(&member).Error; err != nil {
+		return nil, ErrReportPlayerNotOnTeam
+	}
+	var player models.User
+	if err := db.Select("id", "name").First(&player, playerID).Error; err != nil {
+		return nil, err
+	}
+
+	card := &models.ReportCard{
+		PlayerID:     playerID,
+		PlayerName:   player.Name,
+		JerseyNumber: member.JerseyNumber,
+		Team:         *team,
+		Organization: organization,
+		From:         from,
+		To:           to,
+		GeneratedAt:  time.Now(),
+		Notes:        []models.LeadershipNote{},
+		Academics:    []models.AcademicEntry{},
+		Goals:        []models.LifeGoal{},
+	}
+
+	// Stats of the games in the range
+	var totals models.PlayerSeasonStat
+	if err := db.Table("game_stats").
+		Select("COUNT(*) AS games_played, "+sumCountColumns()+", "+
+			"COALESCE(SUM(game_stats.minutes_played), 0) AS minutes_played, COUNT(game_stats.minutes_played) AS minutes_games, "+
+			"COALESCE(SUM(game_stats.plus_minus), 0) AS plus_minus, COUNT(game_stats.plus_minus) AS plus_minus_games").
+		Joins("JOIN events ON events.id = game_stats.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ? AND game_stats.player_id = ? AND events.start_time BETWEEN ? AND ?", team.ID, playerID, from, to).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	if totals.GamesPlayed > 0 {
+		summary := summarize(totals.GamesPlayed, totals.GameStatCounts, totals.MinutesPlayed, totals.MinutesGames, totals.PlusMinus, totals.PlusMinusGames)
+		card.Stats = &summary
+	}
+
+	// Effort and buy-in trends over the rated events in the range
+	type ratedEvent struct {
+		EventID   uint
+		Type      uint8
+		Title     string
+		StartTime time.Time
+		Score     int
+		Second    int
+	}
+	var efforts, buyIns []ratedEvent
+	if err := db.Table("effort_metrics").
+		Select("events.id AS event_id, events.type, events.title, events.start_time, effort_metrics.hustle_score AS score, effort_metrics.engagement_score AS second").
+		Joins("JOIN events ON events.id = effort_metrics.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ? AND effort_metrics.player_id = ? AND events.start_time BETWEEN ? AND ?", team.ID, playerID, from, to).
+		Order("events.start_time").Scan(&efforts).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("buy_in_scores").
+		Select("events.id AS event_id, events.type, events.title, events.start_time, buy_in_scores.score").
+		Joins("JOIN events ON events.id = buy_in_scores.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ? AND buy_in_scores.player_id = ? AND events.start_time BETWEEN ? AND ?", team.ID, playerID, from, to).
+		Order("events.start_time").Scan(&buyIns).Error; err != nil {
+		return nil, err
+	}
+	var hustle, engagement, buyIn []models.ScorePoint
+	for _, e := range efforts {
+		point := models.ScorePoint{EventID: e.EventID, EventType: e.Type, Title: e.Title, StartTime: e.StartTime, Score: e.Score}
+		hustle = append(hustle, point)
+		point.Score = e.Second
+		engagement = append(engagement, point)
+	}
+	for _, e := range buyIns {
+		buyIn = append(buyIn, models.ScorePoint{EventID: e.EventID, EventType: e.Type, Title: e.Title, StartTime: e.StartTime, Score: e.Score})
+	}
+	card.Hustle = analyzeScores(models.ScoreMetricHustle, hustle, 3)
+	card.Engagement = analyzeScores(models.ScoreMetricEngagement, engagement, 3)
+	card.BuyIn = analyzeScores(models.ScoreMetricBuyIn, buyIn, 3)
+
+	// Attendance from RSVPs to the team's past events
+	events, err := playerTeamEvents(ctx, playerID, []uint{team.ID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		eventIDs := make([]uint, len(events))
+		for i, event := range events {
+			eventIDs[i] = event.ID
+		}
+		var rsvps []models.EventRSVP
+		if err := db.Where("user_id = ? AND event_id IN ?", playerID, eventIDs).Find(&rsvps).Error; err != nil {
+			return nil, err
+		}
+		attendance := &card.Attendance
+		attendance.Events = len(events)
+		for _, rsvp := range rsvps {
+			switch rsvp.Status {
+			case models.RSVPAttending:
+				attendance.Attending++
+			case models.RSVPNotAttending:
+				attendance.NotAttending++
+			case models.RSVPMaybe:
+				attendance.Maybe++
+			}
+		}
+		attendance.NoResponse = attendance.Events - attendance.Attending - attendance.NotAttending - attendance.Maybe
+		rate := round1(float64(attendance.Attending) / float64(attendance.Events) * 100)
+		attendance.Rate = &rate
+	}
+
+	// Only notes already shared with the player or their parents
+	if err := db.Where("player_id = ? AND team_id = ? AND visibility IN (?, ?) AND created_at BETWEEN ? AND ?",
+		playerID, team.ID, models.NoteVisibilityPlayer, models.NoteVisibilityParents, from, to).
+		Order("created_at").Find(&card.Notes).Error; err != nil {
+		return nil, err
+	}
+	fillCoachNames(ctx, card.Notes)
+
+	// Terms that ended in the range, oldest first
+	if err := db.Where("player_id = ? AND COALESCE(term_end_date, created_at) BETWEEN ? AND ?", playerID, from, to).
+		Order("COALESCE(term_end_date, created_at), id").Find(&card.Academics).Error; err != nil {
+		return nil, err
+	}
+	var eligibility models.PlayerEligibility
+	if err := db.Where("player_id = ? AND organization_id = ?", playerID, team.OrganizationID).
+		Limit(1).Find(&eligibility).Error; err != nil {
+		return nil, err
+	}
+	if eligibility.ID != 0 {
+		card.Eligibility = &eligibility
+	}
+
+	// Goals that were open at some point of the range
+	goals, err := ListLifeGoals(ctx, playerID, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, goal := range goals {
+		if goal.CreatedAt.After(to) || (goal.CompletedAt != nil && goal.CompletedAt.Before(from)) {
+			continue
+		}
+		card.Goals = append(card.Goals, goal)
+	}
+	return card, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ReportCardFileName names the PDF of a report card
+func ReportCardFileName(card *models.ReportCard) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(card.PlayerName, "-"), "-")
+	if name == "" {
+		name = fmt.Sprintf("player-%d", card.PlayerID)
+	}
+	if card.JerseyNumber != nil {
+		name = fmt.Sprintf("%02d-%s", *card.JerseyNumber, name)
+	}
+	return fmt.Sprintf("report-card-%s-%s.pdf", name, card.To.Format("2006-01-02"))
+}
+
+// WriteTeamReportCards writes a zip with the report card of every active
+// player on the team
+func WriteTeamReportCards(ctx context.Context, team *models.Team, branding *ReportBranding, from, to time.Time, out io.Writer) (int, error) {
+	roster, err := teamRoster(ctx, team.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	archive := zip.NewWriter(out)
+	written := 0
+	for _, player := range roster {
+		card, err := BuildReportCard(ctx, player.UserID, team, branding.Organization, from, to)
+		if err != nil {
+			return written, err
+		}
+		entry, err := archive.Create(ReportCardFileName(card))
+		if err != nil {
+			return written, err
+		}
+		if err := RenderReportCard(card, branding, entry); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, archive.Close()
+}
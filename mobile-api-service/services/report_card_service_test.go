@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.10", false},
+		{"100.64.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadLogoRejectsInternalAddresses(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("\x89PNG"))
+	}))
+	defer server.Close()
+
+	urls := []string{
+		server.URL + "/logo.png",
+		"http://localhost:" + strconv.Itoa(server.Listener.Addr().(*net.TCPAddr).Port) + "/logo.png",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/logo.png",
+		"http://[::1]/logo.png",
+		"file:///etc/passwd",
+		"ftp://example.com/logo.png",
+		"gopher://example.com/",
+		"/relative/logo.png",
+	}
+	for _, url := range urls {
+		if _, err := downloadLogo(context.Background(), url); !errors.Is(err, errLogoAddress) {
+			t.Errorf("downloadLogo(%q) error = %v, want %v", url, err, errLogoAddress)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("server received %d requests, want 0", requests)
+	}
+}
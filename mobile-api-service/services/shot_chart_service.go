@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrShotChartBasketballOnly = errors.New("shot charts are only available for basketball")
+	ErrShotNotFound            = errors.New("shot not found")
+)
+
+// shotCellSize is the side of a heat-map grid square, in feet
+const shotCellSize = 5.0
+
+// A three inside the shortest (high school) arc or a two beyond the longest
+// one was charted at the wrong spot
+const (
+	minThreeDistance = 19.0
+	maxTwoDistance   = 24.5
+)
+
+// Shot zones, in the order charts list them
+const (
+	ZoneRestrictedArea        = "restricted_area"
+	ZonePaint                 = "paint"
+	ZoneMidRangeLeft          = "mid_range_left"
+	ZoneMidRangeCenter        = "mid_range_center"
+	ZoneMidRangeRight         = "mid_range_right"
+	ZoneCornerThreeLeft       = "corner_three_left"
+	ZoneCornerThreeRight      = "corner_three_right"
+	ZoneAboveBreakThreeLeft   = "above_break_three_left"
+	ZoneAboveBreakThreeCenter = "above_break_three_center"
+	ZoneAboveBreakThreeRight  = "above_break_three_right"
+)
+
+// shotZones describes each zone with the point where the app places its label
+var shotZones = []models.ShotZoneStat{
+	{Zone: ZoneRestrictedArea, Label: "Restricted area", PointValue: 2, X: 0, Y: 5.25},
+	{Zone: ZonePaint, Label: "Paint", PointValue: 2, X: 0, Y: 14},
+	{Zone: ZoneMidRangeLeft, Label: "Left mid-range", PointValue: 2, X: -13, Y: 10},
+	{Zone: ZoneMidRangeCenter, Label: "Center mid-range", PointValue: 2, X: 0, Y: 21},
+	{Zone: ZoneMidRangeRight, Label: "Right mid-range", PointValue: 2, X: 13, Y: 10},
+	{Zone: ZoneCornerThreeLeft, Label: "Left corner three", PointValue: 3, X: -23, Y: 7},
+	{Zone: ZoneCornerThreeRight, Label: "Right corner three", PointValue: 3, X: 23, Y: 7},
+	{Zone: ZoneAboveBreakThreeLeft, Label: "Left wing three", PointValue: 3, X: -17, Y: 24},
+	{Zone: ZoneAboveBreakThreeCenter, Label: "Top of the key three", PointValue: 3, X: 0, Y: 29},
+	{Zone: ZoneAboveBreakThreeRight, Label: "Right wing three", PointValue: 3, X: 17, Y: 24},
+}
+
+var shotTypeLabels = map[uint8]string{
+	models.ShotJumpShot: "Jump shot",
+	models.ShotLayup:    "Layup",
+	models.ShotDunk:     "Dunk",
+	models.ShotHook:     "Hook shot",
+	models.ShotTipIn:    "Tip-in",
+	models.ShotFloater:  "Floater",
+}
+
+// basketDistance is the distance of a court spot to the basket, in feet
+func basketDistance(x, y float64) float64 {
+	return math.Hypot(x, y-models.BasketY)
+}
+
+// ShotZoneOf buckets a shot. The charted point value decides between twos and
+// threes since the arc differs between levels; threes below the break (y ≤
+// 14) are corner threes. Everything else is split into left, center and
+// right by the angle to the basket, center being within 30° of the lane.
+func ShotZoneOf(x, y float64, pointValue uint8) string {
+	dx, dy := x, y-models.BasketY
+	central := dy > 0 && math.Atan2(math.Abs(dx), dy) <= math.Pi/6
+	side := func(left, center, right string) string {
+		switch {
+		case central:
+			return center
+		case dx < 0:
+			return left
+		default:
+			return right
+		}
+	}
+
+	if pointValue == 3 {
+		if y <= 14 {
+			if dx < 0 {
+				return ZoneCornerThreeLeft
+			}
+			return ZoneCornerThreeRight
+		}
+		return side(ZoneAboveBreakThreeLeft, ZoneAboveBreakThreeCenter, ZoneAboveBreakThreeRight)
+	}
+	if basketDistance(x, y) <= 4 {
+		return ZoneRestrictedArea
+	}
+	if math.Abs(x) <= 8 && y <= 19 {
+		return ZonePaint
+	}
+	return side(ZoneMidRangeLeft, ZoneMidRangeCenter, ZoneMidRangeRight)
+}
+
+// shotChartTeam checks that the team plays basketball
+func shotChartTeam(ctx context.Context, teamID uint) error {
+	definition, err := TeamStatDefinition(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if !usesColumns(definition) {
+		return ErrShotChartBasketballOnly
+	}
+	return nil
+}
+
+// RecordGameShots saves the charted shots of a game. A client_id that was
+// already recorded updates that shot, so a device can re-send its queue.
+// Nothing is saved when any shot is invalid.
+func RecordGameShots(ctx context.Context, event *models.Event, userID uint, inputs []models.GameShotInput) error {
+	if err := shotChartTeam(ctx, event.TeamID); err != nil {
+		return err
+	}
+	roster, err := TeamPlayerIDs(ctx, event.TeamID)
+	if err != nil {
+		return err
+	}
+	clientIDs := make([]string, len(inputs))
+	for i := range inputs {
+		clientIDs[i] = inputs[i].ClientID
+	}
+	var existing []models.GameShot
+	if err := database.DB.WithContext(ctx).Select("id", "client_id", "player_id").
+		Where("event_id = ? AND client_id IN ?", event.ID, clientIDs).Find(&existing).Error; err != nil {
+		return err
+	}
+	allowed := make(map[uint]bool, len(roster)+len(existing))
+	for _, id := range roster {
+		allowed[id] = true
+	}
+	// Players already charted stay valid after leaving the roster
+	affected := make(map[uint]bool)
+	for _, shot := range existing {
+		allowed[shot.PlayerID] = true
+		affected[shot.PlayerID] = true
+	}
+
+	var rows []GameStatRowError
+	seen := make(map[string]bool, len(inputs))
+	shots := make([]models.GameShot, len(inputs))
+	for i := range inputs {
+		in := &inputs[i]
+		var problems []string
+		if !allowed[in.PlayerID] {
+			problems = append(problems, "player is not on the team roster")
+		}
+		if seen[in.ClientID] {
+			problems = append(problems, "client_id appears more than once")
+		}
+		seen[in.ClientID] = true
+		distance := basketDistance(*in.X, *in.Y)
+		if in.PointValue == 3 && distance < minThreeDistance {
+			problems = append(problems, "a three-pointer must be charted behind the arc")
+		}
+		if in.PointValue == 2 && distance > maxTwoDistance {
+			problems = append(problems, "a two-pointer must be charted inside the arc")
+		}
+		if len(problems) > 0 {
+			rows = append(rows, GameStatRowError{Index: i, PlayerID: in.PlayerID, Errors: problems})
+			continue
+		}
+
+		affected[in.PlayerID] = true
+		shots[i] = models.GameShot{
+			EventID:      event.ID,
+			ClientID:     in.ClientID,
+			TeamID:       event.TeamID,
+			PlayerID:     in.PlayerID,
+			X:            round1(*in.X),
+			Y:            round1(*in.Y),
+			ShotType:     in.ShotType,
+			PointValue:   in.PointValue,
+			Made:         in.Made,
+			Period:       in.Period,
+			ClockSeconds: *in.ClockSeconds,
+			Zone:         ShotZoneOf(*in.X, *in.Y, in.PointValue),
+			RecordedBy:   userID,
+		}
+	}
+	if len(rows) > 0 {
+		return &GameStatValidationError{Rows: rows}
+	}
+
+	if err := database.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "event_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"player_id", "x", "y", "shot_type", "point_value", "made",
+			"period", "clock_seconds", "zone", "recorded_by", "updated_at",
+		}),
+	}).Create(&shots).Error; err != nil {
+		return err
+	}
+
+	playerIDs := make([]uint, 0, len(affected))
+	for id := range affected {
+		playerIDs = append(playerIDs, id)
+	}
+	InvalidateStatsCache(ctx, event.TeamID, playerIDs)
+	return nil
+}
+
+// ListGameShots returns the shots of a game in game order
+func ListGameShots(ctx context.Context, eventID uint) ([]models.GameShot, error) {
+	shots := []models.GameShot{}
+	err := database.DB.WithContext(ctx).Where("event_id = ?", eventID).
+		Order("period, clock_seconds DESC, id").Find(&shots).Error
+	return shots, err
+}
+
+// DeleteGameShot removes a shot charted by mistake
+func DeleteGameShot(ctx context.Context, event *models.Event, shotID uint) error {
+	var shot models.GameShot
+	if err := database.DB.WithContext(ctx).Where("event_id = ?", event.ID).First(&shot, shotID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrShotNotFound
+		}
+		return err
+	}
+	if err := database.DB.WithContext(ctx).Delete(&shot).Error; err != nil {
+		return err
+	}
+	InvalidateStatsCache(ctx, event.TeamID, []uint{shot.PlayerID})
+	return nil
+}
+
+// ShotChartFilter selects the shots of a chart: a team's season (optionally
+// one player or one game) or a player's shots (optionally one team or season)
+type ShotChartFilter struct {
+	TeamID   uint
+	PlayerID uint
+	SeasonID uint
+	EventID  uint
+}
+
+// GetShotChart buckets the matching shots into court zones, shot types and a
+// heat-map grid. Team charts are cached with the team's stats, player charts
+// with the player's.
+func GetShotChart(ctx context.Context, filter ShotChartFilter) (*models.ShotChart, error) {
+	var cacheKey string
+	if database.RedisClient != nil {
+		variant := fmt.Sprintf("shots:t%d:p%d:s%d:e%d", filter.TeamID, filter.PlayerID, filter.SeasonID, filter.EventID)
+		if filter.TeamID != 0 {
+			cacheKey = statsCacheKey(ctx, "team", filter.TeamID, variant)
+		} else {
+			cacheKey = statsCacheKey(ctx, "player", filter.PlayerID, variant)
+		}
+		var cached models.ShotChart
+		if readStatsCache(ctx, cacheKey, &cached) {
+			return &cached, nil
+		}
+	}
+
+	db := database.DB.WithContext(ctx)
+	scope := func() *gorm.DB {
+		query := db.Table("game_shots").
+			Joins("JOIN events ON events.id = game_shots.event_id AND events.deleted_at IS NULL").
+			Joins("JOIN teams ON teams.id = game_shots.team_id")
+		if filter.TeamID != 0 {
+			query = query.Where("game_shots.team_id = ?", filter.TeamID)
+		}
+		if filter.PlayerID != 0 {
+			query = query.Where("game_shots.player_id = ?", filter.PlayerID)
+		}
+		if filter.SeasonID != 0 {
+			query = query.Where("teams.season_id = ?", filter.SeasonID)
+		}
+		if filter.EventID != 0 {
+			query = query.Where("game_shots.event_id = ?", filter.EventID)
+		}
+		return query
+	}
+
+	var games int64
+	if err := scope().Distinct("game_shots.event_id").Count(&games).Error; err != nil {
+		return nil, err
+	}
+	type bucket struct {
+		Zone       string
+		ShotType   uint8
+		PointValue uint8
+		CellCol    int
+		CellRow    int
+		Attempts   int
+		Made       int
+	}
+	var buckets []bucket
+	cells := fmt.Sprintf("LEAST(FLOOR((game_shots.x + %[1]g) / %[2]g), %[3]d) AS cell_col, LEAST(FLOOR(game_shots.y / %[2]g), %[3]d) AS cell_row",
+		models.CourtHalfWidth, shotCellSize, int(2*models.CourtHalfWidth/shotCellSize)-1)
+	if err := scope().
+		Select("game_shots.zone, game_shots.shot_type, game_shots.point_value, " + cells +
+			", COUNT(*) AS attempts, SUM(game_shots.made) AS made").
+		Group("game_shots.zone, game_shots.shot_type, game_shots.point_value, cell_col, cell_row").
+		Scan(&buckets).Error; err != nil {
+		return nil, err
+	}
+
+	chart := &models.ShotChart{
+		TeamID:   filter.TeamID,
+		PlayerID: filter.PlayerID,
+		SeasonID: filter.SeasonID,
+		EventID:  filter.EventID,
+		Games:    int(games),
+		Zones:    make([]models.ShotZoneStat, len(shotZones)),
+		CellSize: shotCellSize,
+		Cells:    []models.ShotHeatCell{},
+	}
+	copy(chart.Zones, shotZones)
+	zoneIndex := make(map[string]int, len(shotZones))
+	for i, zone := range shotZones {
+		zoneIndex[zone.Zone] = i
+	}
+	zonePoints := make([]int, len(shotZones))
+	cellIndex := make(map[[2]int]int)
+	typeIndex := make(map[uint8]int)
+	for _, b := range buckets {
+		chart.Attempts += b.Attempts
+		chart.Made += b.Made
+		if i, ok := zoneIndex[b.Zone]; ok {
+			chart.Zones[i].Attempts += b.Attempts
+			chart.Zones[i].Made += b.Made
+			zonePoints[i] += b.Made * int(b.PointValue)
+		}
+
+		key := [2]int{b.CellCol, b.CellRow}
+		i, ok := cellIndex[key]
+		if !ok {
+			i = len(chart.Cells)
+			cellIndex[key] = i
+			chart.Cells = append(chart.Cells, models.ShotHeatCell{
+				X: -models.CourtHalfWidth + (float64(b.CellCol)+0.5)*shotCellSize,
+				Y: (float64(b.CellRow) + 0.5) * shotCellSize,
+			})
+		}
+		chart.Cells[i].Attempts += b.Attempts
+		chart.Cells[i].Made += b.Made
+
+		i, ok = typeIndex[b.ShotType]
+		if !ok {
+			i = len(chart.ShotTypes)
+			typeIndex[b.ShotType] = i
+			chart.ShotTypes = append(chart.ShotTypes, models.ShotTypeStat{ShotType: b.ShotType, Label: shotTypeLabels[b.ShotType]})
+		}
+		chart.ShotTypes[i].Attempts += b.Attempts
+		chart.ShotTypes[i].Made += b.Made
+	}
+
+	chart.Pct = percentage(chart.Made, chart.Attempts)
+	for i := range chart.Zones {
+		zone := &chart.Zones[i]
+		zone.Pct = percentage(zone.Made, zone.Attempts)
+		if zone.Attempts > 0 {
+			pps := math.Round(float64(zonePoints[i])/float64(zone.Attempts)*100) / 100
+			zone.PointsPerShot = &pps
+		}
+		if chart.Attempts > 0 {
+			zone.Share = math.Round(float64(zone.Attempts)/float64(chart.Attempts)*1000) / 1000
+		}
+	}
+	sort.Slice(chart.Cells, func(i, j int) bool {
+		if chart.Cells[i].Y != chart.Cells[j].Y {
+			return chart.Cells[i].Y < chart.Cells[j].Y
+		}
+		return chart.Cells[i].X < chart.Cells[j].X
+	})
+	sort.Slice(chart.ShotTypes, func(i, j int) bool { return chart.ShotTypes[i].ShotType < chart.ShotTypes[j].ShotType })
+	for i := range chart.Cells {
+		chart.Cells[i].Pct = percentage(chart.Cells[i].Made, chart.Cells[i].Attempts)
+	}
+	if chart.ShotTypes == nil {
+		chart.ShotTypes = []models.ShotTypeStat{}
+	}
+	for i := range chart.ShotTypes {
+		chart.ShotTypes[i].Pct = percentage(chart.ShotTypes[i].Made, chart.ShotTypes[i].Attempts)
+	}
+
+	if cacheKey != "" {
+		writeStatsCache(ctx, cacheKey, chart)
+	}
+	return chart, nil
+}
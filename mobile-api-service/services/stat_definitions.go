@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+)
+
+// Sports with a stat definition
+const (
+	SportBasketball = "basketball"
+	SportVolleyball = "volleyball"
+	SportSoccer     = "soccer"
+	SportFootball   = "football"
+)
+
+var ErrSportNotSupported = errors.New("no stat definition for this team's sport")
+
+func statBound(v float64) *float64 {
+	return &v
+}
+
+func countField(key, label string) models.StatField {
+	return models.StatField{Key: key, Label: label, Type: models.StatFieldInteger, Min: statBound(0)}
+}
+
+func columnField(key, label string) models.StatField {
+	field := countField(key, label)
+	field.Column = true
+	return field
+}
+
+func terms(fields ...string) []models.StatTerm {
+	out := make([]models.StatTerm, len(fields))
+	for i, field := range fields {
+		out[i] = models.StatTerm{Field: field, Coefficient: 1}
+	}
+	return out
+}
+
+func pctFormula(key, label, formula string, numerator, denominator []models.StatTerm) models.StatFormula {
+	return models.StatFormula{Key: key, Label: label, Formula: formula, Numerator: numerator, Denominator: denominator, Scale: 100, Decimals: 1}
+}
+
+func rateFormula(key, label, formula string, numerator, denominator []models.StatTerm, decimals int) models.StatFormula {
+	return models.StatFormula{Key: key, Label: label, Formula: formula, Numerator: numerator, Denominator: denominator, Scale: 1, Decimals: decimals}
+}
+
+// statDefinitions is the registry, keyed by normalized sport. Basketball
+// keeps its box score in the game_stats columns; its advanced metrics are
+// computed by the stats service, so only the extra fields are derived here.
+var statDefinitions = map[string]*models.SportStatDefinition{
+	SportBasketball: {
+		Sport: SportBasketball,
+		Label: "Basketball",
+		Fields: []models.StatField{
+			columnField("points", "Points"),
+			columnField("rebounds", "Rebounds"),
+			columnField("assists", "Assists"),
+			columnField("steals", "Steals"),
+			columnField("blocks", "Blocks"),
+			columnField("turnovers", "Turnovers"),
+			columnField("fouls", "Fouls"),
+			columnField("field_goals_made", "Field goals made"),
+			columnField("field_goals_attempted", "Field goals attempted"),
+			columnField("three_pointers_made", "Three-pointers made"),
+			columnField("three_pointers_attempted", "Three-pointers attempted"),
+			columnField("free_throws_made", "Free throws made"),
+			columnField("free_throws_attempted", "Free throws attempted"),
+			countField("offensive_rebounds", "Offensive rebounds"),
+			countField("defensive_rebounds", "Defensive rebounds"),
+			countField("charges_taken", "Charges taken"),
+			{Key: "started", Label: "Started", Type: models.StatFieldBoolean},
+		},
+		Rules: []models.StatRule{
+			{Fields: []string{"offensive_rebounds", "defensive_rebounds"}, Limit: []string{"rebounds"}},
+		},
+		Derived: []models.StatFormula{
+			pctFormula("offensive_rebound_share", "Offensive rebound share", "offensive_rebounds / rebounds",
+				terms("offensive_rebounds"), terms("rebounds")),
+		},
+	},
+	SportVolleyball: {
+		Sport: SportVolleyball,
+		Label: "Volleyball",
+		Fields: []models.StatField{
+			countField("sets_played", "Sets played"),
+			countField("kills", "Kills"),
+			countField("attack_errors", "Attack errors"),
+			countField("total_attacks", "Total attacks"),
+			countField("assists", "Assists"),
+			countField("service_aces", "Service aces"),
+			countField("service_errors", "Service errors"),
+			countField("reception_errors", "Reception errors"),
+			countField("digs", "Digs"),
+			countField("block_solos", "Solo blocks"),
+			countField("block_assists", "Block assists"),
+			countField("ball_handling_errors", "Ball handling errors"),
+		},
+		Rules: []models.StatRule{
+			{Fields: []string{"kills", "attack_errors"}, Limit: []string{"total_attacks"}},
+		},
+		Derived: []models.StatFormula{
+			rateFormula("hitting_pct", "Hitting percentage", "(kills - attack_errors) / total_attacks",
+				[]models.StatTerm{{Field: "kills", Coefficient: 1}, {Field: "attack_errors", Coefficient: -1}}, terms("total_attacks"), 3),
+			rateFormula("kills_per_set", "Kills per set", "kills / sets_played", terms("kills"), terms("sets_played"), 2),
+			rateFormula("digs_per_set", "Digs per set", "digs / sets_played", terms("digs"), terms("sets_played"), 2),
+			rateFormula("total_blocks", "Total blocks", "block_solos + 0.5 × block_assists",
+				[]models.StatTerm{{Field: "block_solos", Coefficient: 1}, {Field: "block_assists", Coefficient: 0.5}}, nil, 1),
+			rateFormula("points", "Points", "kills + service_aces + block_solos + 0.5 × block_assists",
+				[]models.StatTerm{{Field: "kills", Coefficient: 1}, {Field: "service_aces", Coefficient: 1}, {Field: "block_solos", Coefficient: 1}, {Field: "block_assists", Coefficient: 0.5}}, nil, 1),
+		},
+	},
+	SportSoccer: {
+		Sport: SportSoccer,
+		Label: "Soccer",
+		Fields: []models.StatField{
+			{Key: "started", Label: "Started", Type: models.StatFieldBoolean},
+			countField("goals", "Goals"),
+			countField("assists", "Assists"),
+			countField("shots", "Shots"),
+			countField("shots_on_target", "Shots on target"),
+			countField("fouls_committed", "Fouls committed"),
+			countField("offsides", "Offsides"),
+			{Key: "yellow_cards", Label: "Yellow cards", Type: models.StatFieldInteger, Min: statBound(0), Max: statBound(2)},
+			{Key: "red_cards", Label: "Red cards", Type: models.StatFieldInteger, Min: statBound(0), Max: statBound(1)},
+			countField("saves", "Saves"),
+			countField("goals_conceded", "Goals conceded"),
+		},
+		Rules: []models.StatRule{
+			{Fields: []string{"goals"}, Limit: []string{"shots_on_target"}},
+			{Fields: []string{"shots_on_target"}, Limit: []string{"shots"}},
+		},
+		Derived: []models.StatFormula{
+			pctFormula("shot_accuracy", "Shot accuracy", "shots_on_target / shots", terms("shots_on_target"), terms("shots")),
+			pctFormula("conversion_rate", "Conversion rate", "goals / shots", terms("goals"), terms("shots")),
+			pctFormula("save_pct", "Save percentage", "saves / (saves + goals_conceded)", terms("saves"), terms("saves", "goals_conceded")),
+			rateFormula("goal_contributions", "Goal contributions", "goals + assists", terms("goals", "assists"), nil, 0),
+		},
+	},
+	SportFootball: {
+		Sport: SportFootball,
+		Label: "Football",
+		Fields: []models.StatField{
+			countField("passing_completions", "Completions"),
+			countField("passing_attempts", "Passing attempts"),
+			{Key: "passing_yards", Label: "Passing yards", Type: models.StatFieldInteger},
+			countField("passing_touchdowns", "Passing touchdowns"),
+			countField("interceptions_thrown", "Interceptions thrown"),
+			countField("rushing_attempts", "Rushing attempts"),
+			{Key: "rushing_yards", Label: "Rushing yards", Type: models.StatFieldInteger},
+			countField("rushing_touchdowns", "Rushing touchdowns"),
+			countField("targets", "Targets"),
+			countField("receptions", "Receptions"),
+			{Key: "receiving_yards", Label: "Receiving yards", Type: models.StatFieldInteger},
+			countField("receiving_touchdowns", "Receiving touchdowns"),
+			countField("tackles", "Tackles"),
+			{Key: "sacks", Label: "Sacks", Type: models.StatFieldDecimal, Min: statBound(0)},
+			countField("interceptions", "Interceptions"),
+			countField("fumbles_lost", "Fumbles lost"),
+			countField("field_goals_made", "Field goals made"),
+			countField("field_goals_attempted", "Field goals attempted"),
+		},
+		Rules: []models.StatRule{
+			{Fields: []string{"passing_completions"}, Limit: []string{"passing_attempts"}},
+			{Fields: []string{"passing_touchdowns"}, Limit: []string{"passing_completions"}},
+			{Fields: []string{"receptions"}, Limit: []string{"targets"}},
+			{Fields: []string{"receiving_touchdowns"}, Limit: []string{"receptions"}},
+			{Fields: []string{"rushing_touchdowns"}, Limit: []string{"rushing_attempts"}},
+			{Fields: []string{"field_goals_made"}, Limit: []string{"field_goals_attempted"}},
+		},
+		Derived: []models.StatFormula{
+			pctFormula("completion_pct", "Completion percentage", "passing_completions / passing_attempts",
+				terms("passing_completions"), terms("passing_attempts")),
+			rateFormula("yards_per_pass", "Yards per pass attempt", "passing_yards / passing_attempts",
+				terms("passing_yards"), terms("passing_attempts"), 1),
+			rateFormula("yards_per_carry", "Yards per carry", "rushing_yards / rushing_attempts",
+				terms("rushing_yards"), terms("rushing_attempts"), 1),
+			rateFormula("yards_per_reception", "Yards per reception", "receiving_yards / receptions",
+				terms("receiving_yards"), terms("receptions"), 1),
+			rateFormula("total_touchdowns", "Total touchdowns", "passing_touchdowns + rushing_touchdowns + receiving_touchdowns",
+				terms("passing_touchdowns", "rushing_touchdowns", "receiving_touchdowns"), nil, 0),
+			pctFormula("field_goal_pct", "Field goal percentage", "field_goals_made / field_goals_attempted",
+				terms("field_goals_made"), terms("field_goals_attempted")),
+		},
+	},
+}
+
+// sportAliases maps other spellings of teams.sport_type to registry keys
+var sportAliases = map[string]string{
+	"":                     SportBasketball,
+	"american football":    SportFootball,
+	"association football": SportSoccer,
+}
+
+// NormalizeSport turns a teams.sport_type into a registry key. Teams without
+// a sport predate the registry and are basketball teams.
+func NormalizeSport(sportType string) string {
+	sport := strings.ToLower(strings.TrimSpace(sportType))
+	if alias, ok := sportAliases[sport]; ok {
+		return alias
+	}
+	return sport
+}
+
+// StatDefinitionFor returns the definition of a teams.sport_type
+func StatDefinitionFor(sportType string) (*models.SportStatDefinition, error) {
+	definition, ok := statDefinitions[NormalizeSport(sportType)]
+	if !ok {
+		return nil, ErrSportNotSupported
+	}
+	return definition, nil
+}
+
+// StatDefinitions lists every registered sport
+func StatDefinitions() []*models.SportStatDefinition {
+	list := make([]*models.SportStatDefinition, 0, len(statDefinitions))
+	for _, definition := range statDefinitions {
+		list = append(list, definition)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Sport < list[j].Sport })
+	return list
+}
+
+// TeamStatDefinition returns the definition of the team's sport
+func TeamStatDefinition(ctx context.Context, teamID uint) (*models.SportStatDefinition, error) {
+	var team models.Team
+	if err := database.DB.WithContext(ctx).Unscoped().Select("id", "sport_type").First(&team, teamID).Error; err != nil {
+		return nil, err
+	}
+	return StatDefinitionFor(team.SportType)
+}
+
+// columnValues exposes the game_stats columns to rules and formulas
+func columnValues(c models.GameStatCounts) map[string]float64 {
+	return map[string]float64{
+		"points":                   float64(c.Points),
+		"rebounds":                 float64(c.Rebounds),
+		"assists":                  float64(c.Assists),
+		"steals":                   float64(c.Steals),
+		"blocks":                   float64(c.Blocks),
+		"turnovers":                float64(c.Turnovers),
+		"fouls":                    float64(c.Fouls),
+		"field_goals_made":         float64(c.FieldGoalsMade),
+		"field_goals_attempted":    float64(c.FieldGoalsAttempted),
+		"three_pointers_made":      float64(c.ThreePointersMade),
+		"three_pointers_attempted": float64(c.ThreePointersAttempted),
+		"free_throws_made":         float64(c.FreeThrowsMade),
+		"free_throws_attempted":    float64(c.FreeThrowsAttempted),
+	}
+}
+
+func sumFields(values map[string]float64, fields []string) float64 {
+	total := 0.0
+	for _, field := range fields {
+		total += values[field]
+	}
+	return total
+}
+
+// usesColumns reports whether the sport records its stats in the game_stats columns
+func usesColumns(definition *models.SportStatDefinition) bool {
+	for _, field := range definition.Fields {
+		if field.Column {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSportStats checks a stat line against the sport's definition:
+// additional_stats may only hold the sport's non-column fields with the right
+// types and bounds, sports without columns must leave them at zero, and the
+// rules must hold. Booleans count as 1 when true.
+func ValidateSportStats(definition *models.SportStatDefinition, counts models.GameStatCounts, additional json.RawMessage) []string {
+	var problems []string
+	values := map[string]float64{}
+	if usesColumns(definition) {
+		values = columnValues(counts)
+	} else if counts != (models.GameStatCounts{}) {
+		problems = append(problems, fmt.Sprintf("%s stats go in additional_stats; the basketball columns must be 0", definition.Label))
+	}
+
+	var raw map[string]interface{}
+	if len(additional) > 0 {
+		if err := json.Unmarshal(additional, &raw); err != nil {
+			return append(problems, "additional_stats must be a JSON object")
+		}
+	}
+	fields := make(map[string]models.StatField, len(definition.Fields))
+	for _, field := range definition.Fields {
+		fields[field.Key] = field
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok || field.Column {
+			problems = append(problems, fmt.Sprintf("%s is not a %s stat", key, definition.Label))
+			continue
+		}
+		value, problem := fieldValue(field, raw[key])
+		if problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		values[key] = value
+	}
+
+	for _, rule := range definition.Rules {
+		if sumFields(values, rule.Fields) > sumFields(values, rule.Limit) {
+			problems = append(problems, fmt.Sprintf("%s must not exceed %s",
+				strings.Join(rule.Fields, " + "), strings.Join(rule.Limit, " + ")))
+		}
+	}
+	return problems
+}
+
+func fieldValue(field models.StatField, raw interface{}) (float64, string) {
+	var value float64
+	switch field.Type {
+	case models.StatFieldBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return 0, fmt.Sprintf("%s must be true or false", field.Key)
+		}
+		if b {
+			value = 1
+		}
+		return value, ""
+	case models.StatFieldInteger:
+		n, ok := raw.(float64)
+		if !ok || n != math.Trunc(n) {
+			return 0, fmt.Sprintf("%s must be a whole number", field.Key)
+		}
+		value = n
+	default:
+		n, ok := raw.(float64)
+		if !ok {
+			return 0, fmt.Sprintf("%s must be a number", field.Key)
+		}
+		value = n
+	}
+	if field.Min != nil && value < *field.Min {
+		return 0, fmt.Sprintf("%s must be at least %g", field.Key, *field.Min)
+	}
+	if field.Max != nil && value > *field.Max {
+		return 0, fmt.Sprintf("%s must be at most %g", field.Key, *field.Max)
+	}
+	return value, ""
+}
+
+// additionalValues reads the numeric and boolean fields of a stored additional_stats
+func additionalValues(definition *models.SportStatDefinition, additional json.RawMessage) map[string]float64 {
+	values := map[string]float64{}
+	var raw map[string]interface{}
+	if len(additional) == 0 || json.Unmarshal(additional, &raw) != nil {
+		return values
+	}
+	for _, field := range definition.Fields {
+		if field.Column {
+			continue
+		}
+		switch v := raw[field.Key].(type) {
+		case float64:
+			values[field.Key] = v
+		case bool:
+			if v {
+				values[field.Key] = 1
+			}
+		}
+	}
+	return values
+}
+
+// EvaluateFormula computes a derived metric, or nil when its denominator is zero
+func EvaluateFormula(formula models.StatFormula, values map[string]float64) *float64 {
+	sum := func(terms []models.StatTerm) float64 {
+		total := 0.0
+		for _, term := range terms {
+			total += term.Coefficient * values[term.Field]
+		}
+		return total
+	}
+	denominator := 1.0
+	if len(formula.Denominator) > 0 {
+		denominator = sum(formula.Denominator)
+	}
+	if denominator == 0 {
+		return nil
+	}
+	scale := math.Pow(10, float64(formula.Decimals))
+	v := math.Round(sum(formula.Numerator)/denominator*formula.Scale*scale) / scale
+	return &v
+}
+
+// summarizeSportStats totals the sport's additional fields over games and
+// derives the sport's metrics. columns holds the game_stats totals for
+// sports that use them.
+func summarizeSportStats(definition *models.SportStatDefinition, totals map[string]float64, columns models.GameStatCounts, games int) *models.SportStatSummary {
+	summary := &models.SportStatSummary{
+		Totals:   map[string]float64{},
+		Averages: map[string]float64{},
+		Derived:  map[string]*float64{},
+	}
+	values := map[string]float64{}
+	if usesColumns(definition) {
+		values = columnValues(columns)
+	}
+	for _, field := range definition.Fields {
+		if field.Column {
+			continue
+		}
+		total := math.Round(totals[field.Key]*100) / 100
+		values[field.Key] = total
+		summary.Totals[field.Key] = total
+		if games > 0 {
+			summary.Averages[field.Key] = math.Round(total/float64(games)*100) / 100
+		}
+	}
+	for _, formula := range definition.Derived {
+		summary.Derived[formula.Key] = EvaluateFormula(formula, values)
+	}
+	return summary
+}
@@ -0,0 +1,743 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStatImportProfileNotFound = errors.New("import profile not found")
+	ErrStatImportProfileExists   = errors.New("an import profile with this name already exists")
+	ErrStatImportInvalid         = errors.New("some rows are invalid; fix them or set skip_invalid")
+	ErrStatImportEmpty           = errors.New("the file has no rows to import")
+)
+
+// StatImportMappingError is returned when the column mapping does not fit the
+// file or the team's sport
+type StatImportMappingError struct {
+	Problems []string
+}
+
+func (e *StatImportMappingError) Error() string {
+	return "invalid column mapping: " + strings.Join(e.Problems, "; ")
+}
+
+// Import targets besides the sport's stat fields
+const (
+	importJersey    = "jersey_number"
+	importName      = "name"
+	importFirstName = "first_name"
+	importLastName  = "last_name"
+	importMinutes   = "minutes_played"
+	importNotes     = "notes"
+)
+
+// importPairs are scorebook columns holding "made-attempted" in one cell
+var importPairs = map[string][2]string{
+	"field_goals":    {"field_goals_made", "field_goals_attempted"},
+	"three_pointers": {"three_pointers_made", "three_pointers_attempted"},
+	"free_throws":    {"free_throws_made", "free_throws_attempted"},
+}
+
+// importHeaderAliases are the header names common scorebook exports use,
+// normalized by importHeaderKey. Fields of the stat definition are also
+// recognized by their key and label.
+var importHeaderAliases = map[string]string{
+	"#":            importJersey,
+	"no":           importJersey,
+	"num":          importJersey,
+	"number":       importJersey,
+	"jersey":       importJersey,
+	"jersey#":      importJersey,
+	"jerseynumber": importJersey,
+	"uniform":      importJersey,
+	"player":       importName,
+	"playername":   importName,
+	"fullname":     importName,
+	"first":        importFirstName,
+	"firstname":    importFirstName,
+	"last":         importLastName,
+	"lastname":     importLastName,
+	"min":          importMinutes,
+	"mins":         importMinutes,
+	"mp":           importMinutes,
+	"minutes":      importMinutes,
+	"comments":     importNotes,
+	"pts":          "points",
+	"reb":          "rebounds",
+	"rebs":         "rebounds",
+	"trb":          "rebounds",
+	"tr":           "rebounds",
+	"ast":          "assists",
+	"stl":          "steals",
+	"blk":          "blocks",
+	"to":           "turnovers",
+	"tov":          "turnovers",
+	"pf":           "fouls",
+	"fgm":          "field_goals_made",
+	"fga":          "field_goals_attempted",
+	"3pm":          "three_pointers_made",
+	"3fgm":         "three_pointers_made",
+	"3ptm":         "three_pointers_made",
+	"3pa":          "three_pointers_attempted",
+	"3fga":         "three_pointers_attempted",
+	"3pta":         "three_pointers_attempted",
+	"ftm":          "free_throws_made",
+	"fta":          "free_throws_attempted",
+	"fg":           "field_goals",
+	"3p":           "three_pointers",
+	"3pt":          "three_pointers",
+	"3fg":          "three_pointers",
+	"ft":           "free_throws",
+	"oreb":         "offensive_rebounds",
+	"or":           "offensive_rebounds",
+	"dreb":         "defensive_rebounds",
+	"dr":           "defensive_rebounds",
+	"gs":           "started",
+}
+
+// importTotalNames are the names of the total rows exports append
+var importTotalNames = map[string]bool{"total": true, "totals": true, "team": true, "team totals": true}
+
+// importHeaderKey normalizes a header for alias lookup: "3-PT M" → "3ptm".
+// Percent signs are kept so "FG%" does not read as "FG".
+func importHeaderKey(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '#' || r == '%' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// importNameKey normalizes a player name for matching
+func importNameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '.':
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// importNameKeys returns the forms a CSV name may match under: as written,
+// "Last, First" flipped, and a first initial
+func importNameKeys(name string) []string {
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = first + " " + last
+	}
+	full := importNameKey(name)
+	if full == "" {
+		return nil
+	}
+	keys := []string{full}
+	if parts := strings.Fields(full); len(parts) > 1 && len(parts[0]) > 1 {
+		keys = append(keys, parts[0][:1]+" "+strings.Join(parts[1:], " "))
+	}
+	return keys
+}
+
+// importTargets lists the fields a CSV column can map to for a sport
+func importTargets(definition *models.SportStatDefinition) map[string]bool {
+	targets := map[string]bool{
+		importJersey: true, importName: true, importFirstName: true, importLastName: true,
+		importMinutes: true, importNotes: true,
+	}
+	for _, field := range definition.Fields {
+		targets[field.Key] = true
+	}
+	if usesColumns(definition) {
+		for pair := range importPairs {
+			targets[pair] = true
+		}
+	}
+	return targets
+}
+
+// detectImportColumns maps headers by their common names and the sport's field keys and labels
+func detectImportColumns(definition *models.SportStatDefinition, headers []string) map[string]string {
+	targets := importTargets(definition)
+	byKey := make(map[string]string, len(importHeaderAliases))
+	for alias, target := range importHeaderAliases {
+		byKey[alias] = target
+	}
+	for target := range targets {
+		byKey[importHeaderKey(target)] = target
+	}
+	for _, field := range definition.Fields {
+		byKey[importHeaderKey(field.Label)] = field.Key
+	}
+
+	columns := map[string]string{}
+	for _, header := range headers {
+		target, ok := byKey[importHeaderKey(header)]
+		if !ok || !targets[target] {
+			continue
+		}
+		if _, taken := columns[target]; !taken {
+			columns[target] = strings.TrimSpace(header)
+		}
+	}
+	// Separate made and attempted columns win over a combined one
+	for pair, fields := range importPairs {
+		if _, ok := columns[fields[0]]; ok {
+			delete(columns, pair)
+		}
+	}
+	return columns
+}
+
+// checkImportColumns verifies a mapping against the sport; headers is nil
+// when validating a saved profile without a file
+func checkImportColumns(definition *models.SportStatDefinition, columns map[string]string, headers []string) []string {
+	var problems []string
+	targets := importTargets(definition)
+	present := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		present[strings.ToLower(strings.TrimSpace(header))] = true
+	}
+
+	keys := make([]string, 0, len(columns))
+	for target := range columns {
+		keys = append(keys, target)
+	}
+	sort.Strings(keys)
+	for _, target := range keys {
+		header := strings.TrimSpace(columns[target])
+		switch {
+		case !targets[target]:
+			problems = append(problems, fmt.Sprintf("%s is not a %s stat", target, definition.Label))
+		case header == "":
+			problems = append(problems, fmt.Sprintf("%s has no column", target))
+		case headers != nil && !present[strings.ToLower(header)]:
+			problems = append(problems, fmt.Sprintf("column %q of %s is not in the file", header, target))
+		}
+	}
+	for pair, fields := range importPairs {
+		if _, ok := columns[pair]; ok {
+			if _, made := columns[fields[0]]; made {
+				problems = append(problems, fmt.Sprintf("%s and %s are both mapped", pair, fields[0]))
+			}
+		}
+	}
+	_, jersey := columns[importJersey]
+	_, name := columns[importName]
+	_, last := columns[importLastName]
+	if !jersey && !name && !last {
+		problems = append(problems, "map jersey_number or a name column to match players")
+	}
+	return problems
+}
+
+// countPointers exposes the game_stats columns by field key
+func countPointers(c *models.GameStatCounts) map[string]*int {
+	return map[string]*int{
+		"points":                   &c.Points,
+		"rebounds":                 &c.Rebounds,
+		"assists":                  &c.Assists,
+		"steals":                   &c.Steals,
+		"blocks":                   &c.Blocks,
+		"turnovers":                &c.Turnovers,
+		"fouls":                    &c.Fouls,
+		"field_goals_made":         &c.FieldGoalsMade,
+		"field_goals_attempted":    &c.FieldGoalsAttempted,
+		"three_pointers_made":      &c.ThreePointersMade,
+		"three_pointers_attempted": &c.ThreePointersAttempted,
+		"free_throws_made":         &c.FreeThrowsMade,
+		"free_throws_attempted":    &c.FreeThrowsAttempted,
+	}
+}
+
+// emptyImportCell reports cells scorebooks leave for "none"
+func emptyImportCell(cell string) bool {
+	return cell == "" || cell == "-" || cell == "—"
+}
+
+func parseImportCount(cell string) (int, error) {
+	if emptyImportCell(cell) {
+		return 0, nil
+	}
+	return strconv.Atoi(cell)
+}
+
+// parseImportMinutes reads "23", "23.5" or "23:45", rounded to whole minutes
+func parseImportMinutes(cell string) (int, error) {
+	if minutes, seconds, ok := strings.Cut(cell, ":"); ok {
+		m, err := strconv.Atoi(minutes)
+		if err != nil {
+			return 0, err
+		}
+		s, err := strconv.Atoi(seconds)
+		if err != nil || s < 0 || s >= 60 {
+			return 0, fmt.Errorf("invalid seconds")
+		}
+		return int(math.Round(float64(m) + float64(s)/60)), nil
+	}
+	minutes, err := strconv.ParseFloat(cell, 64)
+	return int(math.Round(minutes)), err
+}
+
+func parseImportBool(cell string) (bool, bool) {
+	switch strings.ToLower(cell) {
+	case "", "-", "0", "n", "no", "false":
+		return false, true
+	case "1", "x", "y", "yes", "true", "*":
+		return true, true
+	}
+	return false, false
+}
+
+// importCSV reads the records of a file and the line each starts on
+func importCSV(text, delimiter string) ([][]string, []int, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(text, "\ufeff")))
+	if delimiter != "" {
+		reader.Comma = []rune(delimiter)[0]
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	return records, lines, nil
+}
+
+// loadImportColumns picks the mapping of an import: the request's columns,
+// else the saved profile's, else the detected ones
+func loadImportColumns(ctx context.Context, teamID uint, req *models.StatImportRequest) (map[string]string, string, error) {
+	delimiter := req.Delimiter
+	if len(req.Columns) > 0 {
+		return req.Columns, delimiter, nil
+	}
+	if req.ProfileID == nil {
+		return nil, delimiter, nil
+	}
+	profile, err := GetStatImportProfile(ctx, teamID, *req.ProfileID)
+	if err != nil {
+		return nil, "", err
+	}
+	var columns map[string]string
+	if err := json.Unmarshal(profile.Columns, &columns); err != nil {
+		return nil, "", err
+	}
+	if delimiter == "" {
+		delimiter = profile.Delimiter
+	}
+	return columns, delimiter, nil
+}
+
+// PreviewStatImport parses a scorebook CSV for a game, matches its rows to
+// the roster and validates them like a bulk entry, without saving anything
+func PreviewStatImport(ctx context.Context, event *models.Event, req *models.StatImportRequest) (*models.StatImportPreview, []models.GameStatLine, error) {
+	definition, err := TeamStatDefinition(ctx, event.TeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var plays int64
+	if err := database.DB.WithContext(ctx).Model(&models.GamePlay{}).Where("event_id = ?", event.ID).Count(&plays).Error; err != nil {
+		return nil, nil, err
+	}
+	if plays > 0 {
+		return nil, nil, ErrStatsFromPlayLog
+	}
+
+	columns, delimiter, err := loadImportColumns(ctx, event.TeamID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, lineNumbers, err := importCSV(req.CSV, delimiter)
+	if err != nil {
+		return nil, nil, &StatImportMappingError{Problems: []string{"the file is not valid CSV: " + err.Error()}}
+	}
+	for len(records) > 0 && strings.TrimSpace(strings.Join(records[0], "")) == "" {
+		records, lineNumbers = records[1:], lineNumbers[1:]
+	}
+	if len(records) < 2 {
+		return nil, nil, ErrStatImportEmpty
+	}
+	headers := records[0]
+	if columns == nil {
+		columns = detectImportColumns(definition, headers)
+	}
+	if problems := checkImportColumns(definition, columns, headers); len(problems) > 0 {
+		return nil, nil, &StatImportMappingError{Problems: problems}
+	}
+
+	index := make(map[string]int, len(headers))
+	for i, header := range headers {
+		key := strings.ToLower(strings.TrimSpace(header))
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+	columnOf := make(map[string]int, len(columns))
+	mapped := make(map[int]bool, len(columns))
+	for target, header := range columns {
+		columnOf[target] = index[strings.ToLower(strings.TrimSpace(header))]
+		mapped[columnOf[target]] = true
+	}
+
+	preview := &models.StatImportPreview{
+		EventID:         event.ID,
+		Columns:         columns,
+		UnmappedColumns: []string{},
+		Rows:            []models.StatImportRow{},
+		SkippedLines:    []int{},
+	}
+	for i, header := range headers {
+		if !mapped[i] && strings.TrimSpace(header) != "" {
+			preview.UnmappedColumns = append(preview.UnmappedColumns, header)
+		}
+	}
+
+	roster, err := teamRoster(ctx, event.TeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uint]*rosterPlayer, len(roster))
+	byJersey := make(map[int]*rosterPlayer)
+	byName := make(map[string][]*rosterPlayer)
+	for i := range roster {
+		player := &roster[i]
+		byID[player.UserID] = player
+		if player.JerseyNumber != nil {
+			byJersey[*player.JerseyNumber] = player
+		}
+		for _, key := range importNameKeys(player.Name) {
+			byName[key] = append(byName[key], player)
+		}
+	}
+	var recorded []models.GameStat
+	if err := database.DB.WithContext(ctx).Where("event_id = ?", event.ID).Find(&recorded).Error; err != nil {
+		return nil, nil, err
+	}
+	stored := make(map[uint]*models.GameStat, len(recorded))
+	for i := range recorded {
+		stored[recorded[i].PlayerID] = &recorded[i]
+	}
+
+	var lines []models.GameStatLine
+	var lineRows []int
+	seen := make(map[uint]int)
+	for r, record := range records[1:] {
+		lineNumber := lineNumbers[r+1]
+		cell := func(target string) string {
+			i, ok := columnOf[target]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			preview.SkippedLines = append(preview.SkippedLines, lineNumber)
+			continue
+		}
+
+		row := models.StatImportRow{Line: lineNumber, Errors: []string{}}
+		row.Name = cell(importName)
+		if row.Name == "" {
+			row.Name = strings.TrimSpace(cell(importFirstName) + " " + cell(importLastName))
+		}
+		jersey := strings.TrimPrefix(cell(importJersey), "#")
+		if importTotalNames[importNameKey(row.Name)] || importTotalNames[importNameKey(jersey)] {
+			preview.SkippedLines = append(preview.SkippedLines, lineNumber)
+			continue
+		}
+		if jersey != "" {
+			if n, err := strconv.Atoi(jersey); err == nil {
+				row.JerseyNumber = &n
+			} else {
+				row.Errors = append(row.Errors, fmt.Sprintf("jersey number %q is not a number", jersey))
+			}
+		}
+
+		// Match the player: an explicit assignment, else the jersey number
+		// (checked against the name when both are given), else the name
+		var player *rosterPlayer
+		if id, ok := req.Assignments[lineNumber]; ok {
+			if player = byID[id]; player == nil {
+				row.Errors = append(row.Errors, "assigned player is not on the team roster")
+			} else {
+				row.MatchedBy = models.StatImportMatchAssignment
+			}
+		} else {
+			var named []*rosterPlayer
+			for _, key := range importNameKeys(row.Name) {
+				if named = byName[key]; len(named) > 0 {
+					break
+				}
+			}
+			if row.JerseyNumber != nil && byJersey[*row.JerseyNumber] != nil {
+				player = byJersey[*row.JerseyNumber]
+				row.MatchedBy = models.StatImportMatchJersey
+				if row.Name != "" && len(named) > 0 {
+					agrees := false
+					for _, candidate := range named {
+						agrees = agrees || candidate == player
+					}
+					if !agrees {
+						row.Errors = append(row.Errors, fmt.Sprintf("jersey #%d belongs to %s, not %s", *row.JerseyNumber, player.Name, row.Name))
+					}
+				}
+			} else {
+				switch len(named) {
+				case 0:
+					row.Errors = append(row.Errors, "no roster player matches this jersey number or name")
+				case 1:
+					player = named[0]
+					row.MatchedBy = models.StatImportMatchName
+				default:
+					row.Errors = append(row.Errors, fmt.Sprintf("%s matches several roster players", row.Name))
+				}
+			}
+		}
+		if player != nil {
+			row.PlayerID = &player.UserID
+			row.PlayerName = player.Name
+			row.Update = stored[player.UserID] != nil
+			if previous, ok := seen[player.UserID]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("player already imported from line %d", previous))
+			}
+			seen[player.UserID] = lineNumber
+		}
+
+		// A player's stored line is the base, so fields the file does not
+		// map keep their values
+		var base models.GameStatLine
+		if player != nil && stored[player.UserID] != nil {
+			base = statLineOf(stored[player.UserID])
+		}
+		line, problems := importStatLine(definition, columns, cell, base)
+		row.Errors = append(row.Errors, problems...)
+		if player != nil {
+			line.PlayerID = player.UserID
+			row.Stats = &line
+		}
+		if len(row.Errors) == 0 {
+			lines = append(lines, line)
+			lineRows = append(lineRows, len(preview.Rows))
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+
+	// The lines that parsed cleanly still have to pass the bulk entry checks
+	var valid []models.GameStatLine
+	err = validateGameStatLines(ctx, event, lines)
+	var invalid *GameStatValidationError
+	switch {
+	case errors.As(err, &invalid):
+		failed := make(map[int]bool, len(invalid.Rows))
+		for _, rowError := range invalid.Rows {
+			row := &preview.Rows[lineRows[rowError.Index]]
+			row.Errors = append(row.Errors, rowError.Errors...)
+			failed[rowError.Index] = true
+		}
+		for i, line := range lines {
+			if !failed[i] {
+				valid = append(valid, line)
+			}
+		}
+	case err != nil:
+		return nil, nil, err
+	default:
+		valid = lines
+	}
+
+	preview.ValidRows = len(valid)
+	preview.InvalidRows = len(preview.Rows) - len(valid)
+	return preview, valid, nil
+}
+
+// importStatLine reads the stat cells of a row onto base. Only mapped fields
+// are replaced; empty minutes, notes and additional number cells keep the
+// base value too.
+func importStatLine(definition *models.SportStatDefinition, columns map[string]string, cell func(string) string, base models.GameStatLine) (models.GameStatLine, []string) {
+	line := base
+	var problems []string
+	counts := countPointers(&line.GameStatCounts)
+	additional := map[string]interface{}{}
+	if len(base.AdditionalStats) > 0 {
+		json.Unmarshal(base.AdditionalStats, &additional)
+	}
+	header := func(target string) string { return columns[target] }
+
+	for pair, fields := range importPairs {
+		if _, ok := columns[pair]; !ok {
+			continue
+		}
+		value := cell(pair)
+		if emptyImportCell(value) {
+			continue
+		}
+		made, attempted, ok := strings.Cut(strings.ReplaceAll(value, "/", "-"), "-")
+		m, err1 := strconv.Atoi(strings.TrimSpace(made))
+		a, err2 := strconv.Atoi(strings.TrimSpace(attempted))
+		if !ok || err1 != nil || err2 != nil {
+			problems = append(problems, fmt.Sprintf("%s: %q is not made-attempted", header(pair), value))
+			continue
+		}
+		*counts[fields[0]], *counts[fields[1]] = m, a
+	}
+
+	for _, field := range definition.Fields {
+		if _, ok := columns[field.Key]; !ok {
+			continue
+		}
+		value := cell(field.Key)
+		if field.Column {
+			n, err := parseImportCount(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a whole number", header(field.Key), value))
+			} else if n < 0 {
+				problems = append(problems, fmt.Sprintf("%s must not be negative", header(field.Key)))
+			} else {
+				*counts[field.Key] = n
+			}
+			continue
+		}
+		if field.Type == models.StatFieldBoolean {
+			b, ok := parseImportBool(value)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: %q is not yes or no", header(field.Key), value))
+			} else if b {
+				additional[field.Key] = true
+			} else {
+				delete(additional, field.Key)
+			}
+			continue
+		}
+		if emptyImportCell(value) {
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a number", header(field.Key), value))
+			continue
+		}
+		additional[field.Key] = n
+	}
+
+	if value := cell(importMinutes); !emptyImportCell(value) {
+		minutes, err := parseImportMinutes(value)
+		if err != nil || minutes < 0 {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a number of minutes", header(importMinutes), value))
+		} else {
+			line.MinutesPlayed = &minutes
+		}
+	}
+	if notes := cell(importNotes); notes != "" {
+		line.Notes = notes
+	}
+	line.AdditionalStats = nil
+	if len(additional) > 0 {
+		line.AdditionalStats, _ = json.Marshal(additional)
+	}
+	return line, problems
+}
+
+// ImportGameStats saves the rows of a previewed import. Rows with errors fail
+// the import unless SkipInvalid is set, in which case only valid rows are saved.
+func ImportGameStats(ctx context.Context, event *models.Event, userID uint, req *models.StatImportRequest) (*models.StatImportPreview, int, error) {
+	preview, lines, err := PreviewStatImport(ctx, event, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if preview.InvalidRows > 0 && !req.SkipInvalid {
+		return preview, 0, ErrStatImportInvalid
+	}
+	if len(lines) == 0 {
+		return preview, 0, ErrStatImportEmpty
+	}
+	written, err := SaveGameStats(ctx, event, userID, lines)
+	return preview, written, err
+}
+
+// ListStatImportProfiles returns a team's saved mappings by name
+func ListStatImportProfiles(ctx context.Context, teamID uint) ([]models.StatImportProfile, error) {
+	profiles := []models.StatImportProfile{}
+	err := database.DB.WithContext(ctx).Where("team_id = ?", teamID).Order("name").Find(&profiles).Error
+	return profiles, err
+}
+
+func GetStatImportProfile(ctx context.Context, teamID, profileID uint) (*models.StatImportProfile, error) {
+	var profile models.StatImportProfile
+	if err := database.DB.WithContext(ctx).Where("team_id = ?", teamID).First(&profile, profileID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStatImportProfileNotFound
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveStatImportProfile creates a profile, or updates it when profile has an ID
+func SaveStatImportProfile(ctx context.Context, profile *models.StatImportProfile, req *models.StatImportProfileRequest) error {
+	definition, err := TeamStatDefinition(ctx, profile.TeamID)
+	if err != nil {
+		return err
+	}
+	if problems := checkImportColumns(definition, req.Columns, nil); len(problems) > 0 {
+		return &StatImportMappingError{Problems: problems}
+	}
+
+	db := database.DB.WithContext(ctx)
+	var taken int64
+	if err := db.Model(&models.StatImportProfile{}).
+		Where("team_id = ? AND name = ? AND id <> ?", profile.TeamID, req.Name, profile.ID).
+		Count(&taken).Error; err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrStatImportProfileExists
+	}
+
+	profile.Name = req.Name
+	profile.Delimiter = req.Delimiter
+	if profile.Delimiter == "" {
+		profile.Delimiter = ","
+	}
+	profile.Columns, _ = json.Marshal(req.Columns)
+	return db.Save(profile).Error
+}
+
+func DeleteStatImportProfile(ctx context.Context, teamID, profileID uint) error {
+	result := database.DB.WithContext(ctx).Where("team_id = ?", teamID).Delete(&models.StatImportProfile{}, profileID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatImportProfileNotFound
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+func TestImportStatLine(t *testing.T) {
+	definition, err := StatDefinitionFor(SportBasketball)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := map[string]string{
+		"points":             "PTS",
+		"field_goals":        "FG",
+		"offensive_rebounds": "OREB",
+		"started":            "GS",
+		importMinutes:        "MIN",
+		importNotes:          "Notes",
+	}
+	stored := models.GameStatLine{
+		PlayerID: 7,
+		GameStatCounts: models.GameStatCounts{
+			Points: 10, Rebounds: 5,
+			FieldGoalsMade: 4, FieldGoalsAttempted: 9,
+			FreeThrowsMade: 2, FreeThrowsAttempted: 2,
+		},
+		MinutesPlayed:   intPtr(20),
+		AdditionalStats: json.RawMessage(`{"charges_taken":2,"started":true}`),
+		Notes:           "Left early",
+	}
+
+	tests := []struct {
+		name  string
+		base  models.GameStatLine
+		cells map[string]string
+		want  models.GameStatLine
+	}{
+		{
+			name:  "new line",
+			cells: map[string]string{"points": "3", "field_goals": "-", "started": "yes", importMinutes: "12:40", importNotes: "Debut"},
+			want: models.GameStatLine{
+				GameStatCounts:  models.GameStatCounts{Points: 3},
+				MinutesPlayed:   intPtr(13),
+				AdditionalStats: json.RawMessage(`{"started":true}`),
+				Notes:           "Debut",
+			},
+		},
+		{
+			name:  "mapped fields replace the stored line's",
+			base:  stored,
+			cells: map[string]string{"points": "12", "field_goals": "5-10", "offensive_rebounds": "1", "started": "no", importMinutes: "18", importNotes: "Cramped up"},
+			want: models.GameStatLine{
+				PlayerID: 7,
+				GameStatCounts: models.GameStatCounts{
+					Points: 12, Rebounds: 5,
+					FieldGoalsMade: 5, FieldGoalsAttempted: 10,
+					FreeThrowsMade: 2, FreeThrowsAttempted: 2,
+				},
+				MinutesPlayed:   intPtr(18),
+				AdditionalStats: json.RawMessage(`{"charges_taken":2,"offensive_rebounds":1}`),
+				Notes:           "Cramped up",
+			},
+		},
+		{
+			name:  "empty cells keep the stored values",
+			base:  stored,
+			cells: map[string]string{"points": "10", "field_goals": "", "offensive_rebounds": "", "started": "yes"},
+			want:  stored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, problems := importStatLine(definition, columns, func(target string) string { return tt.cells[target] }, tt.base)
+			if len(problems) > 0 {
+				t.Fatalf("importStatLine() problems = %q", problems)
+			}
+			got, _ := json.Marshal(line)
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Errorf("importStatLine() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
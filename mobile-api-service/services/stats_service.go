@@ -0,0 +1,538 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// statsCacheTTL bounds how long an unused cached stats page lingers; edits
+// invalidate it right away by bumping the version in its key
+const statsCacheTTL = time.Hour
+
+// countColumns are the game_stats columns summed into season totals
+var countColumns = []string{
+	"points", "rebounds", "assists", "steals", "blocks", "turnovers", "fouls",
+	"field_goals_made", "field_goals_attempted",
+	"three_pointers_made", "three_pointers_attempted",
+	"free_throws_made", "free_throws_attempted",
+}
+
+func sumCountColumns() string {
+	sums := make([]string, len(countColumns))
+	for i, column := range countColumns {
+		sums[i] = fmt.Sprintf("COALESCE(SUM(game_stats.%s), 0) AS %s", column, column)
+	}
+	return strings.Join(sums, ", ")
+}
+
+// refreshSeasonStats recomputes the season rows of the players on a team from
+// their game_stats. With no player IDs every player of the team is refreshed
+// and rows of players without games are removed.
+func refreshSeasonStats(tx *gorm.DB, teamID uint, playerIDs []uint) error {
+	var team models.Team
+	if err := tx.Unscoped().Select("id", "season_id", "sport_type").First(&team, teamID).Error; err != nil {
+		return err
+	}
+
+	query := tx.Table("game_stats").
+		Select("game_stats.player_id, COUNT(*) AS games_played, "+sumCountColumns()+", "+
+			"COALESCE(SUM(game_stats.minutes_played), 0) AS minutes_played, COUNT(game_stats.minutes_played) AS minutes_games, "+
+			"COALESCE(SUM(game_stats.plus_minus), 0) AS plus_minus, COUNT(game_stats.plus_minus) AS plus_minus_games").
+		Joins("JOIN events ON events.id = game_stats.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ?", teamID).
+		Group("game_stats.player_id")
+	if len(playerIDs) > 0 {
+		query = query.Where("game_stats.player_id IN ?", playerIDs)
+	}
+	var rows []models.PlayerSeasonStat
+	if err := query.Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	if len(playerIDs) == 0 {
+		stale := tx.Where("team_id = ?", teamID)
+		if len(rows) > 0 {
+			kept := make([]uint, len(rows))
+			for i, row := range rows {
+				kept[i] = row.PlayerID
+			}
+			stale = stale.Where("player_id NOT IN ?", kept)
+		}
+		if err := stale.Delete(&models.PlayerSeasonStat{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	var additional map[uint]map[string]float64
+	if definition, err := StatDefinitionFor(team.SportType); err == nil {
+		if additional, err = sumAdditionalStats(tx, definition, teamID, "game_stats.player_id", playerIDs); err != nil {
+			return err
+		}
+	}
+	for i := range rows {
+		rows[i].TeamID = teamID
+		rows[i].SeasonID = team.SeasonID
+		rows[i].Sport = NormalizeSport(team.SportType)
+		rows[i].AdditionalTotals = nil
+		if totals, ok := additional[rows[i].PlayerID]; ok {
+			rows[i].AdditionalTotals, _ = json.Marshal(totals)
+		}
+	}
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows).Error
+}
+
+// sumAdditionalStats totals the additional_stats of a team's games per value
+// of groupBy (a player or an event column)
+func sumAdditionalStats(tx *gorm.DB, definition *models.SportStatDefinition, teamID uint, groupBy string, playerIDs []uint) (map[uint]map[string]float64, error) {
+	type additionalRow struct {
+		GroupID         uint
+		AdditionalStats json.RawMessage
+	}
+	query := tx.Table("game_stats").
+		Select(groupBy+" AS group_id, game_stats.additional_stats").
+		Joins("JOIN events ON events.id = game_stats.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ? AND game_stats.additional_stats IS NOT NULL", teamID)
+	if len(playerIDs) > 0 {
+		query = query.Where("game_stats.player_id IN ?", playerIDs)
+	}
+	var rows []additionalRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]map[string]float64)
+	for _, row := range rows {
+		values := additionalValues(definition, row.AdditionalStats)
+		if len(values) == 0 {
+			continue
+		}
+		if totals[row.GroupID] == nil {
+			totals[row.GroupID] = make(map[string]float64)
+		}
+		for key, value := range values {
+			totals[row.GroupID][key] += value
+		}
+	}
+	return totals, nil
+}
+
+// RebuildTeamSeasonStats recomputes every season row of a team, for backfills
+// and after games are deleted
+func RebuildTeamSeasonStats(ctx context.Context, teamID uint) error {
+	var playerIDs []uint
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := refreshSeasonStats(tx, teamID, nil); err != nil {
+			return err
+		}
+		return tx.Model(&models.PlayerSeasonStat{}).Where("team_id = ?", teamID).Pluck("player_id", &playerIDs).Error
+	})
+	if err == nil {
+		InvalidateStatsCache(ctx, teamID, playerIDs)
+	}
+	return err
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func perGame(total, games int) float64 {
+	if games == 0 {
+		return 0
+	}
+	return round1(float64(total) / float64(games))
+}
+
+// summarize turns totals into averages and advanced metrics. Per-36 rates
+// need minutes in every game; plus/minus needs at least one tracked game.
+func summarize(games int, c models.GameStatCounts, minutes, minutesGames, plusMinus, plusMinusGames int) models.StatSummary {
+	s := models.StatSummary{GamesPlayed: games, Totals: c, MinutesPlayed: minutes}
+	s.Averages = models.StatAverages{
+		Points:        perGame(c.Points, games),
+		Rebounds:      perGame(c.Rebounds, games),
+		Assists:       perGame(c.Assists, games),
+		Steals:        perGame(c.Steals, games),
+		Blocks:        perGame(c.Blocks, games),
+		Turnovers:     perGame(c.Turnovers, games),
+		Fouls:         perGame(c.Fouls, games),
+		MinutesPlayed: perGame(minutes, minutesGames),
+	}
+
+	a := &s.Advanced
+	a.ShootingSplits = shootingSplits(c)
+	if c.FieldGoalsAttempted > 0 {
+		efg := round1((float64(c.FieldGoalsMade) + 0.5*float64(c.ThreePointersMade)) / float64(c.FieldGoalsAttempted) * 100)
+		a.EffectiveFieldGoalPct = &efg
+	}
+	if shots := 2 * (float64(c.FieldGoalsAttempted) + 0.44*float64(c.FreeThrowsAttempted)); shots > 0 {
+		ts := round1(float64(c.Points) / shots * 100)
+		a.TrueShootingPct = &ts
+	}
+	if c.Turnovers > 0 {
+		ratio := math.Round(float64(c.Assists)/float64(c.Turnovers)*100) / 100
+		a.AssistTurnoverRatio = &ratio
+	}
+	if minutes > 0 && minutesGames == games {
+		per36 := func(v int) float64 { return round1(float64(v) * 36 / float64(minutes)) }
+		a.Per36 = &models.Per36Stats{
+			Points:    per36(c.Points),
+			Rebounds:  per36(c.Rebounds),
+			Assists:   per36(c.Assists),
+			Steals:    per36(c.Steals),
+			Blocks:    per36(c.Blocks),
+			Turnovers: per36(c.Turnovers),
+		}
+	}
+	if plusMinusGames > 0 {
+		pm := plusMinus
+		pmPerGame := perGame(plusMinus, plusMinusGames)
+		a.PlusMinus = &pm
+		a.PlusMinusPerGame = &pmPerGame
+	}
+	return s
+}
+
+// summarizeSeason also summarizes the sport's additional fields when the
+// sport has a stat definition
+func summarizeSeason(row *models.PlayerSeasonStat) models.StatSummary {
+	summary := summarize(row.GamesPlayed, row.GameStatCounts, row.MinutesPlayed, row.MinutesGames, row.PlusMinus, row.PlusMinusGames)
+	if definition, err := StatDefinitionFor(row.Sport); err == nil {
+		var totals map[string]float64
+		if len(row.AdditionalTotals) > 0 {
+			json.Unmarshal(row.AdditionalTotals, &totals)
+		}
+		summary.SportStats = summarizeSportStats(definition, totals, row.GameStatCounts, row.GamesPlayed)
+	}
+	return summary
+}
+
+// sumSeasons adds up season rows of one sport, for careers
+func sumSeasons(rows []models.PlayerSeasonStat) models.PlayerSeasonStat {
+	var total models.PlayerSeasonStat
+	additional := map[string]float64{}
+	for _, row := range rows {
+		total.Sport = row.Sport
+		var totals map[string]float64
+		if len(row.AdditionalTotals) > 0 && json.Unmarshal(row.AdditionalTotals, &totals) == nil {
+			for key, value := range totals {
+				additional[key] += value
+			}
+		}
+		total.GamesPlayed += row.GamesPlayed
+		total.GameStatCounts.Add(row.GameStatCounts)
+		total.MinutesPlayed += row.MinutesPlayed
+		total.MinutesGames += row.MinutesGames
+		total.PlusMinus += row.PlusMinus
+		total.PlusMinusGames += row.PlusMinusGames
+	}
+	total.AdditionalTotals, _ = json.Marshal(additional)
+	return total
+}
+
+// careerLines sums a player's seasons per sport, in the order the sports were first played
+func careerLines(rows []models.PlayerSeasonStat) []models.CareerStatLine {
+	var sports []string
+	bySport := make(map[string][]models.PlayerSeasonStat)
+	for _, row := range rows {
+		sport := NormalizeSport(row.Sport)
+		if _, ok := bySport[sport]; !ok {
+			sports = append(sports, sport)
+		}
+		bySport[sport] = append(bySport[sport], row)
+	}
+
+	careers := make([]models.CareerStatLine, 0, len(sports))
+	for _, sport := range sports {
+		total := sumSeasons(bySport[sport])
+		total.Sport = sport
+		careers = append(careers, models.CareerStatLine{Sport: sport, StatSummary: summarizeSeason(&total)})
+	}
+	return careers
+}
+
+func statsVersionKey(scope string, id uint) string {
+	return fmt.Sprintf("stats:%s:%d:version", scope, id)
+}
+
+// statsCacheKey includes the scope's version so bumping it orphans every cached variant
+func statsCacheKey(ctx context.Context, scope string, id uint, variant string) string {
+	version, _ := database.RedisClient.Get(ctx, statsVersionKey(scope, id)).Int64()
+	return fmt.Sprintf("stats:%s:%d:v%d:%s", scope, id, version, variant)
+}
+
+func readStatsCache(ctx context.Context, key string, dest interface{}) bool {
+	data, err := database.RedisClient.Get(ctx, key).Bytes()
+	return err == nil && json.Unmarshal(data, dest) == nil
+}
+
+func writeStatsCache(ctx context.Context, key string, value interface{}) {
+	if data, err := json.Marshal(value); err == nil {
+		database.RedisClient.Set(ctx, key, data, statsCacheTTL)
+	}
+}
+
+// InvalidateStatsCache drops the cached stats of a team and its players
+func InvalidateStatsCache(ctx context.Context, teamID uint, playerIDs []uint) {
+	if database.RedisClient == nil {
+		return
+	}
+	pipe := database.RedisClient.Pipeline()
+	pipe.Incr(ctx, statsVersionKey("team", teamID))
+	for _, playerID := range playerIDs {
+		pipe.Incr(ctx, statsVersionKey("player", playerID))
+	}
+	pipe.Exec(ctx)
+}
+
+// seasonStatLines summarizes season rows with their team names
+func seasonStatLines(db *gorm.DB, rows []models.PlayerSeasonStat) ([]models.SeasonStatLine, error) {
+	var teams []models.Team
+	teamIDs := make([]uint, len(rows))
+	for i, row := range rows {
+		teamIDs[i] = row.TeamID
+	}
+	if len(teamIDs) > 0 {
+		if err := db.Unscoped().Select("id", "name").Where("id IN ?", teamIDs).Find(&teams).Error; err != nil {
+			return nil, err
+		}
+	}
+	teamNames := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+	}
+
+	lines := make([]models.SeasonStatLine, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		lines = append(lines, models.SeasonStatLine{
+			TeamID:      row.TeamID,
+			TeamName:    teamNames[row.TeamID],
+			SeasonID:    row.SeasonID,
+			Sport:       NormalizeSport(row.Sport),
+			StatSummary: summarizeSeason(row),
+		})
+	}
+	return lines, nil
+}
+
+// PlayerStatsFilter narrows GET /players/{playerId}/stats; Page and Limit page the game log
+type PlayerStatsFilter struct {
+	TeamID   uint
+	SeasonID uint
+	Page     int
+	Limit    int
+}
+
+// GetPlayerStats returns a player's game log, season lines and career totals
+func GetPlayerStats(ctx context.Context, playerID uint, filter PlayerStatsFilter) (*models.PlayerStats, error) {
+	var cacheKey string
+	if database.RedisClient != nil {
+		cacheKey = statsCacheKey(ctx, "player", playerID,
+			fmt.Sprintf("t%d:s%d:p%d:l%d", filter.TeamID, filter.SeasonID, filter.Page, filter.Limit))
+		var cached models.PlayerStats
+		if readStatsCache(ctx, cacheKey, &cached) {
+			return &cached, nil
+		}
+	}
+
+	db := database.DB.WithContext(ctx)
+	var rows []models.PlayerSeasonStat
+	if err := db.Where("player_id = ?", playerID).Order("season_id, team_id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	var selected []models.PlayerSeasonStat
+	for _, row := range rows {
+		if (filter.TeamID != 0 && row.TeamID != filter.TeamID) || (filter.SeasonID != 0 && row.SeasonID != filter.SeasonID) {
+			continue
+		}
+		selected = append(selected, row)
+	}
+	seasons, err := seasonStatLines(db, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PlayerStats{
+		PlayerID: playerID,
+		Seasons:  seasons,
+		Careers:  careerLines(rows),
+		Games:    []models.PlayerGameLine{},
+	}
+
+	scope := func() *gorm.DB {
+		query := db.Table("game_stats").
+			Joins("JOIN events ON events.id = game_stats.event_id AND events.deleted_at IS NULL").
+			Joins("JOIN teams ON teams.id = events.team_id").
+			Where("game_stats.player_id = ?", playerID)
+		if filter.TeamID != 0 {
+			query = query.Where("events.team_id = ?", filter.TeamID)
+		}
+		if filter.SeasonID != 0 {
+			query = query.Where("teams.season_id = ?", filter.SeasonID)
+		}
+		return query
+	}
+	if err := scope().Count(&stats.GamesTotal).Error; err != nil {
+		return nil, err
+	}
+	if err := scope().
+		Select("game_stats.*, events.team_id, events.title, events.opponent, events.start_time").
+		Order("events.start_time DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Scan(&stats.Games).Error; err != nil {
+		return nil, err
+	}
+	for i := range stats.Games {
+		stats.Games[i].ShootingSplits = shootingSplits(stats.Games[i].GameStatCounts)
+	}
+
+	if cacheKey != "" {
+		writeStatsCache(ctx, cacheKey, stats)
+	}
+	return stats, nil
+}
+
+// GetTeamStats returns a team's per-game totals, player season lines and
+// season summary. The team's plus/minus is its point differential over the
+// games tracked play by play.
+func GetTeamStats(ctx context.Context, teamID uint) (*models.TeamStats, error) {
+	var cacheKey string
+	if database.RedisClient != nil {
+		cacheKey = statsCacheKey(ctx, "team", teamID, "season")
+		var cached models.TeamStats
+		if readStatsCache(ctx, cacheKey, &cached) {
+			return &cached, nil
+		}
+	}
+
+	db := database.DB.WithContext(ctx)
+	var team models.Team
+	if err := db.Select("id", "season_id", "sport_type").First(&team, teamID).Error; err != nil {
+		return nil, err
+	}
+	definition, _ := StatDefinitionFor(team.SportType)
+	var gameAdditional map[uint]map[string]float64
+	if definition != nil {
+		var err error
+		if gameAdditional, err = sumAdditionalStats(db, definition, teamID, "game_stats.event_id", nil); err != nil {
+			return nil, err
+		}
+	}
+	stats := &models.TeamStats{
+		TeamID:   teamID,
+		SeasonID: team.SeasonID,
+		Sport:    NormalizeSport(team.SportType),
+		Players:  []models.TeamPlayerStatLine{},
+		Games:    []models.TeamGameLine{},
+	}
+
+	type gameTotals struct {
+		EventID   uint
+		Title     string
+		Opponent  string
+		StartTime time.Time
+		models.GameStatCounts
+	}
+	var games []gameTotals
+	if err := db.Table("game_stats").
+		Select("events.id AS event_id, events.title, events.opponent, events.start_time, "+sumCountColumns()).
+		Joins("JOIN events ON events.id = game_stats.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ?", teamID).
+		Group("events.id, events.title, events.opponent, events.start_time").
+		Order("events.start_time").
+		Scan(&games).Error; err != nil {
+		return nil, err
+	}
+
+	type opponentScore struct {
+		EventID uint
+		Points  int
+	}
+	var opponent []opponentScore
+	if err := db.Table("game_plays").
+		Select("game_plays.event_id, COALESCE(SUM(CASE WHEN game_plays.play_type = ? THEN game_plays.points ELSE 0 END), 0) AS points", models.PlayOpponentScore).
+		Joins("JOIN events ON events.id = game_plays.event_id AND events.deleted_at IS NULL").
+		Where("events.team_id = ? AND game_plays.voided = ? AND game_plays.duplicate_of IS NULL", teamID, false).
+		Group("game_plays.event_id").
+		Scan(&opponent).Error; err != nil {
+		return nil, err
+	}
+	opponentPoints := make(map[uint]int, len(opponent))
+	for _, o := range opponent {
+		opponentPoints[o.EventID] = o.Points
+	}
+
+	var season models.GameStatCounts
+	seasonAdditional := map[string]float64{}
+	differential, trackedGames := 0, 0
+	for _, game := range games {
+		line := models.TeamGameLine{
+			EventID:        game.EventID,
+			Title:          game.Title,
+			Opponent:       game.Opponent,
+			StartTime:      game.StartTime,
+			Totals:         game.GameStatCounts,
+			ShootingSplits: shootingSplits(game.GameStatCounts),
+		}
+		if points, ok := opponentPoints[game.EventID]; ok {
+			line.OpponentPoints = &points
+			differential += game.Points - points
+			trackedGames++
+		}
+		if totals, ok := gameAdditional[game.EventID]; ok {
+			line.SportTotals = totals
+			for key, value := range totals {
+				seasonAdditional[key] += value
+			}
+		}
+		season.Add(game.GameStatCounts)
+		stats.Games = append(stats.Games, line)
+	}
+	// Team minutes are not meaningful per 36, so they are left out
+	stats.Season = summarize(len(games), season, 0, 0, differential, trackedGames)
+	if definition != nil {
+		stats.Season.SportStats = summarizeSportStats(definition, seasonAdditional, season, len(games))
+	}
+
+	type playerRow struct {
+		models.PlayerSeasonStat
+		PlayerName   string
+		JerseyNumber *int
+	}
+	var players []playerRow
+	if err := db.Table("player_season_stats").
+		Select("player_season_stats.*, users.name AS player_name, team_members.jersey_number").
+		Joins("LEFT JOIN users ON users.id = player_season_stats.player_id").
+		Joins("LEFT JOIN team_members ON team_members.user_id = player_season_stats.player_id AND team_members.team_id = player_season_stats.team_id").
+		Where("player_season_stats.team_id = ?", teamID).
+		Order("player_season_stats.points DESC, users.name").
+		Scan(&players).Error; err != nil {
+		return nil, err
+	}
+	for i := range players {
+		stats.Players = append(stats.Players, models.TeamPlayerStatLine{
+			PlayerID:     players[i].PlayerID,
+			PlayerName:   players[i].PlayerName,
+			JerseyNumber: players[i].JerseyNumber,
+			StatSummary:  summarizeSeason(&players[i].PlayerSeasonStat),
+		})
+	}
+
+	if cacheKey != "" {
+		writeStatsCache(ctx, cacheKey, stats)
+	}
+	return stats, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestSummarize(t *testing.T) {
+	shooter := models.GameStatCounts{
+		Points: 25, Rebounds: 10, Assists: 6, Turnovers: 4,
+		FieldGoalsMade: 9, FieldGoalsAttempted: 20,
+		ThreePointersMade: 3, ThreePointersAttempted: 8,
+		FreeThrowsMade: 4, FreeThrowsAttempted: 5,
+	}
+
+	tests := []struct {
+		name           string
+		games          int
+		counts         models.GameStatCounts
+		minutes        int
+		minutesGames   int
+		plusMinus      int
+		plusMinusGames int
+		averages       models.StatAverages
+		advanced       models.AdvancedStats
+	}{
+		{
+			name:   "no games",
+			counts: models.GameStatCounts{},
+		},
+		{
+			name:           "minutes and plus/minus in every game",
+			games:          2,
+			counts:         shooter,
+			minutes:        60,
+			minutesGames:   2,
+			plusMinus:      7,
+			plusMinusGames: 2,
+			averages:       models.StatAverages{Points: 12.5, Rebounds: 5, Assists: 3, Turnovers: 2, MinutesPlayed: 30},
+			advanced: models.AdvancedStats{
+				// (9 + 0.5·3) / 20
+				EffectiveFieldGoalPct: floatPtr(52.5),
+				// 25 / (2·(20 + 0.44·5))
+				TrueShootingPct:     floatPtr(56.3),
+				AssistTurnoverRatio: floatPtr(1.5),
+				Per36:               &models.Per36Stats{Points: 15, Rebounds: 6, Assists: 3.6, Turnovers: 2.4},
+				PlusMinus:           intPtr(7),
+				PlusMinusPerGame:    floatPtr(3.5),
+			},
+		},
+		{
+			name:         "minutes missing in a game leave out per-36",
+			games:        3,
+			counts:       shooter,
+			minutes:      50,
+			minutesGames: 2,
+			averages:     models.StatAverages{Points: 8.3, Rebounds: 3.3, Assists: 2, Turnovers: 1.3, MinutesPlayed: 25},
+			advanced: models.AdvancedStats{
+				EffectiveFieldGoalPct: floatPtr(52.5),
+				TrueShootingPct:       floatPtr(56.3),
+				AssistTurnoverRatio:   floatPtr(1.5),
+			},
+		},
+		{
+			name:     "free throws only",
+			games:    1,
+			counts:   models.GameStatCounts{Points: 4, Assists: 1, FreeThrowsMade: 4, FreeThrowsAttempted: 6},
+			averages: models.StatAverages{Points: 4, Assists: 1},
+			advanced: models.AdvancedStats{
+				// 4 / (2·0.44·6)
+				TrueShootingPct: floatPtr(75.8),
+			},
+		},
+		{
+			name:           "plus/minus tracked in some games",
+			games:          4,
+			counts:         models.GameStatCounts{Points: 2, FieldGoalsMade: 1, FieldGoalsAttempted: 3, Turnovers: 3},
+			minutes:        45,
+			minutesGames:   4,
+			plusMinus:      -5,
+			plusMinusGames: 2,
+			averages:       models.StatAverages{Points: 0.5, Turnovers: 0.8, MinutesPlayed: 11.3},
+			advanced: models.AdvancedStats{
+				EffectiveFieldGoalPct: floatPtr(33.3),
+				TrueShootingPct:       floatPtr(33.3),
+				AssistTurnoverRatio:   floatPtr(0),
+				Per36:                 &models.Per36Stats{Points: 1.6, Turnovers: 2.4},
+				PlusMinus:             intPtr(-5),
+				PlusMinusPerGame:      floatPtr(-2.5),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarize(tt.games, tt.counts, tt.minutes, tt.minutesGames, tt.plusMinus, tt.plusMinusGames)
+			if got.GamesPlayed != tt.games || got.Totals != tt.counts || got.MinutesPlayed != tt.minutes {
+				t.Errorf("summarize() totals = %d games, %+v, %d minutes", got.GamesPlayed, got.Totals, got.MinutesPlayed)
+			}
+			if got.Averages != tt.averages {
+				t.Errorf("Averages = %+v, want %+v", got.Averages, tt.averages)
+			}
+			// The splits come from shootingSplits; the metrics summarize adds are compared in full
+			advanced := got.Advanced
+			if !reflect.DeepEqual(advanced.ShootingSplits, shootingSplits(tt.counts)) {
+				t.Errorf("ShootingSplits differ from shootingSplits()")
+			}
+			advanced.ShootingSplits = models.ShootingSplits{}
+			if !reflect.DeepEqual(advanced, tt.advanced) {
+				gotJSON, _ := json.Marshal(advanced)
+				wantJSON, _ := json.Marshal(tt.advanced)
+				t.Errorf("Advanced = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+)
+
+var ErrTemplateNotFound = errors.New("notification template not found")
+
+// placeholderPattern matches {{variable_name}} with optional inner whitespace
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// bracePattern matches anything in double braces, including placeholders
+// that placeholderPattern cannot substitute such as {{player.name}}
+var bracePattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// TemplateVariableError reports placeholders that were not supplied,
+// variables that the template does not use and placeholders that cannot be
+// substituted and would be left in the output
+type TemplateVariableError struct {
+	Template  string
+	Missing   []string
+	Extra     []string
+	Malformed []string
+}
+
+func (e *TemplateVariableError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing variables: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		parts = append(parts, "unexpected variables: "+strings.Join(e.Extra, ", "))
+	}
+	if len(e.Malformed) > 0 {
+		parts = append(parts, "malformed placeholders: "+strings.Join(e.Malformed, ", "))
+	}
+	return fmt.Sprintf("template %q: %s", e.Template, strings.Join(parts, "; "))
+}
+
+// RenderedTemplate is a template with every placeholder substituted
+type RenderedTemplate struct {
+	TemplateID uint   `json:"template_id"`
+	Type       string `json:"type"`
+	Locale     string `json:"locale"`
+	Subject    string `json:"subject"`
+	Body       string `json:"body"`
+}
+
+// LocaleFallbackChain expands a locale into the lookup order used for
+// templates, e.g. "es-MX" -> ["es-mx", "es", "en"]
+func LocaleFallbackChain(locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+
+	var chain []string
+	seen := map[string]bool{}
+	add := func(l string) {
+		if l != "" && !seen[l] {
+			seen[l] = true
+			chain = append(chain, l)
+		}
+	}
+
+	for l := locale; l != ""; {
+		add(l)
+		i := strings.LastIndex(l, "-")
+		if i < 0 {
+			break
+		}
+		l = l[:i]
+	}
+	add(models.DefaultLocale)
+	return chain
+}
+
+// TemplateVariables returns the distinct placeholder names used by the template's subject and body
+func TemplateVariables(template *models.NotificationTemplate) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, text := range []string{template.Subject, template.BodyTemplate} {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTemplate returns the active template of the given type that best matches the locale
+func LoadTemplate(ctx context.Context, templateType, locale string) (*models.NotificationTemplate, error) {
+	chain := LocaleFallbackChain(locale)
+
+	var templates []models.NotificationTemplate
+	err := database.DB.WithContext(ctx).
+		Where("type = ? AND is_active = ? AND LOWER(locale) IN ?", templateType, true, chain).
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range chain {
+		for i := range templates {
+			if strings.EqualFold(templates[i].Locale, l) {
+				return &templates[i], nil
+			}
+		}
+	}
+	return nil, ErrTemplateNotFound
+}
+
+// RenderTemplate substitutes vars into the template. Every placeholder must be
+// supplied, every supplied variable must be used and no placeholder may be
+// left unsubstituted, otherwise a *TemplateVariableError is returned and
+// nothing is rendered.
+func RenderTemplate(template *models.NotificationTemplate, vars map[string]string) (*RenderedTemplate, error) {
+	required := TemplateVariables(template)
+
+	varErr := &TemplateVariableError{Template: template.Name}
+	used := map[string]bool{}
+	for _, name := range required {
+		used[name] = true
+		if _, ok := vars[name]; !ok {
+			varErr.Missing = append(varErr.Missing, name)
+		}
+	}
+	for name := range vars {
+		if !used[name] {
+			varErr.Extra = append(varErr.Extra, name)
+		}
+	}
+	for _, text := range []string{template.Subject, template.BodyTemplate} {
+		for _, braces := range bracePattern.FindAllString(text, -1) {
+			if !placeholderPattern.MatchString(braces) {
+				varErr.Malformed = append(varErr.Malformed, braces)
+			}
+		}
+	}
+	if len(varErr.Missing) > 0 || len(varErr.Extra) > 0 || len(varErr.Malformed) > 0 {
+		sort.Strings(varErr.Extra)
+		return nil, varErr
+	}
+
+	substitute := func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			return vars[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+		})
+	}
+
+	return &RenderedTemplate{
+		TemplateID: template.ID,
+		Type:       template.Type,
+		Locale:     template.Locale,
+		Subject:    substitute(template.Subject),
+		Body:       substitute(template.BodyTemplate),
+	}, nil
+}
+
+// RenderNotification loads the best template for the type and locale and renders it
+func RenderNotification(ctx context.Context, templateType, locale string, vars map[string]string) (*RenderedTemplate, error) {
+	template, err := LoadTemplate(ctx, templateType, locale)
+	if err != nil {
+		return nil, err
+	}
+	return RenderTemplate(template, vars)
+}
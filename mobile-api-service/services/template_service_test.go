@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	template := &models.NotificationTemplate{
+		Name:         "Game Day Published",
+		Type:         models.NotificationTypeGameDayPublished,
+		Locale:       "en",
+		Subject:      "Game day: {{event_title}}",
+		BodyTemplate: "Plan for {{ event_title }} is out. Report at {{reporting_time}}.",
+	}
+
+	tests := []struct {
+		name      string
+		template  *models.NotificationTemplate
+		vars      map[string]string
+		subject   string
+		body      string
+		missing   []string
+		extra     []string
+		malformed []string
+	}{
+		{
+			name:     "all variables",
+			template: template,
+			vars:     map[string]string{"event_title": "Finals", "reporting_time": "5pm"},
+			subject:  "Game day: Finals",
+			body:     "Plan for Finals is out. Report at 5pm.",
+		},
+		{
+			name:     "values are not substituted again",
+			template: template,
+			vars:     map[string]string{"event_title": "{{reporting_time}}", "reporting_time": "5pm"},
+			subject:  "Game day: {{reporting_time}}",
+			body:     "Plan for {{reporting_time}} is out. Report at 5pm.",
+		},
+		{
+			name:     "missing variable",
+			template: template,
+			vars:     map[string]string{"event_title": "Finals"},
+			missing:  []string{"reporting_time"},
+		},
+		{
+			name:     "extra variables",
+			template: template,
+			vars:     map[string]string{"event_title": "Finals", "reporting_time": "5pm", "venue": "Gym", "coach": "Lee"},
+			extra:    []string{"coach", "venue"},
+		},
+		{
+			name:     "missing and extra",
+			template: template,
+			vars:     map[string]string{"reporting_time": "5pm", "title": "Finals"},
+			missing:  []string{"event_title"},
+			extra:    []string{"title"},
+		},
+		{
+			name: "placeholder that cannot be substituted",
+			template: &models.NotificationTemplate{
+				Name:         "Broken",
+				Subject:      "Hi {{player.name}}",
+				BodyTemplate: "{{ goal title }} is due {{target_date}}",
+			},
+			vars:      map[string]string{"target_date": "May 1"},
+			malformed: []string{"{{player.name}}", "{{ goal title }}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := RenderTemplate(tt.template, tt.vars)
+			if tt.missing != nil || tt.extra != nil || tt.malformed != nil {
+				var varErr *TemplateVariableError
+				if !errors.As(err, &varErr) {
+					t.Fatalf("RenderTemplate() error = %v, want *TemplateVariableError", err)
+				}
+				if rendered != nil {
+					t.Errorf("RenderTemplate() rendered %+v alongside an error", rendered)
+				}
+				if !reflect.DeepEqual(varErr.Missing, tt.missing) {
+					t.Errorf("Missing = %v, want %v", varErr.Missing, tt.missing)
+				}
+				if !reflect.DeepEqual(varErr.Extra, tt.extra) {
+					t.Errorf("Extra = %v, want %v", varErr.Extra, tt.extra)
+				}
+				if !reflect.DeepEqual(varErr.Malformed, tt.malformed) {
+					t.Errorf("Malformed = %v, want %v", varErr.Malformed, tt.malformed)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			if rendered.Subject != tt.subject {
+				t.Errorf("Subject = %q, want %q", rendered.Subject, tt.subject)
+			}
+			if rendered.Body != tt.body {
+				t.Errorf("Body = %q, want %q", rendered.Body, tt.body)
+			}
+		})
+	}
+}
+
+// TestSeededTemplatesRender renders every seeded template with exactly its
+// own variables, so no placeholder can be left in the output
+func TestSeededTemplatesRender(t *testing.T) {
+	templates := []models.NotificationTemplate{
+		{Name: "Schedule Change", Subject: "Schedule Change", BodyTemplate: "{{event_title}} has been {{change_type}}. New time: {{new_time}}"},
+		{Name: "Goal Comment", Subject: "New Comment on Your Goal", BodyTemplate: `{{coach_name}} commented on "{{goal_title}}": {{comment}}`},
+	}
+	for _, template := range templates {
+		vars := map[string]string{}
+		for _, name := range TemplateVariables(&template) {
+			vars[name] = "value"
+		}
+		rendered, err := RenderTemplate(&template, vars)
+		if err != nil {
+			t.Fatalf("%s: RenderTemplate() error = %v", template.Name, err)
+		}
+		if strings.Contains(rendered.Subject+rendered.Body, "{{") {
+			t.Errorf("%s: placeholder left in %q", template.Name, rendered.Body)
+		}
+	}
+}
+
+func TestLocaleFallbackChain(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"es-MX", []string{"es-mx", "es", "en"}},
+		{"es_mx", []string{"es-mx", "es", "en"}},
+		{" zh-Hant-TW ", []string{"zh-hant-tw", "zh-hant", "zh", "en"}},
+		{"fr", []string{"fr", "en"}},
+		{"en-GB", []string{"en-gb", "en"}},
+		{"en", []string{"en"}},
+		{"", []string{"en"}},
+	}
+	for _, tt := range tests {
+		if got := LocaleFallbackChain(tt.locale); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LocaleFallbackChain(%q) = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/storage"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidVideoGrant = errors.New("invalid video grant")
+
+// VideoViewer is who a video is being shown to
+type VideoViewer struct {
+	UserID     uint
+	SuperAdmin bool
+	// AdminOrgIDs are the organizations the viewer administers
+	AdminOrgIDs []uint
+}
+
+// VisibleVideos restricts a query joined on videos to the videos the viewer
+// may watch. Coaches of the video's team and admins of its organization see
+// everything; everyone else needs a grant in video_permissions:
+//   - public: any signed-in user
+//   - team: active roster members of the video's team and the approved parents of its players
+//   - player / parent: the granted user
+func VisibleVideos(db *gorm.DB, viewer VideoViewer) *gorm.DB {
+	if viewer.SuperAdmin {
+		return db
+	}
+
+	coachTeams := database.DB.Model(&models.TeamMember{}).Select("team_id").
+		Where("user_id = ? AND member_type = ? AND status = ?", viewer.UserID, models.MemberTypeCoach, models.MemberStatusActive)
+	memberTeams := database.DB.Model(&models.TeamMember{}).Select("team_id").
+		Where("user_id = ? AND status = ?", viewer.UserID, models.MemberStatusActive)
+	childTeams := database.DB.Model(&models.TeamMember{}).Select("team_members.team_id").
+		Joins("JOIN parent_players ON parent_players.player_id = team_members.user_id").
+		Where("parent_players.parent_id = ? AND parent_players.status = ?", viewer.UserID, models.ParentLinkApproved).
+		Where("team_members.member_type = ? AND team_members.status = ?", models.MemberTypePlayer, models.MemberStatusActive)
+
+	granted := database.DB.Model(&models.VideoPermission{}).Select("1").
+		Where("video_permissions.video_id = videos.id").
+		Where(database.DB.Where("video_permissions.permission_type = ?", models.VideoPermissionPublic).
+			Or("video_permissions.permission_type = ? AND (videos.team_id IN (?) OR videos.team_id IN (?))",
+				models.VideoPermissionTeam, memberTeams, childTeams).
+			Or("video_permissions.permission_type IN (?, ?) AND video_permissions.user_id = ?",
+				models.VideoPermissionPlayer, models.VideoPermissionParent, viewer.UserID))
+
+	visible := database.DB.Where("videos.team_id IN (?)", coachTeams).Or("EXISTS (?)", granted)
+	if len(viewer.AdminOrgIDs) > 0 {
+		orgTeams := database.DB.Model(&models.Team{}).Select("id").Where("organization_id IN ?", viewer.AdminOrgIDs)
+		visible = visible.Or("videos.team_id IN (?)", orgTeams)
+	}
+	return db.Where(visible)
+}
+
+// CanViewVideo reports whether the viewer may watch the video
+func CanViewVideo(ctx context.Context, viewer VideoViewer, videoID uint) bool {
+	var count int64
+	VisibleVideos(database.DB.WithContext(ctx).Model(&models.Video{}), viewer).
+		Where("videos.id = ?", videoID).
+		Count(&count)
+	return count > 0
+}
+
+// PlaybackURLTTL is how long signed media URLs stay valid, from PLAYBACK_URL_TTL_MINUTES
+func PlaybackURLTTL() time.Duration {
+	minutes, err := strconv.Atoi(config.AppConfig.PlaybackURLTTL)
+	if err != nil || minutes < 1 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// SignMediaURL turns a stored media URL into a signed, expiring one. URLs that
+// do not belong to the storage backend are returned unchanged.
+func SignMediaURL(ctx context.Context, rawURL string) string {
+	key, ok := storage.Default.ObjectKey(rawURL)
+	if !ok {
+		return rawURL
+	}
+	signed, err := storage.Default.SignedURL(ctx, key, PlaybackURLTTL())
+	if err != nil {
+		return ""
+	}
+	return signed
+}
+
+// SignVideoURLs replaces the storage and thumbnail URLs of a video about to be
+// returned with signed ones and adds its HLS playlist URL. The video must not
+// be saved afterwards.
+func SignVideoURLs(ctx context.Context, video *models.Video) {
+	expires := time.Now().Add(PlaybackURLTTL())
+	video.StorageURL = SignMediaURL(ctx, video.StorageURL)
+	video.ThumbnailURL = SignMediaURL(ctx, video.ThumbnailURL)
+	video.URLExpiresAt = &expires
+	if video.ProcessingStatus == models.VideoStatusCompleted {
+		video.HLSURL = VideoHLSURL(video.ID, expires)
+	}
+}
+
+// SignClipURLs signs the media URLs of player clips
+func SignClipURLs(ctx context.Context, clips []models.PlayerClip) {
+	expires := time.Now().Add(PlaybackURLTTL())
+	for i := range clips {
+		clips[i].StorageURL = SignMediaURL(ctx, clips[i].StorageURL)
+		clips[i].ThumbnailURL = SignMediaURL(ctx, clips[i].ThumbnailURL)
+		clips[i].URLExpiresAt = &expires
+		clips[i].HLSURL = ClipHLSURL(clips[i].ID, expires)
+	}
+}
+
+// SignTagURLs sets the clip playlist URL of each tag
+func SignTagURLs(tags []models.VideoTag) {
+	expires := time.Now().Add(PlaybackURLTTL())
+	for i := range tags {
+		tags[i].HLSURL = ClipHLSURL(tags[i].ID, expires)
+	}
+}
+
+// validateGrant checks that a player or parent grant names someone on the video's team
+func validateGrant(ctx context.Context, teamID uint, grant models.VideoGrant) error {
+	switch grant.PermissionType {
+	case models.VideoPermissionPublic, models.VideoPermissionTeam:
+		if grant.UserID != nil {
+			return ErrInvalidVideoGrant
+		}
+		return nil
+	case models.VideoPermissionPlayer:
+		if grant.UserID == nil || !IsTeamPlayer(ctx, *grant.UserID, teamID) {
+			return ErrInvalidVideoGrant
+		}
+		return nil
+	case models.VideoPermissionParent:
+		if grant.UserID == nil {
+			return ErrInvalidVideoGrant
+		}
+		players, err := TeamPlayerIDs(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		for _, playerID := range players {
+			if IsParentOf(ctx, *grant.UserID, playerID) {
+				return nil
+			}
+		}
+		return ErrInvalidVideoGrant
+	}
+	return ErrInvalidVideoGrant
+}
+
+func grantQuery(tx *gorm.DB, videoID uint, grant models.VideoGrant) *gorm.DB {
+	query := tx.Model(&models.VideoPermission{}).
+		Where("video_id = ? AND permission_type = ?", videoID, grant.PermissionType)
+	if grant.UserID == nil {
+		return query.Where("user_id IS NULL")
+	}
+	return query.Where("user_id = ?", *grant.UserID)
+}
+
+// GrantVideoAccess adds every grant to every video, skipping grants that
+// already exist. It returns the number of grants created.
+func GrantVideoAccess(ctx context.Context, videos []models.Video, grants []models.VideoGrant, grantedBy uint) (int, error) {
+	for _, video := range videos {
+		for _, grant := range grants {
+			if err := validateGrant(ctx, video.TeamID, grant); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	created := 0
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, video := range videos {
+			for _, grant := range grants {
+				var count int64
+				if err := grantQuery(tx, video.ID, grant).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					continue
+				}
+				permission := &models.VideoPermission{
+					VideoID:        video.ID,
+					UserID:         grant.UserID,
+					PermissionType: grant.PermissionType,
+					GrantedBy:      grantedBy,
+				}
+				if err := tx.Create(permission).Error; err != nil {
+					return err
+				}
+				created++
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+// RevokeVideoAccess removes every grant from every video and returns the number removed
+func RevokeVideoAccess(ctx context.Context, videoIDs []uint, grants []models.VideoGrant) (int64, error) {
+	var removed int64
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, videoID := range videoIDs {
+			for _, grant := range grants {
+				result := grantQuery(tx, videoID, grant).Delete(&models.VideoPermission{})
+				if result.Error != nil {
+					return result.Error
+				}
+				removed += result.RowsAffected
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// ListVideoPermissions returns the grants of a video
+func ListVideoPermissions(ctx context.Context, videoID uint) ([]models.VideoPermission, error) {
+	var permissions []models.VideoPermission
+	err := database.DB.WithContext(ctx).Where("video_id = ?", videoID).Order("id").Find(&permissions).Error
+	return permissions, err
+}
+
+// ListTeamVideos returns the team's videos the viewer may watch, newest first
+func ListTeamVideos(ctx context.Context, viewer VideoViewer, teamID uint, page, limit int) ([]models.Video, int64, error) {
+	scope := func() *gorm.DB {
+		return VisibleVideos(database.DB.WithContext(ctx).Model(&models.Video{}).Where("videos.team_id = ?", teamID), viewer)
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var videos []models.Video
+	err := scope().Order("videos.uploaded_at DESC, videos.id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&videos).Error
+	return videos, total, err
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/storage"
+)
+
+// thumbnailWidth is the width thumbnails are scaled to, keeping the aspect ratio
+const thumbnailWidth = 640
+
+// ProgressFunc reports the stage a processing job is in and its overall progress (0-100)
+type ProgressFunc func(stage string, progress uint8)
+
+// VideoStreamInfo is the per-stream part of the extracted metadata
+type VideoStreamInfo struct {
+	Type      string `json:"type"`
+	Codec     string `json:"codec"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	FrameRate string `json:"frame_rate,omitempty"`
+	Channels  int    `json:"channels,omitempty"`
+}
+
+// VideoMetadata is stored in videos.metadata
+type VideoMetadata struct {
+	Format   string            `json:"format"`
+	Duration float64           `json:"duration"`
+	BitRate  int64             `json:"bit_rate"`
+	Streams  []VideoStreamInfo `json:"streams"`
+}
+
+type ffprobeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		Channels     int    `json:"channels"`
+	} `json:"streams"`
+}
+
+// HasVideoStream reports whether the file contains a picture track
+func (m *VideoMetadata) HasVideoStream() bool {
+	for _, s := range m.Streams {
+		if s.Type == "video" {
+			return true
+		}
+	}
+	return false
+}
+
+func runTool(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// The tool ran and rejected the file; retrying will not help
+			return nil, Permanent(fmt.Errorf("%s: %v: %s", filepath.Base(name), err, bytes.TrimSpace(stderr.Bytes())))
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// ProbeVideo extracts duration, container and stream details with ffprobe
+func ProbeVideo(ctx context.Context, file string) (*VideoMetadata, error) {
+	out, err := runTool(ctx, config.AppConfig.FFprobePath,
+		"-v", "error", "-print_format", "json", "-show_format", "-show_streams", file)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, Permanent(fmt.Errorf("unreadable ffprobe output: %w", err))
+	}
+
+	meta := &VideoMetadata{Format: probe.Format.FormatName}
+	meta.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	meta.BitRate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+	for _, s := range probe.Streams {
+		meta.Streams = append(meta.Streams, VideoStreamInfo{
+			Type:      s.CodecType,
+			Codec:     s.CodecName,
+			Width:     s.Width,
+			Height:    s.Height,
+			FrameRate: s.AvgFrameRate,
+			Channels:  s.Channels,
+		})
+	}
+	return meta, nil
+}
+
+// GenerateThumbnail grabs one frame at the given second as a JPEG
+func GenerateThumbnail(ctx context.Context, file, out string, at float64) error {
+	_, err := runTool(ctx, config.AppConfig.FFmpegPath,
+		"-v", "error", "-y",
+		"-ss", strconv.FormatFloat(at, 'f', 2, 64),
+		"-i", file,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=%d:-2", thumbnailWidth),
+		out)
+	return err
+}
+
+// downloadObject copies a stored object into a local temp file for the tools to read
+func downloadObject(ctx context.Context, objectKey, dir string) (string, error) {
+	src, err := storage.Default.Open(ctx, objectKey)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	local := filepath.Join(dir, "source"+path.Ext(objectKey))
+	dst, err := os.Create(local)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", err
+	}
+	return local, nil
+}
+
+// ProcessVideo extracts the metadata of an uploaded video, stores its
+// thumbnail and HLS renditions and marks it completed
+func ProcessVideo(ctx context.Context, videoID uint, report ProgressFunc) error {
+	var video models.Video
+	if err := database.DB.WithContext(ctx).First(&video, videoID).Error; err != nil {
+		return Permanent(err)
+	}
+	upload, err := GetVideoUpload(ctx, videoID)
+	if err != nil {
+		return Permanent(err)
+	}
+	if upload.CompletedAt == nil {
+		return Permanent(errors.New("upload is not finished"))
+	}
+
+	dir, err := os.MkdirTemp("", fmt.Sprintf("video-%d-*", videoID))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	report("download", 10)
+	source, err := downloadObject(ctx, upload.ObjectKey, dir)
+	if errors.Is(err, storage.ErrNotFound) {
+		return Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	report("probe", 25)
+	meta, err := ProbeVideo(ctx, source)
+	if err != nil {
+		return err
+	}
+	if !meta.HasVideoStream() {
+		return Permanent(errors.New("file has no video stream"))
+	}
+	metaJSON, _ := json.Marshal(meta)
+	duration := int(math.Round(meta.Duration))
+	durationMs := int64(math.Round(meta.Duration * 1000))
+
+	report("thumbnail", 35)
+	thumb := filepath.Join(dir, "thumbnail.jpg")
+	if err := GenerateThumbnail(ctx, source, thumb, meta.Duration/10); err != nil {
+		return err
+	}
+	thumbKey := path.Join(path.Dir(upload.ObjectKey), "thumbnail.jpg")
+	if err := putFile(ctx, thumb, thumbKey, "image/jpeg"); err != nil {
+		return err
+	}
+
+	report("hls", 40)
+	hlsPrefix := path.Join(path.Dir(upload.ObjectKey), "hls")
+	err = PackageHLS(ctx, videoID, source, dir, hlsPrefix, meta, func(done float64) {
+		report("hls", 40+uint8(done*50))
+	})
+	if err != nil {
+		return err
+	}
+
+	report("finalize", 95)
+	return database.DB.WithContext(ctx).Model(&video).Updates(map[string]interface{}{
+		"duration":          duration,
+		"duration_ms":       durationMs,
+		"metadata":          metaJSON,
+		"thumbnail_url":     storage.Default.URL(thumbKey),
+		"processing_status": models.VideoStatusCompleted,
+	}).Error
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	videoJobStream = "videos:jobs"
+	videoJobGroup  = "video-workers"
+	// videoJobRetryKey is a ZSET of job IDs scored by when the next attempt is due
+	videoJobRetryKey = "videos:jobs:retry"
+	// videoJobDeadKey is the dead-letter list of job IDs that ran out of attempts
+	videoJobDeadKey = "videos:jobs:dead"
+
+	videoJobRetryBaseDelay = 30 * time.Second
+	// videoJobClaimIdle is how long a job may sit unacknowledged before another
+	// worker assumes its consumer died and takes it over
+	videoJobClaimIdle = 15 * time.Minute
+	// videoJobLease is how long a running job stays reserved for its worker
+	// without a heartbeat
+	videoJobLease = 2 * time.Minute
+	// videoJobHeartbeat is how often a running job renews its lease and resets
+	// the idle time of its stream entry
+	videoJobHeartbeat = 30 * time.Second
+)
+
+var (
+	ErrVideoJobNotFound = errors.New("video processing job not found")
+	ErrVideoJobNotDead  = errors.New("only dead-lettered jobs can be retried")
+)
+
+func videoJobMaxAttempts() int {
+	attempts, err := strconv.Atoi(config.AppConfig.VideoJobMaxAttempts)
+	if err != nil || attempts < 1 {
+		return 3
+	}
+	return attempts
+}
+
+// EnqueueVideoProcessing records a queued job for the video and publishes it
+// on the Redis stream
+func EnqueueVideoProcessing(ctx context.Context, videoID uint) (*models.VideoProcessingJob, error) {
+	job := &models.VideoProcessingJob{
+		VideoID: videoID,
+		Status:  models.VideoJobQueued,
+		Stage:   "queued",
+	}
+	if err := database.DB.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	if database.RedisClient == nil {
+		go handleVideoJob(context.Background(), job.ID)
+		return job, nil
+	}
+	if err := publishVideoJob(ctx, job.ID); err != nil {
+		// Without its row the video is picked up again by requeueOrphanedUploads
+		database.DB.Delete(job)
+		return nil, err
+	}
+	return job, nil
+}
+
+func publishVideoJob(ctx context.Context, jobID uint) error {
+	return database.RedisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: videoJobStream,
+		Values: map[string]interface{}{"job_id": jobID},
+	}).Err()
+}
+
+// GetLatestVideoJob returns the most recent processing job of a video
+func GetLatestVideoJob(ctx context.Context, videoID uint) (*models.VideoProcessingJob, error) {
+	var job models.VideoProcessingJob
+	err := database.DB.WithContext(ctx).Where("video_id = ?", videoID).Order("id DESC").First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVideoJobNotFound
+	}
+	return &job, err
+}
+
+// RetryDeadVideoJob moves a dead-lettered job back onto the queue with fresh attempts
+func RetryDeadVideoJob(ctx context.Context, jobID uint) (*models.VideoProcessingJob, error) {
+	var job models.VideoProcessingJob
+	if err := database.DB.WithContext(ctx).First(&job, jobID).Error; err != nil {
+		return nil, ErrVideoJobNotFound
+	}
+	if job.Status != models.VideoJobDead {
+		return nil, ErrVideoJobNotDead
+	}
+
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&job).Updates(map[string]interface{}{
+			"status":      models.VideoJobQueued,
+			"stage":       "queued",
+			"progress":    0,
+			"attempts":    0,
+			"finished_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Video{}).Where("id = ?", job.VideoID).
+			Update("processing_status", models.VideoStatusPending).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if database.RedisClient == nil {
+		go handleVideoJob(context.Background(), job.ID)
+		return &job, nil
+	}
+	database.RedisClient.LRem(ctx, videoJobDeadKey, 0, strconv.FormatUint(uint64(job.ID), 10))
+	return &job, publishVideoJob(ctx, job.ID)
+}
+
+// handleVideoJob runs one attempt of a job and decides between success, a
+// delayed retry and the dead-letter list
+func handleVideoJob(ctx context.Context, jobID uint) {
+	// The conditional transition to running is the lease: a job that another
+	// worker is running is only taken over once that worker stopped renewing it
+	now := time.Now()
+	start := database.DB.WithContext(ctx).Model(&models.VideoProcessingJob{}).
+		Where("id = ?", jobID).
+		Where("status IN (?, ?) OR (status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?))",
+			models.VideoJobQueued, models.VideoJobRetrying, models.VideoJobRunning, now).
+		Updates(map[string]interface{}{
+			"status":           models.VideoJobRunning,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"started_at":       now,
+			"lease_expires_at": now.Add(videoJobLease),
+		})
+	if start.Error != nil {
+		log.Printf("Video job %d skipped: %v", jobID, start.Error)
+		return
+	}
+	if start.RowsAffected == 0 {
+		log.Printf("Video job %d skipped: finished or running elsewhere", jobID)
+		return
+	}
+
+	var job models.VideoProcessingJob
+	if err := database.DB.WithContext(ctx).First(&job, jobID).Error; err != nil {
+		log.Printf("Video job %d skipped: %v", jobID, err)
+		return
+	}
+	database.DB.WithContext(ctx).Model(&models.Video{}).Where("id = ?", job.VideoID).
+		Update("processing_status", models.VideoStatusProcessing)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(videoJobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				database.DB.WithContext(ctx).Model(&models.VideoProcessingJob{}).
+					Where("id = ? AND status = ?", job.ID, models.VideoJobRunning).
+					Update("lease_expires_at", time.Now().Add(videoJobLease))
+			}
+		}
+	}()
+
+	report := func(stage string, progress uint8) {
+		database.DB.WithContext(ctx).Model(&job).Updates(map[string]interface{}{
+			"stage":    stage,
+			"progress": progress,
+		})
+	}
+
+	err := ProcessVideo(ctx, job.VideoID, report)
+	finished := time.Now()
+	if err == nil {
+		database.DB.WithContext(ctx).Model(&job).Updates(map[string]interface{}{
+			"status":      models.VideoJobSucceeded,
+			"stage":       "done",
+			"progress":    100,
+			"last_error":  "",
+			"finished_at": finished,
+		})
+		return
+	}
+
+	log.Printf("Video job %d attempt %d failed: %v", job.ID, job.Attempts, err)
+	if job.Attempts < videoJobMaxAttempts() && !isPermanent(err) && database.RedisClient != nil {
+		delay := videoJobRetryBaseDelay << uint(job.Attempts-1)
+		database.DB.WithContext(ctx).Model(&job).Updates(map[string]interface{}{
+			"status":     models.VideoJobRetrying,
+			"last_error": err.Error(),
+		})
+		database.RedisClient.ZAdd(ctx, videoJobRetryKey, &redis.Z{
+			Score:  float64(time.Now().Add(delay).Unix()),
+			Member: strconv.FormatUint(uint64(job.ID), 10),
+		})
+		return
+	}
+
+	database.DB.WithContext(ctx).Model(&job).Updates(map[string]interface{}{
+		"status":      models.VideoJobDead,
+		"last_error":  err.Error(),
+		"finished_at": finished,
+	})
+	database.DB.WithContext(ctx).Model(&models.Video{}).Where("id = ?", job.VideoID).
+		Update("processing_status", models.VideoStatusFailed)
+	if database.RedisClient != nil {
+		database.RedisClient.LPush(ctx, videoJobDeadKey, strconv.FormatUint(uint64(job.ID), 10))
+	}
+}
+
+// handleVideoMessage processes one stream entry and acknowledges it. While
+// the job runs the consumer re-claims the entry, which resets its idle time
+// so reclaimStaleVideoJobs never mistakes a long job for a crashed one.
+func handleVideoMessage(ctx context.Context, consumer string, msg redis.XMessage) {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(videoJobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				database.RedisClient.XClaimJustID(ctx, &redis.XClaimArgs{
+					Stream:   videoJobStream,
+					Group:    videoJobGroup,
+					Consumer: consumer,
+					Messages: []string{msg.ID},
+				})
+			}
+		}
+	}()
+
+	if raw, ok := msg.Values["job_id"].(string); ok {
+		if jobID, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			handleVideoJob(ctx, uint(jobID))
+		}
+	}
+	close(stop)
+	database.RedisClient.XAck(ctx, videoJobStream, videoJobGroup, msg.ID)
+	database.RedisClient.XDel(ctx, videoJobStream, msg.ID)
+}
+
+func ensureVideoJobGroup(ctx context.Context) error {
+	err := database.RedisClient.XGroupCreateMkStream(ctx, videoJobStream, videoJobGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// promoteVideoRetries re-publishes retries whose backoff has elapsed
+func promoteVideoRetries(ctx context.Context, now time.Time) error {
+	due, err := database.RedisClient.ZRangeByScore(ctx, videoJobRetryKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for _, member := range due {
+		// ZREM is the claim, as with schedule changes
+		if removed, err := database.RedisClient.ZRem(ctx, videoJobRetryKey, member).Result(); err != nil || removed == 0 {
+			continue
+		}
+		if jobID, err := strconv.ParseUint(member, 10, 64); err == nil {
+			publishVideoJob(ctx, uint(jobID))
+		}
+	}
+	return nil
+}
+
+// requeueOrphanedUploads queues finished uploads whose job was never published
+func requeueOrphanedUploads(ctx context.Context, now time.Time) error {
+	var videoIDs []uint
+	err := database.DB.WithContext(ctx).Model(&models.VideoUpload{}).
+		Joins("JOIN videos ON videos.id = video_uploads.video_id AND videos.deleted_at IS NULL").
+		Where("video_uploads.completed_at < ? AND videos.processing_status = ?", now.Add(-time.Minute), models.VideoStatusPending).
+		Where("NOT EXISTS (SELECT 1 FROM video_processing_jobs j WHERE j.video_id = video_uploads.video_id)").
+		Pluck("video_uploads.video_id", &videoIDs).Error
+	if err != nil {
+		return err
+	}
+	for _, id := range videoIDs {
+		if err := requeueOrphanedUpload(ctx, id); err != nil {
+			log.Printf("Failed to re-queue video %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// requeueOrphanedUpload creates the missing job of a video while holding a
+// lock on the video row, so sweeps running in several processes queue it once
+func requeueOrphanedUpload(ctx context.Context, videoID uint) error {
+	var job *models.VideoProcessingJob
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Video{}, videoID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.VideoProcessingJob{}).Where("video_id = ?", videoID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		job = &models.VideoProcessingJob{
+			VideoID: videoID,
+			Status:  models.VideoJobQueued,
+			Stage:   "queued",
+		}
+		return tx.Create(job).Error
+	})
+	if err != nil || job == nil {
+		return err
+	}
+	if err := publishVideoJob(ctx, job.ID); err != nil {
+		database.DB.Delete(job)
+		return err
+	}
+	return nil
+}
+
+// reclaimStaleVideoJobs takes over entries left pending by crashed consumers
+func reclaimStaleVideoJobs(ctx context.Context, consumer string) {
+	messages, _, err := database.RedisClient.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   videoJobStream,
+		Group:    videoJobGroup,
+		Consumer: consumer,
+		MinIdle:  videoJobClaimIdle,
+		Start:    "0-0",
+		Count:    10,
+	}).Result()
+	if err != nil {
+		return
+	}
+	for _, msg := range messages {
+		handleVideoMessage(ctx, consumer, msg)
+	}
+}
+
+// RunVideoWorkers consumes the processing stream with a pool of workers and
+// blocks until ctx is cancelled
+func RunVideoWorkers(ctx context.Context, concurrency int) error {
+	if database.RedisClient == nil {
+		return errors.New("video workers require Redis")
+	}
+	if err := ensureVideoJobGroup(ctx); err != nil {
+		return err
+	}
+
+	host, _ := os.Hostname()
+	prefix := fmt.Sprintf("%s-%d", host, os.Getpid())
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		consumer := fmt.Sprintf("%s-%d", prefix, i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				streams, err := database.RedisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+					Group:    videoJobGroup,
+					Consumer: consumer,
+					Streams:  []string{videoJobStream, ">"},
+					Count:    1,
+					Block:    5 * time.Second,
+				}).Result()
+				if err != nil {
+					if err != redis.Nil && ctx.Err() == nil {
+						log.Printf("Video worker %s read failed: %v", consumer, err)
+						time.Sleep(time.Second)
+					}
+					continue
+				}
+				for _, stream := range streams {
+					for _, msg := range stream.Messages {
+						// A job in flight finishes even when the pool is shutting down
+						handleVideoMessage(context.Background(), consumer, msg)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := promoteVideoRetries(ctx, now); err != nil {
+					log.Printf("Video retry sweep failed: %v", err)
+				}
+				if err := requeueOrphanedUploads(ctx, now); err != nil {
+					log.Printf("Video re-queue sweep failed: %v", err)
+				}
+				reclaimStaleVideoJobs(ctx, prefix+"-reclaim")
+			}
+		}
+	}()
+
+	log.Printf("Video workers started (%d)", concurrency)
+	wg.Wait()
+	return nil
+}
+
+// VideoWorkerConcurrency is the pool size from VIDEO_WORKER_CONCURRENCY
+func VideoWorkerConcurrency() int {
+	n, err := strconv.Atoi(config.AppConfig.VideoWorkerConcurrency)
+	if err != nil || n < 1 {
+		return 2
+	}
+	return n
+}
+
+// StartVideoWorkers runs the worker pool inside the API process unless
+// VIDEO_WORKERS_IN_PROCESS is false (cmd/video-worker runs it instead)
+func StartVideoWorkers() {
+	if config.AppConfig.VideoWorkersInProcess == "false" {
+		return
+	}
+	go func() {
+		if err := RunVideoWorkers(context.Background(), VideoWorkerConcurrency()); err != nil {
+			log.Printf("Video workers stopped: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrVideoNotFound      = errors.New("video not found")
+	ErrVideoNotProcessed  = errors.New("video has not finished processing")
+	ErrVideoTagNotFound   = errors.New("video tag not found")
+	ErrVideoTagOverlap    = errors.New("tag overlaps an existing tag of this player")
+	ErrVideoTagOutOfRange = errors.New("tag range must lie within the video duration")
+	ErrVideoTagNotPlayer  = errors.New("player is not on this video's team")
+)
+
+// videoLengthMs returns the exact length of a processed video in
+// milliseconds. Videos processed before duration_ms was stored fall back to
+// the probed duration in their metadata.
+func videoLengthMs(video *models.Video) (int64, bool) {
+	if video.DurationMs != nil {
+		return *video.DurationMs, true
+	}
+	var meta VideoMetadata
+	if len(video.Metadata) > 0 && json.Unmarshal(video.Metadata, &meta) == nil && meta.Duration > 0 {
+		return int64(math.Round(meta.Duration * 1000)), true
+	}
+	if video.Duration != nil {
+		return int64(*video.Duration) * 1000, true
+	}
+	return 0, false
+}
+
+// roundSeconds matches the DECIMAL(10,2) precision of video_tags times
+func roundSeconds(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// CreateVideoTag records a tagged range for a player and notifies them. A
+// player cannot have two overlapping tags on the same video; ranges that only
+// touch at an endpoint are allowed.
+func CreateVideoTag(ctx context.Context, videoID, createdBy uint, req *models.CreateVideoTagRequest) (*models.VideoTag, error) {
+	tag := &models.VideoTag{
+		VideoID:     videoID,
+		PlayerID:    req.PlayerID,
+		StartTime:   roundSeconds(*req.StartTime),
+		EndTime:     roundSeconds(*req.EndTime),
+		Label:       req.Label,
+		Description: req.Description,
+		CreatedBy:   createdBy,
+	}
+	if tag.EndTime <= tag.StartTime {
+		return nil, ErrVideoTagOutOfRange
+	}
+
+	var video models.Video
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the video serializes concurrent tagging so the overlap check holds
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&video, videoID).Error; err != nil {
+			return ErrVideoNotFound
+		}
+		length, ok := videoLengthMs(&video)
+		if video.ProcessingStatus != models.VideoStatusCompleted || !ok {
+			return ErrVideoNotProcessed
+		}
+		if *req.EndTime*1000 > float64(length) {
+			return ErrVideoTagOutOfRange
+		}
+		// Rounding to hundredths must not carry the end past the last frame
+		if end := float64(length/10) / 100; tag.EndTime > end {
+			tag.EndTime = end
+		}
+		if tag.EndTime <= tag.StartTime {
+			return ErrVideoTagOutOfRange
+		}
+		if !IsTeamPlayer(ctx, tag.PlayerID, video.TeamID) {
+			return ErrVideoTagNotPlayer
+		}
+
+		var overlapping int64
+		if err := tx.Model(&models.VideoTag{}).
+			Where("video_id = ? AND player_id = ? AND start_time < ? AND end_time > ?",
+				videoID, tag.PlayerID, tag.EndTime, tag.StartTime).
+			Count(&overlapping).Error; err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return ErrVideoTagOverlap
+		}
+		return tx.Create(tag).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyVideoTagged(&video, tag)
+	return tag, nil
+}
+
+func notifyVideoTagged(video *models.Video, tag *models.VideoTag) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"video_id":   video.ID,
+		"tag_id":     tag.ID,
+		"start_time": tag.StartTime,
+	})
+	NotificationDispatcher.DispatchAsync(&models.NotificationIntent{
+		UserIDs:   []uint{tag.PlayerID},
+		Type:      models.NotificationTypeVideoTagged,
+		Variables: map[string]string{"video_title": video.Title},
+		Data:      data,
+	})
+}
+
+// ListVideoTags returns the tags of a video in playback order
+func ListVideoTags(ctx context.Context, videoID uint) ([]models.VideoTag, error) {
+	var tags []models.VideoTag
+	err := database.DB.WithContext(ctx).Where("video_id = ?", videoID).
+		Order("start_time, id").Find(&tags).Error
+	return tags, err
+}
+
+// DeleteVideoTag removes a tag
+func DeleteVideoTag(ctx context.Context, tagID uint) error {
+	result := database.DB.WithContext(ctx).Delete(&models.VideoTag{}, tagID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVideoTagNotFound
+	}
+	return nil
+}
+
+// PlayerClipFilter narrows GET /players/{playerId}/videos
+type PlayerClipFilter struct {
+	Label    string
+	SeasonID uint
+	Page     int
+	Limit    int
+}
+
+// ListPlayerClips returns the player's tagged clips across the videos the
+// viewer may watch, newest video first and in playback order within a video
+func ListPlayerClips(ctx context.Context, viewer VideoViewer, playerID uint, filter PlayerClipFilter) ([]models.PlayerClip, int64, error) {
+	scope := func() *gorm.DB {
+		query := database.DB.WithContext(ctx).Table("video_tags").
+			Joins("JOIN videos ON videos.id = video_tags.video_id AND videos.deleted_at IS NULL").
+			Joins("JOIN teams ON teams.id = videos.team_id").
+			Where("video_tags.player_id = ?", playerID)
+		if filter.Label != "" {
+			query = query.Where("video_tags.label = ?", filter.Label)
+		}
+		if filter.SeasonID != 0 {
+			query = query.Where("teams.season_id = ?", filter.SeasonID)
+		}
+		return VisibleVideos(query, viewer)
+	}
+
+	var total int64
+	if err := scope().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var clips []models.PlayerClip
+	err := scope().
+		Select("video_tags.*, videos.title AS video_title, videos.team_id, teams.season_id, videos.event_id, videos.storage_url, videos.thumbnail_url, videos.uploaded_at").
+		Order("videos.uploaded_at DESC, video_tags.video_id DESC, video_tags.start_time").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Scan(&clips).Error
+	return clips, total, err
+}
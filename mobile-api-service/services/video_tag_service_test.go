@@ -0,0 +1,53 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mobile-api-service/models"
+)
+
+func TestVideoLengthMs(t *testing.T) {
+	tests := []struct {
+		name   string
+		video  models.Video
+		want   int64
+		wantOK bool
+	}{
+		{
+			name:   "stored milliseconds",
+			video:  models.Video{Duration: intPtr(12), DurationMs: int64Ptr(12345), Metadata: json.RawMessage(`{"duration":12.4}`)},
+			want:   12345,
+			wantOK: true,
+		},
+		{
+			name:   "probed metadata of older videos",
+			video:  models.Video{Duration: intPtr(12), Metadata: json.RawMessage(`{"format":"mp4","duration":12.3456}`)},
+			want:   12346,
+			wantOK: true,
+		},
+		{
+			name:   "rounded seconds without metadata",
+			video:  models.Video{Duration: intPtr(12)},
+			want:   12000,
+			wantOK: true,
+		},
+		{
+			name:  "not processed",
+			video: models.Video{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := videoLengthMs(&tt.video)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("videoLengthMs() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobile-api-service/config"
+	"mobile-api-service/database"
+	"mobile-api-service/models"
+	"mobile-api-service/storage"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadOffsetMismatch = errors.New("upload offset does not match")
+	ErrUploadTooLarge       = errors.New("upload exceeds the declared length")
+	ErrUploadChunkTooSmall  = errors.New("chunk is smaller than the storage minimum")
+	ErrUploadLocked         = errors.New("upload is locked by another request")
+	ErrUploadFinished       = errors.New("upload is already finished")
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+	ErrChecksumAlgorithm    = errors.New("unsupported checksum algorithm")
+)
+
+// SupportedChecksumAlgorithms is advertised in the Tus-Checksum-Algorithm header
+const SupportedChecksumAlgorithms = "sha1,md5,sha256"
+
+// uploadLockTTL bounds how long a crashed request can keep an upload locked
+const uploadLockTTL = 10 * time.Minute
+
+// CreateVideoUploadInput is the metadata sent when an upload is created
+type CreateVideoUploadInput struct {
+	TeamID      uint
+	EventID     *uint
+	Title       string
+	Description string
+	FileName    string
+	FileType    string
+	Length      int64
+	SHA256      string
+	UploadedBy  uint
+}
+
+// MaxVideoUploadBytes is the largest upload accepted, from VIDEO_MAX_UPLOAD_BYTES
+func MaxVideoUploadBytes() int64 {
+	limit, err := strconv.ParseInt(config.AppConfig.VideoMaxUploadBytes, 10, 64)
+	if err != nil || limit <= 0 {
+		return 4 << 30
+	}
+	return limit
+}
+
+func randomKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// CreateVideoUpload creates the videos row (pending) and its resumable upload
+func CreateVideoUpload(ctx context.Context, input *CreateVideoUploadInput) (*models.Video, *models.VideoUpload, error) {
+	if input.Length <= 0 || input.Length > MaxVideoUploadBytes() {
+		return nil, nil, ErrUploadTooLarge
+	}
+	if input.SHA256 != "" {
+		if raw, err := hex.DecodeString(input.SHA256); err != nil || len(raw) != sha256.Size {
+			return nil, nil, fmt.Errorf("invalid sha256 checksum")
+		}
+	}
+
+	uploadKey, err := randomKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	initialHash, err := sha256.New().(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	video := &models.Video{
+		TeamID:           input.TeamID,
+		EventID:          input.EventID,
+		Title:            input.Title,
+		Description:      input.Description,
+		FileSize:         input.Length,
+		FileType:         input.FileType,
+		ProcessingStatus: models.VideoStatusPending,
+		UploadedBy:       input.UploadedBy,
+		UploadedAt:       time.Now(),
+	}
+	upload := &models.VideoUpload{
+		UploadKey:      uploadKey,
+		UploadLength:   input.Length,
+		ExpectedSHA256: strings.ToLower(input.SHA256),
+		HashState:      initialHash,
+		CreatedBy:      input.UploadedBy,
+	}
+
+	err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(video).Error; err != nil {
+			return err
+		}
+		upload.VideoID = video.ID
+		upload.ObjectKey = fmt.Sprintf("videos/%d/%d/%s%s", video.TeamID, video.ID, uploadKey, path.Ext(input.FileName))
+		if err := tx.Create(upload).Error; err != nil {
+			return err
+		}
+		// New videos start out visible to their team; coaches narrow or widen it later
+		return tx.Create(&models.VideoPermission{
+			VideoID:        video.ID,
+			PermissionType: models.VideoPermissionTeam,
+			GrantedBy:      input.UploadedBy,
+		}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return video, upload, nil
+}
+
+// GetVideoUpload loads the upload of a video
+func GetVideoUpload(ctx context.Context, videoID uint) (*models.VideoUpload, error) {
+	var upload models.VideoUpload
+	err := database.DB.WithContext(ctx).Where("video_id = ?", videoID).First(&upload).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUploadNotFound
+	}
+	return &upload, err
+}
+
+// releaseUploadLock deletes the lock only while it still holds the caller's
+// token, so a request that outlived uploadLockTTL cannot free a lock that
+// another request has taken since
+var releaseUploadLock = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func lockUpload(ctx context.Context, videoID uint) (func(), error) {
+	if database.RedisClient == nil {
+		return func() {}, nil
+	}
+	token, err := randomKey()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("videos:upload-lock:%d", videoID)
+	ok, err := database.RedisClient.SetNX(ctx, key, token, uploadLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUploadLocked
+	}
+	return func() {
+		releaseUploadLock.Run(context.Background(), database.RedisClient, []string{key}, token)
+	}, nil
+}
+
+// parseChunkChecksum parses a tus Upload-Checksum header ("<algorithm> <base64 digest>")
+func parseChunkChecksum(header string) (hash.Hash, []byte, error) {
+	if header == "" {
+		return nil, nil, nil
+	}
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) != 2 {
+		return nil, nil, ErrChecksumAlgorithm
+	}
+	digest, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, ErrChecksumMismatch
+	}
+	switch strings.ToLower(parts[0]) {
+	case "sha1":
+		return sha1.New(), digest, nil
+	case "md5":
+		return md5.New(), digest, nil
+	case "sha256":
+		return sha256.New(), digest, nil
+	}
+	return nil, nil, ErrChecksumAlgorithm
+}
+
+// WriteVideoChunk appends one PATCH body at offset. The chunk is buffered and
+// verified against checksumHeader before it reaches storage, so a corrupted
+// chunk leaves the upload offset untouched and can simply be resent. When the
+// last byte arrives the upload is assembled and its whole-file SHA-256 checked.
+func WriteVideoChunk(ctx context.Context, videoID uint, offset int64, body io.Reader, checksumHeader string) (*models.VideoUpload, error) {
+	unlock, err := lockUpload(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	upload, err := GetVideoUpload(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.CompletedAt != nil {
+		return upload, ErrUploadFinished
+	}
+	if offset != upload.UploadOffset {
+		return upload, ErrUploadOffsetMismatch
+	}
+
+	chunkHash, expectedDigest, err := parseChunkChecksum(checksumHeader)
+	if err != nil {
+		return upload, err
+	}
+
+	fileHash := sha256.New()
+	if err := fileHash.(encoding.BinaryUnmarshaler).UnmarshalBinary(upload.HashState); err != nil {
+		return upload, err
+	}
+
+	tmp, err := os.CreateTemp("", "video-chunk-*")
+	if err != nil {
+		return upload, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	writers := []io.Writer{tmp, fileHash}
+	if chunkHash != nil {
+		writers = append(writers, chunkHash)
+	}
+	remaining := upload.UploadLength - upload.UploadOffset
+	size, err := io.Copy(io.MultiWriter(writers...), io.LimitReader(body, remaining+1))
+	if err != nil {
+		return upload, err
+	}
+	if size > remaining {
+		return upload, ErrUploadTooLarge
+	}
+	if size == 0 {
+		return upload, nil
+	}
+	if chunkHash != nil && !bytes.Equal(chunkHash.Sum(nil), expectedDigest) {
+		return upload, ErrChecksumMismatch
+	}
+
+	final := upload.UploadOffset+size == upload.UploadLength
+	if !final && size < storage.Default.MinChunkSize() {
+		return upload, ErrUploadChunkTooSmall
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return upload, err
+	}
+	if err := storage.Default.WriteChunk(ctx, upload.UploadKey, upload.UploadOffset, tmp, size); err != nil {
+		return upload, err
+	}
+
+	hashState, err := fileHash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return upload, err
+	}
+
+	upload.UploadOffset += size
+	upload.HashState = hashState
+	if err := database.DB.WithContext(ctx).Model(upload).Updates(map[string]interface{}{
+		"upload_offset": upload.UploadOffset,
+		"hash_state":    upload.HashState,
+	}).Error; err != nil {
+		return upload, err
+	}
+
+	if final {
+		return upload, finishVideoUpload(ctx, upload, hex.EncodeToString(fileHash.Sum(nil)))
+	}
+	return upload, nil
+}
+
+// finishVideoUpload assembles the chunks and queues the video for processing,
+// or marks it failed when the file does not match the checksum declared at creation
+func finishVideoUpload(ctx context.Context, upload *models.VideoUpload, sum string) error {
+	now := time.Now()
+	upload.CompletedAt = &now
+	database.DB.WithContext(ctx).Model(upload).Update("completed_at", now)
+
+	if upload.ExpectedSHA256 != "" && upload.ExpectedSHA256 != sum {
+		storage.Default.AbortUpload(ctx, upload.UploadKey)
+		database.DB.WithContext(ctx).Model(&models.Video{}).Where("id = ?", upload.VideoID).
+			Update("processing_status", models.VideoStatusFailed)
+		return ErrChecksumMismatch
+	}
+
+	if err := storage.Default.CompleteUpload(ctx, upload.UploadKey, upload.ObjectKey); err != nil {
+		database.DB.WithContext(ctx).Model(&models.Video{}).Where("id = ?", upload.VideoID).
+			Update("processing_status", models.VideoStatusFailed)
+		return err
+	}
+
+	err := database.DB.WithContext(ctx).Model(&models.Video{}).Where("id = ?", upload.VideoID).
+		Updates(map[string]interface{}{
+			"storage_url": storage.Default.URL(upload.ObjectKey),
+			"file_size":   upload.UploadLength,
+			"uploaded_at": now,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	// The upload itself succeeded; a failure to queue is retried by the re-queue sweep
+	if _, err := EnqueueVideoProcessing(ctx, upload.VideoID); err != nil {
+		log.Printf("Failed to queue processing for video %d: %v", upload.VideoID, err)
+	}
+	return nil
+}
+
+// AbortVideoUpload discards an unfinished upload and its video
+func AbortVideoUpload(ctx context.Context, videoID uint) error {
+	unlock, err := lockUpload(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	upload, err := GetVideoUpload(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	if upload.CompletedAt != nil {
+		return ErrUploadFinished
+	}
+
+	if err := storage.Default.AbortUpload(ctx, upload.UploadKey); err != nil {
+		return err
+	}
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(upload).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Video{}, videoID).Error
+	})
+}
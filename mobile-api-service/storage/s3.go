@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3MinPartSize is the S3 minimum size of every multipart source except the last
+const s3MinPartSize = 5 << 20
+
+// S3Backend stores objects in any S3-compatible service. Each upload chunk is
+// stored as its own object and the chunks are composed server side on completion.
+type S3Backend struct {
+	client        *minio.Client
+	bucket        string
+	publicBaseURL string
+}
+
+func NewS3Backend(endpoint, accessKey, secretKey, bucket string, useSSL bool, publicBaseURL string) (*S3Backend, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(context.Background(), bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("bucket %q does not exist", bucket)
+	}
+
+	return &S3Backend{client: client, bucket: bucket, publicBaseURL: strings.TrimRight(publicBaseURL, "/")}, nil
+}
+
+func chunkPrefix(uploadKey string) string {
+	return "uploads/" + uploadKey + "/"
+}
+
+func (b *S3Backend) WriteChunk(ctx context.Context, uploadKey string, offset int64, data io.Reader, size int64) error {
+	// Zero-padded offsets keep lexical and numeric order identical
+	key := fmt.Sprintf("%s%020d", chunkPrefix(uploadKey), offset)
+	_, err := b.client.PutObject(ctx, b.bucket, key, data, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	return err
+}
+
+func (b *S3Backend) chunkKeys(ctx context.Context, uploadKey string) ([]string, error) {
+	var keys []string
+	for object := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{Prefix: chunkPrefix(uploadKey), Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		keys = append(keys, object.Key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (b *S3Backend) CompleteUpload(ctx context.Context, uploadKey, objectKey string) error {
+	keys, err := b.chunkKeys(ctx, uploadKey)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return ErrNotFound
+	}
+
+	dst := minio.CopyDestOptions{Bucket: b.bucket, Object: objectKey}
+	if len(keys) == 1 {
+		_, err = b.client.CopyObject(ctx, dst, minio.CopySrcOptions{Bucket: b.bucket, Object: keys[0]})
+	} else {
+		sources := make([]minio.CopySrcOptions, 0, len(keys))
+		for _, key := range keys {
+			sources = append(sources, minio.CopySrcOptions{Bucket: b.bucket, Object: key})
+		}
+		_, err = b.client.ComposeObject(ctx, dst, sources...)
+	}
+	if err != nil {
+		return err
+	}
+
+	return b.AbortUpload(ctx, uploadKey)
+}
+
+func (b *S3Backend) AbortUpload(ctx context.Context, uploadKey string) error {
+	keys, err := b.chunkKeys(ctx, uploadKey)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := b.client.RemoveObject(ctx, b.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *S3Backend) MinChunkSize() int64 {
+	return s3MinPartSize
+}
+
+func (b *S3Backend) Put(ctx context.Context, objectKey string, data io.Reader, size int64, contentType string) error {
+	_, err := b.client.PutObject(ctx, b.bucket, objectKey, data, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (b *S3Backend) Open(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	if _, err := b.client.StatObject(ctx, b.bucket, objectKey, minio.StatObjectOptions{}); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return b.client.GetObject(ctx, b.bucket, objectKey, minio.GetObjectOptions{})
+}
+
+func (b *S3Backend) Delete(ctx context.Context, objectKey string) error {
+	return b.client.RemoveObject(ctx, b.bucket, objectKey, minio.RemoveObjectOptions{})
+}
+
+func (b *S3Backend) URL(objectKey string) string {
+	return b.publicBaseURL + "/" + strings.TrimLeft(objectKey, "/")
+}
+
+func (b *S3Backend) ObjectKey(url string) (string, bool) {
+	return trimBaseURL(b.publicBaseURL, url)
+}
+
+// SignedURL presigns a GET request for the object
+func (b *S3Backend) SignedURL(ctx context.Context, objectKey string, ttl time.Duration) (string, error) {
+	u, err := b.client.PresignedGetObject(ctx, b.bucket, objectKey, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
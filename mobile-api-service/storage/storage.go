@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"mobile-api-service/config"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Backend stores media objects and the in-progress chunks of resumable uploads
+type Backend interface {
+	// WriteChunk stores data as the part of upload uploadKey that starts at offset
+	WriteChunk(ctx context.Context, uploadKey string, offset int64, data io.Reader, size int64) error
+	// CompleteUpload assembles every chunk of uploadKey, in offset order, into objectKey
+	CompleteUpload(ctx context.Context, uploadKey, objectKey string) error
+	// AbortUpload discards the chunks of an unfinished upload
+	AbortUpload(ctx context.Context, uploadKey string) error
+	// MinChunkSize is the smallest accepted chunk other than the last one
+	MinChunkSize() int64
+
+	Put(ctx context.Context, objectKey string, data io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, objectKey string) (io.ReadCloser, error)
+	Delete(ctx context.Context, objectKey string) error
+	// URL returns the address an object is served from
+	URL(objectKey string) string
+	// ObjectKey reverses URL, reporting false for addresses of another backend
+	ObjectKey(url string) (string, bool)
+	// SignedURL returns an address for objectKey that stops working after ttl
+	SignedURL(ctx context.Context, objectKey string, ttl time.Duration) (string, error)
+}
+
+var Default Backend
+
+// trimBaseURL strips the public base URL from an object address
+func trimBaseURL(baseURL, url string) (string, bool) {
+	if !strings.HasPrefix(url, baseURL+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(url, baseURL+"/"), true
+}
+
+// Init selects the backend configured by STORAGE_BACKEND
+func Init() {
+	switch strings.ToLower(config.AppConfig.StorageBackend) {
+	case "s3":
+		backend, err := NewS3Backend(
+			config.AppConfig.S3Endpoint,
+			config.AppConfig.S3AccessKey,
+			config.AppConfig.S3SecretKey,
+			config.AppConfig.S3Bucket,
+			config.AppConfig.S3UseSSL != "false",
+			config.AppConfig.StoragePublicBaseURL,
+		)
+		if err != nil {
+			log.Fatal("Failed to initialize S3 storage:", err)
+		}
+		Default = backend
+	default:
+		secret := config.AppConfig.MediaSigningSecret
+		if secret == "" {
+			secret = config.AppConfig.JWTSecret
+		}
+		backend, err := NewLocalBackend(config.AppConfig.StorageLocalDir, config.AppConfig.StoragePublicBaseURL, secret)
+		if err != nil {
+			log.Fatal("Failed to initialize local storage:", err)
+		}
+		Default = backend
+	}
+
+	log.Printf("Media storage initialized (%s)", config.AppConfig.StorageBackend)
+}